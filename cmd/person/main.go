@@ -1,7 +1,21 @@
 package main
 
-import "github.com/alukart32/effective-mobile-test-task/internal/person"
+import (
+	"os"
+
+	"github.com/alukart32/effective-mobile-test-task/internal/person"
+)
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "import":
+			person.Import(os.Args[2:])
+			return
+		case "migrate":
+			person.Migrate(os.Args[2:])
+			return
+		}
+	}
 	person.Run()
 }
@@ -0,0 +1,71 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alukart32/effective-mobile-test-task/internal/person/model"
+	kafka "github.com/segmentio/kafka-go"
+)
+
+const (
+	// _eventTypeHeader is the Kafka message header of the person event type.
+	_eventTypeHeader = "event-type"
+	// _tenantHeader is the Kafka message header of the person tenant.
+	_tenantHeader = "tenant-id"
+)
+
+// personEvents publishes the person domain events to Kafka.
+// The events are keyed by the person ID, so the events of the same person
+// go to the same partition in the publishing order.
+type personEvents struct {
+	writer *kafka.Writer
+}
+
+func PersonEvents(brokers []string, topic string, batchSize int) (*personEvents, error) {
+	if len(brokers) == 0 {
+		return nil, fmt.Errorf("empty brokers list")
+	}
+	if len(topic) == 0 {
+		return nil, fmt.Errorf("empty events topic")
+	}
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	return &personEvents{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(brokers...),
+			Topic:        topic,
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+			BatchSize:    batchSize,
+		},
+	}, nil
+}
+
+// Publish writes the events synchronously, the error means some of them
+// may be not written and the whole batch must be published again.
+func (e *personEvents) Publish(ctx context.Context, msgs []model.OutboxMessage) error {
+	kafkaMsgs := make([]kafka.Message, len(msgs))
+	for i, m := range msgs {
+		kafkaMsgs[i] = kafka.Message{
+			Key:   []byte(m.Key),
+			Value: m.Payload,
+			Headers: []kafka.Header{
+				{Key: _eventTypeHeader, Value: []byte(m.Type)},
+				{Key: _tenantHeader, Value: []byte(m.Tenant)},
+			},
+			Time: m.CreatedAt,
+		}
+	}
+
+	if err := e.writer.WriteMessages(ctx, kafkaMsgs...); err != nil {
+		return fmt.Errorf("PersonEvents.Publish: %w", err)
+	}
+	return nil
+}
+
+func (e *personEvents) Close() error {
+	return e.writer.Close()
+}
@@ -0,0 +1,96 @@
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/alukart32/effective-mobile-test-task/internal/person/model"
+)
+
+// The webhook request headers.
+const (
+	_webhookEventHeader     = "X-Webhook-Event"
+	_webhookDeliveryHeader  = "X-Webhook-Delivery"
+	_webhookTimestampHeader = "X-Webhook-Timestamp"
+	// _webhookSignatureHeader is "sha256=<hex HMAC of timestamp.payload>".
+	_webhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// webhookSender posts the signed person events to the webhooks.
+type webhookSender struct {
+	client *http.Client
+}
+
+func WebhookSender(timeout time.Duration) (*webhookSender, error) {
+	if timeout <= 0 {
+		return nil, fmt.Errorf("non positive webhook timeout")
+	}
+	// The resolved address is checked on dial, so the host resolved to the
+	// internal address after the registration isn't reached. The proxy would
+	// dial instead, it isn't used.
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			addr, err := netip.ParseAddr(host)
+			if err != nil || !model.IsPublicAddr(addr) {
+				return fmt.Errorf("webhook address %s isn't public", address)
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &webhookSender{
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   timeout,
+			// The redirects aren't followed, the signed payload goes only to the registered URL.
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}, nil
+}
+
+// Send posts the event payload to the webhook, any non 2xx response is an error.
+func (s *webhookSender) Send(ctx context.Context, d model.WebhookDispatch) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.Webhook.URL,
+		bytes.NewReader(d.Delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("WebhookSender.Send: %w", err)
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(_webhookEventHeader, string(d.Delivery.EventType))
+	req.Header.Set(_webhookDeliveryHeader, strconv.FormatInt(d.Delivery.Id, 10))
+	req.Header.Set(_webhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(_webhookSignatureHeader,
+		"sha256="+model.SignWebhookPayload(d.Webhook.Secret, timestamp, d.Delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("WebhookSender.Send: %w", err)
+	}
+	defer resp.Body.Close()
+	// Drain the body to reuse the connection.
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("WebhookSender.Send: unexpected status: %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
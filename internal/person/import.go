@@ -0,0 +1,228 @@
+package person
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"unicode/utf8"
+
+	"github.com/alukart32/effective-mobile-test-task/internal/person/adapters"
+	"github.com/alukart32/effective-mobile-test-task/internal/person/model"
+	"github.com/alukart32/effective-mobile-test-task/internal/person/service/fioimport"
+	"github.com/alukart32/effective-mobile-test-task/internal/person/service/persondata"
+	"github.com/alukart32/effective-mobile-test-task/internal/person/storage"
+	"github.com/alukart32/effective-mobile-test-task/internal/person/storage/persons"
+	"github.com/alukart32/effective-mobile-test-task/internal/pkg/postgres"
+	"github.com/alukart32/effective-mobile-test-task/internal/pkg/reqmeta"
+	"github.com/alukart32/effective-mobile-test-task/internal/pkg/sqlite"
+	"github.com/alukart32/effective-mobile-test-task/internal/pkg/zerologx"
+	"github.com/caarlos0/env/v8"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/xid"
+)
+
+type importConfig struct {
+	API      apiConfig
+	Storage  storageConfig
+	Postgres postgresConfig
+	Redis    redisConfig
+	Tenant   tenantConfig
+}
+
+// Import runs the import subcommand:
+//
+//	person import [flags] FILE
+//
+// FILE is a CSV or NDJSON file of FIOs, "-" reads stdin. The rejected rows
+// are written to the report file. The persons are imported to the -tenant
+// tenant, the default one if it isn't set. The changes are recorded with the
+// "import" source and the -actor actor, the OS user by default.
+func Import(args []string) {
+	logger := zerologx.Get()
+
+	var osUser string
+	if u, err := user.Current(); err == nil {
+		osUser = u.Username
+	}
+
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	var (
+		format  = flags.String("format", "", "file format: csv or ndjson (default by the file extension, csv for stdin)")
+		header  = flags.Bool("header", false, "the first CSV record is a header")
+		columns = flags.String("columns", "", `CSV column mapping, e.g. "name=0,surname=1,patronymic=2" or "name=first_name"`)
+		comma   = flags.String("comma", ",", "CSV field delimiter")
+		report  = flags.String("report", "import-report.csv", `rejected rows report file, "-" writes stdout`)
+		chunk   = flags.Int("chunk", 0, "number of FIOs created at once")
+		tenant  = flags.String("tenant", "", "tenant of the imported persons (default \"default\")")
+		actor   = flags.String("actor", osUser, "actor of the recorded changes")
+	)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: person import [flags] FILE")
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	path := flags.Arg(0)
+
+	if len(*format) == 0 {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".ndjson", ".jsonl":
+			*format = string(fioimport.NDJSON)
+		default:
+			*format = string(fioimport.CSV)
+		}
+	}
+	importFormat, err := fioimport.ParseFormat(*format)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("parse import flags")
+	}
+	importTenant, err := model.ParseTenant(*tenant)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("parse import flags")
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	correlationID := xid.New().String()
+	ctx = reqmeta.WithTenant(ctx, importTenant)
+	ctx = reqmeta.WithCorrelationID(reqmeta.WithSource(ctx, "import"), correlationID)
+	if len(*actor) != 0 {
+		ctx = reqmeta.WithActor(ctx, *actor)
+	}
+
+	var cfg importConfig
+	err = env.ParseWithOptions(&cfg, env.Options{RequiredIfNoDef: true})
+	if err != nil {
+		logger.Fatal().Err(err).Msg("parse env params")
+	}
+
+	var in io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			logger.Fatal().Err(err).Msg("open import file")
+		}
+		defer f.Close()
+		in = f
+	}
+
+	var r fioimport.Reader
+	switch importFormat {
+	case fioimport.NDJSON:
+		r = fioimport.NDJSONReader(in)
+	case fioimport.CSV:
+		opts := fioimport.CSVOptions{Header: *header}
+		opts.Comma, _ = utf8.DecodeRuneInString(*comma)
+		opts.Columns, err = fioimport.ParseColumnMapping(*columns)
+		if err != nil {
+			logger.Fatal().Err(err).Msg("parse import flags")
+		}
+		r, err = fioimport.CSVReader(in, opts)
+		if err != nil {
+			logger.Fatal().Err(err).Msg("prepare CSV reader")
+		}
+	}
+
+	var out io.Writer = os.Stdout
+	if *report != "-" {
+		f, err := os.Create(*report)
+		if err != nil {
+			logger.Fatal().Err(err).Msg("create import report")
+		}
+		defer f.Close()
+		out = f
+	}
+
+	backend, err := storage.ParseBackend(cfg.Storage.Backend)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("parse env params")
+	}
+	storageCfg := storage.Config{
+		Backend: backend,
+		Cache:   cfg.Redis.cacheConfig(),
+		Quotas:  cfg.Tenant.quotas(),
+	}
+	if backend.UsesRedis() {
+		redisOpt, err := redis.ParseURL(cfg.Redis.URL)
+		if err != nil {
+			logger.Fatal().Err(err).Msg("prepare redis client")
+		}
+		cache := redis.NewClient(redisOpt)
+		defer cache.Close()
+		storageCfg.Redis = cache
+	}
+	if backend.UsesPostgres() {
+		if cfg.Postgres.Migrate {
+			if err = migratePostgres(ctx, cfg.Postgres.URL); err != nil {
+				logger.Fatal().Err(err).Msg("migrate postgres db")
+			}
+		}
+		postgresPool, err := postgres.New(ctx, cfg.Postgres.URL, cfg.Postgres.poolOptions())
+		if err != nil {
+			logger.Fatal().Err(err).Msg("prepare postgres pool")
+		}
+		defer postgresPool.Close()
+		storageCfg.Postgres = postgresPool
+	}
+	if backend.UsesSQLite() {
+		sqliteDB, err := sqlite.Open(cfg.Storage.SQLitePath)
+		if err != nil {
+			logger.Fatal().Err(err).Msg("prepare sqlite db")
+		}
+		defer sqliteDB.Close()
+		err = sqlite.Migrate(sqliteDB, persons.SQLiteMigrations, persons.SQLiteMigrationsDir)
+		if err != nil {
+			logger.Fatal().Err(err).Msg("migrate sqlite db")
+		}
+		storageCfg.SQLite = sqliteDB
+	}
+
+	repo, err := storage.Persons(storageCfg)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("prepare persons storage")
+	}
+	personMetaDataProvider, err := adapters.PersonMetaData(
+		cfg.API.AgifyService,
+		cfg.API.GenderizeService,
+		cfg.API.Nationalize,
+	)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("prepare person metadata provider adapter")
+	}
+	personManager, err := persondata.Manager(repo, personMetaDataProvider)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("prepare person manager")
+	}
+	importer, err := fioimport.Importer(personManager, *chunk)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("prepare FIO importer")
+	}
+
+	logger.Info().
+		Str("file", path).
+		Str("format", string(importFormat)).
+		Str("tenant", importTenant).
+		Str("actor", *actor).
+		Str("correlation_id", correlationID).
+		Msg(">> import persons")
+	summary, err := importer.Import(ctx, r, out)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("import persons")
+	}
+	logger.Info().
+		Int("total", summary.Total).
+		Int("imported", summary.Imported).
+		Int("rejected", summary.Rejected).
+		Str("report", *report).
+		Msg("<< import persons")
+}
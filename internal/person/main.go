@@ -2,49 +2,221 @@ package person
 
 import (
 	"context"
+	"errors"
+	"expvar"
+	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
 	"github.com/alukart32/effective-mobile-test-task/internal/person/adapters"
+	"github.com/alukart32/effective-mobile-test-task/internal/person/model"
 	"github.com/alukart32/effective-mobile-test-task/internal/person/ports"
+	"github.com/alukart32/effective-mobile-test-task/internal/person/service/outbox"
 	"github.com/alukart32/effective-mobile-test-task/internal/person/service/persondata"
+	"github.com/alukart32/effective-mobile-test-task/internal/person/service/webhook"
+	"github.com/alukart32/effective-mobile-test-task/internal/person/storage"
 	"github.com/alukart32/effective-mobile-test-task/internal/person/storage/persons"
+	"github.com/alukart32/effective-mobile-test-task/internal/person/storage/webhooks"
 	"github.com/alukart32/effective-mobile-test-task/internal/pkg/ginx"
 	"github.com/alukart32/effective-mobile-test-task/internal/pkg/postgres"
 	"github.com/alukart32/effective-mobile-test-task/internal/pkg/server"
+	"github.com/alukart32/effective-mobile-test-task/internal/pkg/sqlite"
 	"github.com/alukart32/effective-mobile-test-task/internal/pkg/zerologx"
 	"github.com/caarlos0/env/v8"
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
 )
 
 type config struct {
-	API struct {
-		AgifyService     string `env:"AGIFY_SERVICE_API,notEmpty"`
-		GenderizeService string `env:"GENDERIZE_SERVICE_API,notEmpty"`
-		Nationalize      string `env:"NATIONALIZE_SERVICE_API,notEmpty"`
+	API      apiConfig
+	GraphQL  graphQLConfig
+	Kafka    kafkaConfig
+	Storage  storageConfig
+	Postgres postgresConfig
+	Redis    redisConfig
+	Persons  personsConfig
+	Tenant   tenantConfig
+	Outbox   outboxConfig
+	Webhook  webhookConfig
+}
+
+type apiConfig struct {
+	AgifyService     string `env:"AGIFY_SERVICE_API,notEmpty"`
+	GenderizeService string `env:"GENDERIZE_SERVICE_API,notEmpty"`
+	Nationalize      string `env:"NATIONALIZE_SERVICE_API,notEmpty"`
+}
+
+type graphQLConfig struct {
+	Path string `env:"GRAPHQL_PATH,notEmpty"`
+}
+
+type kafkaConfig struct {
+	// Enabled is false when the FIO messages aren't read and the person
+	// events aren't published, the topics and brokers aren't required then.
+	Enabled   bool     `env:"KAFKA_ENABLED" envDefault:"true"`
+	ReadTopic string   `env:"KAFKA_READ_TOPIC" envDefault:""`
+	ErrTopic  string   `env:"KAFKA_ERROR_TOPIC" envDefault:""`
+	Brokers   []string `env:"KAFKA_BROKERS" envDefault:""`
+	// ReadLimit is the max number of the fetched FIO messages not handled yet.
+	ReadLimit int `env:"KAFKA_READ_LIMIT" envDefault:"64"`
+	// Workers is the number of the FIO messages handled concurrently.
+	Workers int `env:"KAFKA_WORKERS" envDefault:"4"`
+	// Order is the order of handling the FIO messages: partition, key or none.
+	Order string `env:"KAFKA_ORDER" envDefault:"partition"`
+	// GroupID is the consumer group of the FIO messages, the replicas share
+	// the partitions of the read topic and resume from the committed offsets.
+	// The messages are read from the beginning on every start without it.
+	GroupID string `env:"KAFKA_GROUP_ID" envDefault:"person"`
+	// EventsTopic is the topic of the person domain events.
+	EventsTopic string `env:"KAFKA_EVENTS_TOPIC" envDefault:"person.events"`
+}
+
+func (c kafkaConfig) validate() error {
+	if !c.Enabled {
+		return nil
 	}
-	GraphQL struct {
-		Path string `env:"GRAPHQL_PATH,notEmpty"`
+	switch {
+	case len(c.Brokers) == 0:
+		return errors.New("KAFKA_BROKERS is required")
+	case len(c.ReadTopic) == 0:
+		return errors.New("KAFKA_READ_TOPIC is required")
+	case len(c.ErrTopic) == 0:
+		return errors.New("KAFKA_ERROR_TOPIC is required")
+	case c.Order != ports.FIOOrderPartition && c.Order != ports.FIOOrderKey && c.Order != ports.FIOOrderNone:
+		return fmt.Errorf("KAFKA_ORDER: unknown order %q", c.Order)
 	}
-	Kafka struct {
-		ReadTopic string   `env:"KAFKA_READ_TOPIC,notEmpty"`
-		ErrTopic  string   `env:"KAFKA_ERROR_TOPIC,notEmpty"`
-		Brokers   []string `env:"KAFKA_BROKERS,notEmpty"`
-		ReadLimit int      `env:"KAFKA_READ_LIMIT" envDefault:"1"`
+	return nil
+}
+
+type storageConfig struct {
+	// Backend is the persons storage backend: memory, sqlite, postgres or postgres+redis.
+	Backend string `env:"STORAGE_BACKEND" envDefault:"postgres+redis"`
+	// SQLitePath is the db file of the sqlite backend.
+	SQLitePath string `env:"SQLITE_PATH" envDefault:"persons.db"`
+}
+
+type postgresConfig struct {
+	// URL is required by the postgres backends, the outbox and the webhooks.
+	URL string `env:"POSTGRES_URL" envDefault:""`
+	// Migrate applies the embedded migrations at startup.
+	Migrate bool `env:"POSTGRES_MIGRATE" envDefault:"false"`
+	// ReplicaURLs are the optional read replicas.
+	ReplicaURLs []string `env:"POSTGRES_REPLICA_URLS" envDefault:""`
+	// ReplicaCheckInterval is how often the replicas health and lag are checked.
+	ReplicaCheckInterval time.Duration `env:"POSTGRES_REPLICA_CHECK_INTERVAL" envDefault:"1s"`
+
+	MinConns         int32         `env:"DATABASE_MIN_CONNS" envDefault:"0"`
+	MaxConns         int32         `env:"DATABASE_MAX_CONNS" envDefault:"5"`
+	MaxConnIdleTime  time.Duration `env:"DATABASE_MAX_CONN_IDLE_TIME" envDefault:"30m"`
+	MaxConnLifetime  time.Duration `env:"DATABASE_MAX_CONN_LIFETIME" envDefault:"1h"`
+	StatementTimeout time.Duration `env:"DATABASE_STATEMENT_TIMEOUT" envDefault:"0"`
+	ApplicationName  string        `env:"DATABASE_APPLICATION_NAME" envDefault:"person"`
+	PingTimeout      time.Duration `env:"PING_TIMEOUT" envDefault:"1m"`
+	// SlowQuery is the duration the queries are logged after, zero disables it.
+	SlowQuery time.Duration `env:"DATABASE_SLOW_QUERY" envDefault:"0"`
+	// Explain logs the persons queries plans at the debug log level.
+	Explain bool `env:"DATABASE_EXPLAIN" envDefault:"false"`
+}
+
+// poolOptions returns the options of the primary pool. The replicas pools
+// aren't pinged, they're checked by the storage.
+func (c postgresConfig) poolOptions() postgres.Options {
+	opts := postgres.Options{
+		MinConns:         c.MinConns,
+		MaxConns:         c.MaxConns,
+		MaxConnIdleTime:  c.MaxConnIdleTime,
+		MaxConnLifetime:  c.MaxConnLifetime,
+		StatementTimeout: c.StatementTimeout,
+		ApplicationName:  c.ApplicationName,
+		PingTimeout:      c.PingTimeout,
 	}
-	Postgres struct {
-		URL string `env:"POSTGRES_URL,notEmpty"`
+	if c.SlowQuery > 0 {
+		opts.Tracer = postgres.SlowQueryTracer(c.SlowQuery)
 	}
-	Redis struct {
-		URL         string        `env:"REDIS_ADDRESS,notEmpty" envDefault:"127.0.0.1:6379"`
-		DialTimeout time.Duration `env:"REDIS_DEAL_TIMEOUT" envDefault:"100ms"`
-		ReadTimeout time.Duration `env:"REDIS_READ_TIMEOUT" envDefault:"100ms"`
+	return opts
+}
+
+func (c postgresConfig) replicaPoolOptions() postgres.Options {
+	opts := c.poolOptions()
+	opts.PingTimeout = 0
+	return opts
+}
+
+type redisConfig struct {
+	URL         string        `env:"REDIS_ADDRESS,notEmpty" envDefault:"127.0.0.1:6379"`
+	DialTimeout time.Duration `env:"REDIS_DEAL_TIMEOUT" envDefault:"100ms"`
+	ReadTimeout time.Duration `env:"REDIS_READ_TIMEOUT" envDefault:"100ms"`
+	CacheTTL    time.Duration `env:"REDIS_CACHE_TTL" envDefault:"10m"`
+	// CacheJitter is the max random addition to the cache TTL.
+	CacheJitter time.Duration `env:"REDIS_CACHE_JITTER" envDefault:"1m"`
+	// NegativeTTL is the expiration of the cached not found persons, zero disables it.
+	NegativeTTL time.Duration `env:"REDIS_NEGATIVE_TTL" envDefault:"30s"`
+	// ResultTTL is the expiration of the cached collections and stats, zero disables it.
+	ResultTTL time.Duration `env:"REDIS_RESULT_TTL" envDefault:"5s"`
+	// LocalCacheSize limits the persons cached in process, zero disables it.
+	LocalCacheSize int           `env:"LOCAL_CACHE_SIZE" envDefault:"10000"`
+	LocalCacheTTL  time.Duration `env:"LOCAL_CACHE_TTL" envDefault:"5s"`
+	// ReplicaReads routes the cache misses to the postgres replicas.
+	ReplicaReads bool `env:"REDIS_CACHE_REPLICA_READS" envDefault:"false"`
+}
+
+func (c redisConfig) cacheConfig() persons.CacheConfig {
+	return persons.CacheConfig{
+		TTL:          c.CacheTTL,
+		Jitter:       c.CacheJitter,
+		NegativeTTL:  c.NegativeTTL,
+		ResultTTL:    c.ResultTTL,
+		LocalSize:    c.LocalCacheSize,
+		LocalTTL:     c.LocalCacheTTL,
+		ReplicaReads: c.ReplicaReads,
 	}
 }
 
+type personsConfig struct {
+	// Retention is how long the soft deleted persons are kept, zero disables the purge.
+	Retention     time.Duration `env:"PERSONS_RETENTION" envDefault:"720h"`
+	PurgeInterval time.Duration `env:"PERSONS_PURGE_INTERVAL" envDefault:"1h"`
+}
+
+type tenantConfig struct {
+	// Tokens map the bearer tokens to the tenants, e.g. "token1:team-a".
+	// The tenant is taken from the X-Tenant-ID header if there are none.
+	Tokens map[string]string `env:"TENANT_TOKENS" envDefault:""`
+	// Quotas limit the persons of the tenants, e.g. "team-a:1000,team-b:0",
+	// zero is no limit.
+	Quotas map[string]int `env:"TENANT_QUOTAS" envDefault:""`
+	// DefaultQuota limits the persons of the tenants missing in Quotas.
+	DefaultQuota int `env:"TENANT_DEFAULT_QUOTA" envDefault:"0"`
+}
+
+func (c tenantConfig) quotas() model.TenantQuotas {
+	return model.TenantQuotas{Default: c.DefaultQuota, Tenants: c.Quotas}
+}
+
+type outboxConfig struct {
+	BatchSize    int           `env:"OUTBOX_BATCH_SIZE" envDefault:"100"`
+	PollInterval time.Duration `env:"OUTBOX_POLL_INTERVAL" envDefault:"1s"`
+	// Retention is how long the published events are kept in the outbox.
+	Retention       time.Duration `env:"OUTBOX_RETENTION" envDefault:"24h"`
+	CleanupInterval time.Duration `env:"OUTBOX_CLEANUP_INTERVAL" envDefault:"1h"`
+}
+
+type webhookConfig struct {
+	BatchSize    int           `env:"WEBHOOK_BATCH_SIZE" envDefault:"50"`
+	PollInterval time.Duration `env:"WEBHOOK_POLL_INTERVAL" envDefault:"1s"`
+	MaxAttempts  int           `env:"WEBHOOK_MAX_ATTEMPTS" envDefault:"8"`
+	Backoff      time.Duration `env:"WEBHOOK_BACKOFF" envDefault:"1s"`
+	MaxBackoff   time.Duration `env:"WEBHOOK_MAX_BACKOFF" envDefault:"1h"`
+	// DisableAfter is the number of consecutive failures the webhook is disabled after.
+	DisableAfter int           `env:"WEBHOOK_DISABLE_AFTER" envDefault:"20"`
+	Timeout      time.Duration `env:"WEBHOOK_TIMEOUT" envDefault:"10s"`
+	// AdminToken authorizes the webhook admin API, it isn't served without it.
+	AdminToken string `env:"WEBHOOK_ADMIN_TOKEN" envDefault:""`
+}
+
 func Run() {
 	appCtx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -53,33 +225,93 @@ func Run() {
 
 	var cfg config
 	err := env.ParseWithOptions(&cfg, env.Options{RequiredIfNoDef: true})
+	if err == nil {
+		err = cfg.Kafka.validate()
+	}
 	if err != nil {
 		logger.Fatal().Err(err).Msg("parse env params")
 	}
 
-	redisOpt, err := redis.ParseURL(cfg.Redis.URL)
+	backend, err := storage.ParseBackend(cfg.Storage.Backend)
 	if err != nil {
-		logger.Fatal().Err(err).Msg("prepare redis client")
+		logger.Fatal().Err(err).Msg("parse env params")
 	}
-	cache := redis.NewClient(redisOpt)
-	if _, err := cache.Ping(appCtx).Result(); err != nil {
-		logger.Fatal().Err(err).Msg("prepare redis client")
+	storageCfg := storage.Config{
+		Backend: backend,
+		Cache:   cfg.Redis.cacheConfig(),
+		Quotas:  cfg.Tenant.quotas(),
 	}
-	defer func() {
-		if err := cache.ShutdownSave(appCtx).Err(); err != nil {
-			logger.Err(err).Msg("redis client shutdown")
+
+	if backend.UsesRedis() {
+		redisOpt, err := redis.ParseURL(cfg.Redis.URL)
+		if err != nil {
+			logger.Fatal().Err(err).Msg("prepare redis client")
 		}
-	}()
+		cache := redis.NewClient(redisOpt)
+		if _, err := cache.Ping(appCtx).Result(); err != nil {
+			logger.Fatal().Err(err).Msg("prepare redis client")
+		}
+		defer func() {
+			if err := cache.ShutdownSave(appCtx).Err(); err != nil {
+				logger.Err(err).Msg("redis client shutdown")
+			}
+		}()
+		storageCfg.Redis = cache
+	}
 
-	postgresPool, err := postgres.Get(cfg.Postgres.URL)
-	if err != nil {
-		logger.Fatal().Err(err).Msg("prepare postgres pool")
+	var postgresPool *pgxpool.Pool
+	if backend.UsesPostgres() {
+		if cfg.Postgres.Migrate {
+			if err = migratePostgres(appCtx, cfg.Postgres.URL); err != nil {
+				logger.Fatal().Err(err).Msg("migrate postgres db")
+			}
+		}
+		postgresPool, err = postgres.New(appCtx, cfg.Postgres.URL, cfg.Postgres.poolOptions())
+		if err != nil {
+			logger.Fatal().Err(err).Msg("prepare postgres pool")
+		}
+		defer func() {
+			postgresPool.Close()
+		}()
+		storageCfg.Postgres = postgresPool
+
+		storageCfg.PostgresConfig.Explain = cfg.Postgres.Explain
+		replicas := &storageCfg.PostgresConfig.Replicas
+		replicas.CheckInterval = cfg.Postgres.ReplicaCheckInterval
+		for _, url := range cfg.Postgres.ReplicaURLs {
+			replicaPool, err := postgres.New(appCtx, url, cfg.Postgres.replicaPoolOptions())
+			if err != nil {
+				logger.Fatal().Err(err).Msg("prepare postgres replica pool")
+			}
+			defer replicaPool.Close()
+			replicas.Pools = append(replicas.Pools, replicaPool)
+		}
 	}
-	defer func() {
-		postgresPool.Close()
-	}()
 
-	repo, _ := persons.CachedStorage(postgresPool, cache)
+	if backend.UsesSQLite() {
+		sqliteDB, err := sqlite.Open(cfg.Storage.SQLitePath)
+		if err != nil {
+			logger.Fatal().Err(err).Msg("prepare sqlite db")
+		}
+		defer sqliteDB.Close()
+		err = sqlite.Migrate(sqliteDB, persons.SQLiteMigrations, persons.SQLiteMigrationsDir)
+		if err != nil {
+			logger.Fatal().Err(err).Msg("migrate sqlite db")
+		}
+		storageCfg.SQLite = sqliteDB
+	}
+
+	repo, err := storage.Persons(storageCfg)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("prepare persons storage")
+	}
+	if r, ok := repo.(interface{ RunInvalidation(context.Context) }); ok {
+		go r.RunInvalidation(appCtx)
+	}
+	if r, ok := repo.(interface{ RunReplicaChecks(context.Context) }); ok {
+		go r.RunReplicaChecks(appCtx)
+	}
+	logger.Info().Str("backend", string(backend)).Msg("persons storage")
 
 	personMetaDataProvider, err := adapters.PersonMetaData(
 		cfg.API.AgifyService,
@@ -95,17 +327,117 @@ func Run() {
 		logger.Fatal().Err(err).Msg("prepare person manager")
 	}
 
-	// Prepare API
-	_, err = ports.KafkaFIO(
-		appCtx,
-		cfg.Kafka.ReadTopic,
-		cfg.Kafka.ErrTopic,
-		cfg.Kafka.Brokers,
-		cfg.Kafka.ReadLimit,
-		personManager,
-	)
+	if cfg.Persons.Retention > 0 {
+		personPurger, err := persondata.Purger(repo, cfg.Persons.Retention, cfg.Persons.PurgeInterval)
+		if err != nil {
+			logger.Fatal().Err(err).Msg("prepare person purger")
+		}
+		go personPurger.Run(appCtx)
+	}
+
+	ginRouter, err := ginx.Get()
 	if err != nil {
-		logger.Fatal().Err(err).Msg("prepare kafka FIO message handler")
+		logger.Fatal().Err(err).Msg("failed to prepare: gin")
+	}
+	withTenant, err := ports.WithTenant(cfg.Tenant.Tokens)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("prepare tenant middleware")
+	}
+	// The person, GraphQL and webhook routes are scoped to the request tenant.
+	tenantRouter := ginRouter.Group("/", withTenant)
+
+	// The person events are written to the outbox in the postgres transactions,
+	// the memory and sqlite storages publish no events.
+	if postgresPool != nil {
+		outboxStore, err := persons.Outbox(postgresPool)
+		if err != nil {
+			logger.Fatal().Err(err).Msg("prepare outbox storage")
+		}
+		webhookStorage, err := webhooks.Storage(postgresPool)
+		if err != nil {
+			logger.Fatal().Err(err).Msg("prepare webhooks storage")
+		}
+		webhookManager, err := webhook.Manager(webhookStorage)
+		if err != nil {
+			logger.Fatal().Err(err).Msg("prepare webhook manager")
+		}
+		webhookSender, err := adapters.WebhookSender(cfg.Webhook.Timeout)
+		if err != nil {
+			logger.Fatal().Err(err).Msg("prepare webhook sender")
+		}
+		webhookDispatcher, err := webhook.Dispatcher(webhookStorage, webhookSender, webhook.DispatcherConfig{
+			BatchSize:    cfg.Webhook.BatchSize,
+			PollInterval: cfg.Webhook.PollInterval,
+			MaxAttempts:  cfg.Webhook.MaxAttempts,
+			Backoff:      cfg.Webhook.Backoff,
+			MaxBackoff:   cfg.Webhook.MaxBackoff,
+			DisableAfter: cfg.Webhook.DisableAfter,
+			Timeout:      cfg.Webhook.Timeout,
+		})
+		if err != nil {
+			logger.Fatal().Err(err).Msg("prepare webhook dispatcher")
+		}
+		go webhookDispatcher.Run(appCtx)
+
+		events := outbox.Fanout(webhookDispatcher)
+		if cfg.Kafka.Enabled {
+			personEvents, err := adapters.PersonEvents(
+				cfg.Kafka.Brokers,
+				cfg.Kafka.EventsTopic,
+				cfg.Outbox.BatchSize,
+			)
+			if err != nil {
+				logger.Fatal().Err(err).Msg("prepare person events publisher")
+			}
+			defer func() {
+				if err := personEvents.Close(); err != nil {
+					logger.Err(err).Msg("close person events publisher")
+				}
+			}()
+			// The webhook deliveries are enqueued idempotently, so they go before Kafka.
+			events = outbox.Fanout(webhookDispatcher, personEvents)
+		}
+
+		outboxRelay, err := outbox.Relay(outboxStore, events, outbox.RelayConfig{
+			BatchSize:       cfg.Outbox.BatchSize,
+			PollInterval:    cfg.Outbox.PollInterval,
+			Retention:       cfg.Outbox.Retention,
+			CleanupInterval: cfg.Outbox.CleanupInterval,
+		})
+		if err != nil {
+			logger.Fatal().Err(err).Msg("prepare outbox relay")
+		}
+		go outboxRelay.Run(appCtx)
+
+		if len(cfg.Webhook.AdminToken) == 0 {
+			// Anyone could subscribe to the persons of a tenant otherwise.
+			logger.Warn().Msg("WEBHOOK_ADMIN_TOKEN is empty, webhook admin API isn't served")
+		} else {
+			err = ports.WebhookRoutes(tenantRouter, webhookManager, cfg.Webhook.AdminToken)
+			if err != nil {
+				logger.Fatal().Err(err).Msg("prepare webhook routes")
+			}
+		}
+	}
+
+	// Prepare API
+	if cfg.Kafka.Enabled {
+		_, err = ports.KafkaFIO(
+			appCtx,
+			ports.KafkaFIOConfig{
+				ReadTopic:   cfg.Kafka.ReadTopic,
+				ErrorTopic:  cfg.Kafka.ErrTopic,
+				Brokers:     cfg.Kafka.Brokers,
+				GroupID:     cfg.Kafka.GroupID,
+				Workers:     cfg.Kafka.Workers,
+				Order:       cfg.Kafka.Order,
+				MaxInFlight: cfg.Kafka.ReadLimit,
+			},
+			personManager,
+		)
+		if err != nil {
+			logger.Fatal().Err(err).Msg("prepare kafka FIO message handler")
+		}
 	}
 
 	err = ports.HttpRoutes(gin.New(), personManager)
@@ -113,15 +445,13 @@ func Run() {
 		logger.Fatal().Err(err).Msg("prepare person REST routes")
 	}
 
-	ginRouter, err := ginx.Get()
-	if err != nil {
-		logger.Fatal().Err(err).Msg("failed to prepare: gin")
-	}
-	ports.HttpRoutes(ginRouter, personManager)
+	ports.HttpRoutes(tenantRouter, personManager)
 	if err != nil {
 		logger.Fatal().Err(err).Msg("prepare HTTP routes")
 	}
-	ports.Graph(ginRouter, cfg.GraphQL.Path, personManager)
+	// The expvar metrics, e.g. the person cache hits and misses.
+	ginRouter.GET("/debug/vars", gin.WrapH(expvar.Handler()))
+	ports.Graph(tenantRouter, cfg.GraphQL.Path, personManager)
 	if err != nil {
 		logger.Fatal().Err(err).Msg("prepare GraphQL")
 	}
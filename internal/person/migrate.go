@@ -0,0 +1,155 @@
+package person
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/alukart32/effective-mobile-test-task/internal/pkg/migrate"
+	"github.com/alukart32/effective-mobile-test-task/internal/pkg/zerologx"
+	"github.com/alukart32/effective-mobile-test-task/migrations"
+	"github.com/caarlos0/env/v8"
+)
+
+type migrateConfig struct {
+	Postgres postgresConfig
+}
+
+// _migrationSets are the postgres migrations sets, every set has its own
+// version table.
+var _migrationSets = map[string]migrate.Source{
+	"schema": {FS: migrations.FS, Dir: migrations.Dir},
+	"hash-partitioning": {
+		FS:    migrations.FS,
+		Dir:   migrations.HashPartitioningDir,
+		Table: "schema_migrations_hash_partitioning",
+	},
+}
+
+// Migrate runs the migrate subcommand on the postgres db:
+//
+//	person migrate [-set SET] up
+//	person migrate [-set SET] down [N|all]
+//	person migrate [-set SET] status
+//	person migrate [-set SET] force VERSION
+//
+// down reverts one migration by default, force sets the version after a
+// failed migration is fixed by hand. SET is the schema migrations by
+// default, hash-partitioning is the optional one partitioning persons.
+func Migrate(args []string) {
+	logger := zerologx.Get()
+
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	setName := flags.String("set", "schema", "migrations set: schema or hash-partitioning")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: person migrate [flags] up | down [N|all] | status | force VERSION")
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+	set, ok := _migrationSets[*setName]
+	if flags.NArg() == 0 || !ok {
+		flags.Usage()
+		os.Exit(2)
+	}
+	cmd, cmdArgs := flags.Arg(0), flags.Args()[1:]
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	var cfg migrateConfig
+	err := env.ParseWithOptions(&cfg, env.Options{RequiredIfNoDef: true})
+	if err != nil {
+		logger.Fatal().Err(err).Msg("parse env params")
+	}
+	if len(cfg.Postgres.URL) == 0 {
+		logger.Fatal().Err(errors.New("POSTGRES_URL is required")).Msg("parse env params")
+	}
+
+	m, err := migrate.Postgres(cfg.Postgres.URL, set)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("prepare postgres migrator")
+	}
+	defer m.Close()
+
+	switch {
+	case cmd == "up" && len(cmdArgs) == 0:
+		err = m.Up(ctx)
+	case cmd == "down" && len(cmdArgs) <= 1:
+		steps := 1
+		if len(cmdArgs) == 1 {
+			steps, err = parseDownSteps(cmdArgs[0])
+			if err != nil {
+				break
+			}
+		}
+		err = m.Down(ctx, steps)
+	case cmd == "force" && len(cmdArgs) == 1:
+		var version int
+		version, err = strconv.Atoi(cmdArgs[0])
+		if err != nil {
+			err = fmt.Errorf("invalid version: %q", cmdArgs[0])
+			break
+		}
+		err = m.Force(ctx, version)
+	case cmd == "status" && len(cmdArgs) == 0:
+		var status migrate.Status
+		status, err = m.Status()
+		if err == nil {
+			printMigrateStatus(status)
+		}
+	default:
+		flags.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		logger.Fatal().Err(err).Str("set", *setName).Str("cmd", cmd).Msg("migrate postgres db")
+	}
+	logger.Info().Str("set", *setName).Str("cmd", cmd).Msg("migrate postgres db")
+}
+
+// parseDownSteps parses the number of the down migrations, "all" is zero.
+func parseDownSteps(s string) (int, error) {
+	if s == "all" {
+		return 0, nil
+	}
+	steps, err := strconv.Atoi(s)
+	if err != nil || steps <= 0 {
+		return 0, fmt.Errorf("invalid steps: %q", s)
+	}
+	return steps, nil
+}
+
+func printMigrateStatus(status migrate.Status) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintf(w, "version: %d", status.Version)
+	if status.Dirty {
+		fmt.Fprint(w, " (dirty)")
+	}
+	fmt.Fprintln(w)
+	for _, m := range status.Migrations {
+		state := "pending"
+		if m.Applied {
+			state = "applied"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", m.Version, m.Name, state)
+	}
+}
+
+// migratePostgres applies the up migrations at startup, the replicas
+// started together wait for the first one.
+func migratePostgres(ctx context.Context, uri string) error {
+	m, err := migrate.Postgres(uri, _migrationSets["schema"])
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+	return m.Up(ctx)
+}
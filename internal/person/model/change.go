@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// ChangeOp is the kind of the person change.
+type ChangeOp string
+
+const (
+	OpCreate ChangeOp = "create"
+	OpUpdate ChangeOp = "update"
+	// OpDelete is the soft delete of the person.
+	OpDelete  ChangeOp = "delete"
+	OpRestore ChangeOp = "restore"
+	// OpPurge is the hard delete of the soft deleted person after the retention period.
+	OpPurge ChangeOp = "purge"
+	// OpEnrich is the update with the personal metadata recomputed by
+	// the metadata provider.
+	OpEnrich ChangeOp = "enrich"
+)
+
+// PersonChange is the audit history record of the person change.
+// Before is nil for the created person, After is nil for the deleted one.
+type PersonChange struct {
+	Id            int64
+	PersonId      string
+	Op            ChangeOp
+	Before        *Person
+	After         *Person
+	Actor         string
+	Source        string
+	CorrelationId string
+	ChangedAt     time.Time
+}
@@ -0,0 +1,16 @@
+package model
+
+import "errors"
+
+// ErrNotFound reports that the person doesn't exist.
+var ErrNotFound = errors.New("not found")
+
+// ErrVersionMismatch is returned when the person was changed since the expected version.
+var ErrVersionMismatch = errors.New("version mismatch")
+
+// ErrConstraintViolation reports that the person violates the unique or
+// check constraint of the storage.
+var ErrConstraintViolation = errors.New("constraint violation")
+
+// ErrQuotaExceeded reports that the tenant stores as many persons as its quota allows.
+var ErrQuotaExceeded = errors.New("quota exceeded")
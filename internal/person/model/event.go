@@ -0,0 +1,42 @@
+package model
+
+import "time"
+
+// EventType is the type of the person domain event.
+type EventType string
+
+const (
+	EventPersonCreated  EventType = "person.created"
+	EventPersonUpdated  EventType = "person.updated"
+	EventPersonDeleted  EventType = "person.deleted"
+	EventPersonRestored EventType = "person.restored"
+	EventPersonPurged   EventType = "person.purged"
+)
+
+// EventTypeOf returns the event type of the person change.
+func EventTypeOf(op ChangeOp) EventType {
+	switch op {
+	case OpCreate:
+		return EventPersonCreated
+	case OpDelete:
+		return EventPersonDeleted
+	case OpRestore:
+		return EventPersonRestored
+	case OpPurge:
+		return EventPersonPurged
+	default:
+		return EventPersonUpdated
+	}
+}
+
+// OutboxMessage is the domain event stored in the outbox until published.
+// Key is the person ID, the events of the same key are published in order.
+type OutboxMessage struct {
+	Id   int64
+	Key  string
+	Type EventType
+	// Tenant is the tenant of the person, the event is delivered to its webhooks only.
+	Tenant    string
+	Payload   []byte
+	CreatedAt time.Time
+}
@@ -0,0 +1,179 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// PersonPatch is the partial change of the person. The nil field is kept
+// as is, the field set to its zero value is cleared.
+type PersonPatch struct {
+	Name       *string
+	Surname    *string
+	Patronymic *string
+	Nation     *string
+	Gender     *string
+	Age        *int
+	// Enriched are the attributes recomputed by the metadata provider,
+	// the other set attributes are the manual overrides.
+	Enriched []MetaAttr
+}
+
+// NewPersonReplacement returns the patch replacing all the person fields,
+// the empty metadata fields are cleared.
+func NewPersonReplacement(fio FIO, meta PersonalMetaData) PersonPatch {
+	return PersonPatch{
+		Name:       &fio.Name,
+		Surname:    &fio.Surname,
+		Patronymic: &fio.Patronymic,
+		Nation:     &meta.Nation,
+		Gender:     &meta.Gender,
+		Age:        &meta.Age,
+	}
+}
+
+// NewMergePatch parses the JSON Merge Patch (RFC 7396) of the person.
+// The field names are case insensitive, null clears the field.
+func NewMergePatch(data []byte) (PersonPatch, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return PersonPatch{}, fmt.Errorf("invalid merge patch: %w", err)
+	}
+	if fields == nil {
+		return PersonPatch{}, fmt.Errorf("invalid merge patch: not an object")
+	}
+
+	var patch PersonPatch
+	for name, raw := range fields {
+		var err error
+		switch strings.ToLower(name) {
+		case "name":
+			patch.Name, err = mergeString(raw)
+		case "surname":
+			patch.Surname, err = mergeString(raw)
+		case "patronymic":
+			patch.Patronymic, err = mergeString(raw)
+		case "nation":
+			patch.Nation, err = mergeString(raw)
+		case "gender":
+			patch.Gender, err = mergeString(raw)
+		case "age":
+			patch.Age, err = mergeInt(raw)
+		default:
+			err = fmt.Errorf("unsupported field")
+		}
+		if err != nil {
+			return PersonPatch{}, fmt.Errorf("invalid merge patch %s: %w", name, err)
+		}
+	}
+	return patch, nil
+}
+
+func mergeString(raw json.RawMessage) (*string, error) {
+	var s string
+	if !bytes.Equal(raw, []byte("null")) {
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, err
+		}
+	}
+	return &s, nil
+}
+
+func mergeInt(raw json.RawMessage) (*int, error) {
+	var i int
+	if !bytes.Equal(raw, []byte("null")) {
+		if err := json.Unmarshal(raw, &i); err != nil {
+			return nil, err
+		}
+	}
+	return &i, nil
+}
+
+func (p PersonPatch) IsEmpty() bool {
+	return p.Name == nil && p.Surname == nil && p.Patronymic == nil &&
+		p.Nation == nil && p.Gender == nil && p.Age == nil
+}
+
+// Validate checks the patched FIO the same way NewFIO does.
+// The name and surname can't be cleared.
+func (p PersonPatch) Validate() error {
+	if p.Name != nil {
+		if len(*p.Name) == 0 {
+			return fmt.Errorf("empty required name")
+		}
+		if !isAlpha(*p.Name) {
+			return fmt.Errorf("name contains invalid characters: %s", *p.Name)
+		}
+	}
+	if p.Surname != nil {
+		if len(*p.Surname) == 0 {
+			return fmt.Errorf("empty required surname")
+		}
+		if !isAlpha(*p.Surname) {
+			return fmt.Errorf("surname contains invalid characters: %s", *p.Surname)
+		}
+	}
+	if p.Patronymic != nil && len(*p.Patronymic) != 0 && !isAlpha(*p.Patronymic) {
+		return fmt.Errorf("patronymic contains invalid characters: %s", *p.Patronymic)
+	}
+	if p.Age != nil && *p.Age < 0 {
+		return fmt.Errorf("negative age: %d", *p.Age)
+	}
+	return nil
+}
+
+func (p PersonPatch) MarshalZerologObject(e *zerolog.Event) {
+	str := func(key string, v *string) {
+		if v != nil {
+			e.Str(key, *v)
+		}
+	}
+	str("name", p.Name)
+	str("surname", p.Surname)
+	str("patronymic", p.Patronymic)
+	str("nation", p.Nation)
+	str("gender", p.Gender)
+	if p.Age != nil {
+		e.Int("age", *p.Age)
+	}
+	if len(p.Enriched) > 0 {
+		enriched := zerolog.Arr()
+		for _, a := range p.Enriched {
+			enriched.Str(string(a))
+		}
+		e.Array("enriched", enriched)
+	}
+}
+
+// ChangeOp returns the history op of the patch, the patch with the metadata
+// recomputed by the metadata provider is the enrichment.
+func (p PersonPatch) ChangeOp() ChangeOp {
+	if len(p.Enriched) > 0 {
+		return OpEnrich
+	}
+	return OpUpdate
+}
+
+// Overrides returns the attributes set manually by the patch.
+func (p PersonPatch) Overrides() []MetaAttr {
+	var overrides []MetaAttr
+	add := func(attr MetaAttr, set bool) {
+		if !set {
+			return
+		}
+		for _, e := range p.Enriched {
+			if e == attr {
+				return
+			}
+		}
+		overrides = append(overrides, attr)
+	}
+	add(AttrAge, p.Age != nil)
+	add(AttrGender, p.Gender != nil)
+	add(AttrNation, p.Nation != nil)
+	return overrides
+}
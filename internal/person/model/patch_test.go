@@ -0,0 +1,133 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMergePatch(t *testing.T) {
+	str := func(s string) *string { return &s }
+	num := func(i int) *int { return &i }
+
+	type want struct {
+		patch PersonPatch
+		err   bool
+	}
+	tests := []struct {
+		name string
+		data string
+		want want
+	}{
+		{
+			name: "Set and clear fields, no error",
+			data: `{"Surname":"Ivanov","nation":null,"age":30}`,
+			want: want{
+				patch: PersonPatch{
+					Surname: str("Ivanov"),
+					Nation:  str(""),
+					Age:     num(30),
+				},
+			},
+		},
+		{
+			name: "Clear age, no error",
+			data: `{"age":null}`,
+			want: want{
+				patch: PersonPatch{
+					Age: num(0),
+				},
+			},
+		},
+		{
+			name: "Empty object, no error",
+			data: `{}`,
+		},
+		{
+			name: "Unknown field, error",
+			data: `{"id":"1"}`,
+			want: want{
+				err: true,
+			},
+		},
+		{
+			name: "Invalid age type, error",
+			data: `{"age":"30"}`,
+			want: want{
+				err: true,
+			},
+		},
+		{
+			name: "Not an object, error",
+			data: `null`,
+			want: want{
+				err: true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch, err := NewMergePatch([]byte(tt.data))
+			if tt.want.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want.patch, patch)
+		})
+	}
+}
+
+func TestPersonPatch_Validate(t *testing.T) {
+	str := func(s string) *string { return &s }
+	num := func(i int) *int { return &i }
+
+	tests := []struct {
+		name  string
+		patch PersonPatch
+		err   string
+	}{
+		{
+			name: "Valid patch, no error",
+			patch: PersonPatch{
+				Name:       str("Ivan"),
+				Patronymic: str(""),
+				Age:        num(0),
+			},
+		},
+		{
+			name: "Cleared name, error",
+			patch: PersonPatch{
+				Name: str(""),
+			},
+			err: "empty required name",
+		},
+		{
+			name: "Invalid surname, error",
+			patch: PersonPatch{
+				Surname: str("Ivanov1"),
+			},
+			err: "surname contains invalid characters: Ivanov1",
+		},
+		{
+			name: "Negative age, error",
+			patch: PersonPatch{
+				Age: num(-1),
+			},
+			err: "negative age: -1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.patch.Validate()
+			if len(tt.err) != 0 {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
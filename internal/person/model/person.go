@@ -2,8 +2,10 @@ package model
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
@@ -15,10 +17,24 @@ type Person2 struct {
 	PersonalMetaData
 }
 
+// InitialVersion is the version of the new person.
+const InitialVersion int64 = 1
+
 type Person struct {
 	Id string
 	FIO
 	PersonalMetaData
+	// Version is incremented on every change of the person.
+	Version int64
+	// CreatedAt and UpdatedAt are maintained by the storage.
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	// EnrichedAt is set when the personal metadata was enriched.
+	EnrichedAt *time.Time `json:",omitempty"`
+	// Overrides are the metadata attributes set manually, they aren't re-enriched.
+	Overrides []MetaAttr `json:",omitempty"`
+	// DeletedAt is set for the soft deleted person.
+	DeletedAt *time.Time `json:",omitempty"`
 }
 
 func NewPerson(
@@ -29,6 +45,7 @@ func NewPerson(
 		Id:               uuid.New().String(),
 		FIO:              fio,
 		PersonalMetaData: meta,
+		Version:          InitialVersion,
 	}
 }
 
@@ -41,6 +58,7 @@ func (p Person) IsEmpty() bool {
 func (p Person) MarshalZerologObject(e *zerolog.Event) {
 	e.
 		Str("id", p.Id).
+		Int64("version", p.Version).
 		Object("fio", p.FIO).
 		Object("meta", p.PersonalMetaData)
 }
@@ -49,6 +67,22 @@ type PersonFilter struct {
 	OlderThan, YoungerThan int
 	Gender                 string
 	Nations                []string
+	// CreatedAfter and CreatedBefore bound the creation time as [after, before).
+	CreatedAfter, CreatedBefore time.Time
+	// IncludeDeleted adds the soft deleted persons to the result.
+	IncludeDeleted bool
+}
+
+// _filterTimeLayouts are the accepted layouts of the time filters.
+var _filterTimeLayouts = []string{time.RFC3339Nano, time.DateOnly}
+
+func parseFilterTime(v string) (time.Time, error) {
+	for _, layout := range _filterTimeLayouts {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time: %s", v)
 }
 
 func NewPersonFilter(filters []string) (PersonFilter, error) {
@@ -58,7 +92,8 @@ func NewPersonFilter(filters []string) (PersonFilter, error) {
 
 	var filter PersonFilter
 	for _, f := range filters {
-		vals := strings.Split(f, ".")
+		// The time values may contain dots, only the first one separates the filter name.
+		vals := strings.SplitN(f, ".", 2)
 		switch vals[0] {
 		case "older-than":
 			if len(vals[1]) != 0 {
@@ -84,6 +119,22 @@ func NewPersonFilter(filters []string) (PersonFilter, error) {
 			if len(vals[1]) != 0 {
 				filter.Nations = append(filter.Nations, vals[1])
 			}
+		case "created-after":
+			if len(vals[1]) != 0 {
+				t, err := parseFilterTime(vals[1])
+				if err != nil {
+					return PersonFilter{}, fmt.Errorf("filter parsing error: %s", f)
+				}
+				filter.CreatedAfter = t
+			}
+		case "created-before":
+			if len(vals[1]) != 0 {
+				t, err := parseFilterTime(vals[1])
+				if err != nil {
+					return PersonFilter{}, fmt.Errorf("filter parsing error: %s", f)
+				}
+				filter.CreatedBefore = t
+			}
 		default:
 			return PersonFilter{}, fmt.Errorf("unsupported %s filter", f)
 		}
@@ -93,14 +144,47 @@ func NewPersonFilter(filters []string) (PersonFilter, error) {
 
 func (f PersonFilter) IsEmpty() bool {
 	return f.YoungerThan == 0 && f.OlderThan == 0 &&
-		len(f.Gender) == 0 && len(f.Nations) == 0
+		len(f.Gender) == 0 && len(f.Nations) == 0 &&
+		f.CreatedAfter.IsZero() && f.CreatedBefore.IsZero()
+}
+
+// Key returns the canonical form of the filter: the equal filters have
+// the same key regardless of the nations order and the time zones.
+func (f PersonFilter) Key() string {
+	nations := make([]string, 0, len(f.Nations))
+	for _, n := range f.Nations {
+		if !slices.Contains(nations, n) {
+			nations = append(nations, n)
+		}
+	}
+	slices.Sort(nations)
+
+	formatTime := func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.UTC().Format(time.RFC3339Nano)
+	}
+	return fmt.Sprintf("older-than=%d;younger-than=%d;gender=%s;nations=%s;"+
+		"created-after=%s;created-before=%s;include-deleted=%t",
+		f.OlderThan,
+		f.YoungerThan,
+		f.Gender,
+		strings.Join(nations, ","),
+		formatTime(f.CreatedAfter),
+		formatTime(f.CreatedBefore),
+		f.IncludeDeleted,
+	)
 }
 
 func (f PersonFilter) MarshalZerologObject(e *zerolog.Event) {
 	e.
 		Int("older-than", f.OlderThan).
 		Int("younger-than", f.YoungerThan).
-		Str("gender", f.Gender)
+		Str("gender", f.Gender).
+		Time("created-after", f.CreatedAfter).
+		Time("created-before", f.CreatedBefore).
+		Bool("include-deleted", f.IncludeDeleted)
 
 	nations := zerolog.Arr()
 	for _, n := range f.Nations {
@@ -110,6 +194,25 @@ func (f PersonFilter) MarshalZerologObject(e *zerolog.Event) {
 	e.Array("nations", nations)
 }
 
+// MetaAttr is the attribute of the personal metadata.
+type MetaAttr string
+
+const (
+	AttrAge    MetaAttr = "age"
+	AttrGender MetaAttr = "gender"
+	AttrNation MetaAttr = "nation"
+)
+
+// HasOverride reports whether the attribute was set manually.
+func (p Person) HasOverride(attr MetaAttr) bool {
+	for _, o := range p.Overrides {
+		if o == attr {
+			return true
+		}
+	}
+	return false
+}
+
 type PersonalMetaData struct {
 	Nation string
 	Gender string
@@ -127,3 +230,9 @@ func (meta PersonalMetaData) MarshalZerologObject(e *zerolog.Event) {
 		Str("gender", meta.Gender).
 		Str("nation", meta.Nation)
 }
+
+// BatchResult is the outcome of creating a person from a batch item.
+type BatchResult struct {
+	Id  string
+	Err error
+}
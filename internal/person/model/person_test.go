@@ -3,6 +3,7 @@ package model
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -65,6 +66,28 @@ func TestNewPersonFilter(t *testing.T) {
 				err: fmt.Errorf("filter parsing error: %s", "younger-than.txt"),
 			},
 		},
+		{
+			name: "Created time range filters, no error",
+			filters: []string{
+				"created-after.2023-09-01",
+				"created-before.2023-09-02T10:30:00.5Z",
+			},
+			want: want{
+				filter: PersonFilter{
+					CreatedAfter:  time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC),
+					CreatedBefore: time.Date(2023, 9, 2, 10, 30, 0, 500000000, time.UTC),
+				},
+			},
+		},
+		{
+			name: "Invalid created-after filter, error",
+			filters: []string{
+				"created-after.yesterday",
+			},
+			want: want{
+				err: fmt.Errorf("filter parsing error: %s", "created-after.yesterday"),
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -78,3 +101,23 @@ func TestNewPersonFilter(t *testing.T) {
 		})
 	}
 }
+
+func TestPersonFilter_Key(t *testing.T) {
+	msk := time.FixedZone("MSK", 3*60*60)
+	a := PersonFilter{
+		Gender:       "male",
+		Nations:      []string{"RU", "KZ", "RU"},
+		CreatedAfter: time.Date(2023, 9, 1, 3, 0, 0, 0, msk),
+	}
+	b := PersonFilter{
+		Gender:       "male",
+		Nations:      []string{"KZ", "RU"},
+		CreatedAfter: time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC),
+	}
+	assert.Equal(t, a.Key(), b.Key())
+	assert.Equal(t, []string{"RU", "KZ", "RU"}, a.Nations)
+
+	b.IncludeDeleted = true
+	assert.NotEqual(t, a.Key(), b.Key())
+	assert.NotEqual(t, PersonFilter{}.Key(), PersonFilter{OlderThan: 1}.Key())
+}
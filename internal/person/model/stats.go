@@ -0,0 +1,100 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// DefaultAgeBuckets are the age histogram bounds used when none are requested.
+var DefaultAgeBuckets = AgeBuckets{18, 30, 45, 60}
+
+// AgeBuckets are the ascending lower bounds of the age histogram buckets.
+//
+// The bounds b1 < b2 < ... < bn produce the buckets [0, b1), [b1, b2), ...,
+// [bn, +inf).
+type AgeBuckets []int
+
+func NewAgeBuckets(bounds []int) (AgeBuckets, error) {
+	if len(bounds) == 0 {
+		return nil, nil
+	}
+
+	buckets := make(AgeBuckets, len(bounds))
+	for i, b := range bounds {
+		if b <= 0 {
+			return nil, fmt.Errorf("age bucket must be positive: %d", b)
+		}
+		if i > 0 && b <= bounds[i-1] {
+			return nil, fmt.Errorf("age buckets must be ascending: %d after %d", b, bounds[i-1])
+		}
+		buckets[i] = b
+	}
+	return buckets, nil
+}
+
+// ParseAgeBuckets parses comma separated age bucket bounds, e.g. "18,30,45".
+func ParseAgeBuckets(s string) (AgeBuckets, error) {
+	if len(s) == 0 {
+		return nil, nil
+	}
+
+	vals := strings.Split(s, ",")
+	bounds := make([]int, len(vals))
+	for i, v := range vals {
+		b, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return nil, fmt.Errorf("age bucket parsing error: %s", v)
+		}
+		bounds[i] = b
+	}
+	return NewAgeBuckets(bounds)
+}
+
+func (b AgeBuckets) MarshalZerologArray(a *zerolog.Array) {
+	for _, v := range b {
+		a.Int(v)
+	}
+}
+
+// PersonStats is the aggregated statistics over the filtered persons.
+type PersonStats struct {
+	Total        int
+	Genders      []GenderStats
+	Nations      []NationStats
+	AgeHistogram []AgeBucket
+}
+
+type GenderStats struct {
+	Gender string
+	Count  int
+}
+
+type NationStats struct {
+	Nation string
+	Count  int
+	AvgAge float64
+}
+
+// AgeBucket is a histogram bucket [From, To). Zero To means no upper bound.
+type AgeBucket struct {
+	From  int
+	To    int
+	Count int
+}
+
+// NewAgeHistogram returns empty histogram buckets for the bounds.
+func NewAgeHistogram(bounds AgeBuckets) []AgeBucket {
+	histogram := make([]AgeBucket, len(bounds)+1)
+	for i := range histogram {
+		if i > 0 {
+			histogram[i].From = bounds[i-1]
+		}
+		if i < len(bounds) {
+			histogram[i].To = bounds[i]
+		}
+	}
+	return histogram
+}
@@ -0,0 +1,74 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAgeBuckets(t *testing.T) {
+	type want struct {
+		buckets AgeBuckets
+		err     error
+	}
+	tests := []struct {
+		name   string
+		bounds string
+		want   want
+	}{
+		{
+			name:   "Valid buckets, no error",
+			bounds: "18, 30,45",
+			want: want{
+				buckets: AgeBuckets{18, 30, 45},
+			},
+		},
+		{
+			name:   "Empty buckets, no error",
+			bounds: "",
+			want:   want{},
+		},
+		{
+			name:   "Invalid bucket, error",
+			bounds: "18,txt",
+			want: want{
+				err: fmt.Errorf("age bucket parsing error: txt"),
+			},
+		},
+		{
+			name:   "Not positive bucket, error",
+			bounds: "0,18",
+			want: want{
+				err: fmt.Errorf("age bucket must be positive: 0"),
+			},
+		},
+		{
+			name:   "Not ascending buckets, error",
+			bounds: "30,18",
+			want: want{
+				err: fmt.Errorf("age buckets must be ascending: 18 after 30"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buckets, err := ParseAgeBuckets(tt.bounds)
+			if tt.want.err != nil {
+				assert.EqualError(t, err, tt.want.err.Error())
+				return
+			}
+			assert.EqualValues(t, tt.want.buckets, buckets)
+		})
+	}
+}
+
+func TestNewAgeHistogram(t *testing.T) {
+	histogram := NewAgeHistogram(AgeBuckets{18, 30})
+	assert.EqualValues(t, []AgeBucket{
+		{From: 0, To: 18},
+		{From: 18, To: 30},
+		{From: 30, To: 0},
+	}, histogram)
+}
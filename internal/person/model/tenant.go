@@ -0,0 +1,44 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/alukart32/effective-mobile-test-task/internal/pkg/reqmeta"
+)
+
+// isTenant matches the tenant IDs, they're used in the cache keys as is.
+var isTenant = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`).MatchString
+
+// ParseTenant validates the tenant ID, the empty one is the default tenant.
+func ParseTenant(s string) (string, error) {
+	if len(s) == 0 {
+		return reqmeta.DefaultTenant, nil
+	}
+	if !isTenant(s) {
+		return "", fmt.Errorf("invalid tenant: %q, expected lower case letters, digits, - and _", s)
+	}
+	return s, nil
+}
+
+// TenantQuotas limit the number of the persons stored by the tenants.
+// The soft deleted persons count until they're purged. Zero is no limit.
+type TenantQuotas struct {
+	// Default is the quota of the tenants missing in Tenants.
+	Default int
+	Tenants map[string]int
+}
+
+// Limit returns the quota of the tenant.
+func (q TenantQuotas) Limit(tenant string) int {
+	if limit, ok := q.Tenants[tenant]; ok {
+		return limit
+	}
+	return q.Default
+}
+
+// Exceeded reports whether the tenant storing the persons exceeds its quota.
+func (q TenantQuotas) Exceeded(tenant string, persons int64) bool {
+	limit := q.Limit(tenant)
+	return limit > 0 && persons > int64(limit)
+}
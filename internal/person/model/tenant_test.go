@@ -0,0 +1,57 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTenant(t *testing.T) {
+	tests := []struct {
+		name    string
+		tenant  string
+		want    string
+		wantErr bool
+	}{
+		{name: "Empty, default", tenant: "", want: "default"},
+		{name: "Valid, no error", tenant: "team-a_1", want: "team-a_1"},
+		{name: "Upper case, error", tenant: "TeamA", wantErr: true},
+		{name: "Leading dash, error", tenant: "-team", wantErr: true},
+		{name: "Key separator, error", tenant: "team:a", wantErr: true},
+		{name: "Too long, error", tenant: string(make([]byte, 64)), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTenant(tt.tenant)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTenantQuotas_Exceeded(t *testing.T) {
+	quotas := TenantQuotas{Default: 10, Tenants: map[string]int{"small": 2, "unlimited": 0}}
+	tests := []struct {
+		name    string
+		tenant  string
+		persons int64
+		want    bool
+	}{
+		{name: "Default within, not exceeded", tenant: "other", persons: 10},
+		{name: "Default over, exceeded", tenant: "other", persons: 11, want: true},
+		{name: "Tenant within, not exceeded", tenant: "small", persons: 2},
+		{name: "Tenant over, exceeded", tenant: "small", persons: 3, want: true},
+		{name: "Tenant unlimited, not exceeded", tenant: "unlimited", persons: 1000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, quotas.Exceeded(tt.tenant, tt.persons))
+		})
+	}
+	assert.False(t, TenantQuotas{}.Exceeded("any", 1000))
+}
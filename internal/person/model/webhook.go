@@ -0,0 +1,169 @@
+package model
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventTypes are the person event types a webhook can subscribe to.
+var EventTypes = []EventType{
+	EventPersonCreated,
+	EventPersonUpdated,
+	EventPersonDeleted,
+	EventPersonRestored,
+	EventPersonPurged,
+}
+
+// Webhook is the client URL subscribed to the person events.
+type Webhook struct {
+	Id  string
+	URL string
+	// Secret is the HMAC key of the payload signature, it's shown only once
+	// on registration.
+	Secret string `json:"-"`
+	Events []EventType
+	// Enabled is false for the webhook disabled after the consecutive failures.
+	Enabled    bool
+	Failures   int
+	CreatedAt  time.Time
+	DisabledAt *time.Time `json:",omitempty"`
+}
+
+// NewWebhook returns the enabled webhook with the new signing secret.
+func NewWebhook(rawURL string, events []EventType) (Webhook, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return Webhook{}, fmt.Errorf("invalid url: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+		return Webhook{}, fmt.Errorf("invalid url: %s, expected absolute http(s) url", rawURL)
+	}
+	// The host name is checked again by the resolved address on delivery.
+	host := strings.ToLower(u.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return Webhook{}, fmt.Errorf("invalid url: %s, expected public host", rawURL)
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !IsPublicAddr(addr) {
+		return Webhook{}, fmt.Errorf("invalid url: %s, expected public host", rawURL)
+	}
+	if len(events) == 0 {
+		return Webhook{}, fmt.Errorf("empty events")
+	}
+
+	subscribed := make([]EventType, 0, len(events))
+	seen := make(map[EventType]bool, len(events))
+	for _, e := range events {
+		if !e.isKnown() {
+			return Webhook{}, fmt.Errorf("unsupported event: %s", e)
+		}
+		if !seen[e] {
+			seen[e] = true
+			subscribed = append(subscribed, e)
+		}
+	}
+
+	secret := make([]byte, 32)
+	if _, err = rand.Read(secret); err != nil {
+		return Webhook{}, fmt.Errorf("generate secret: %w", err)
+	}
+
+	return Webhook{
+		Id:        uuid.New().String(),
+		URL:       rawURL,
+		Secret:    hex.EncodeToString(secret),
+		Events:    subscribed,
+		Enabled:   true,
+		CreatedAt: time.Now().UTC(),
+	}, nil
+}
+
+// IsPublicAddr reports whether the webhook payload may be sent to addr, it
+// isn't the loopback, link-local, private, unspecified or multicast one.
+func IsPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsValid() &&
+		!addr.IsLoopback() &&
+		!addr.IsLinkLocalUnicast() &&
+		!addr.IsPrivate() &&
+		!addr.IsUnspecified() &&
+		!addr.IsMulticast()
+}
+
+func (e EventType) isKnown() bool {
+	for _, t := range EventTypes {
+		if t == e {
+			return true
+		}
+	}
+	return false
+}
+
+// SignWebhookPayload returns the hex HMAC-SHA256 of "<timestamp>.<payload>".
+// The timestamp is the unix seconds sent along with the signature, so
+// the receiver can reject the replayed payloads.
+func SignWebhookPayload(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// DeliveryStatus is the state of the webhook delivery.
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliveryDelivered DeliveryStatus = "delivered"
+	// DeliveryFailed is the delivery given up after the max attempts.
+	DeliveryFailed DeliveryStatus = "failed"
+)
+
+// WebhookDelivery is the delivery log record of the event to the webhook.
+type WebhookDelivery struct {
+	Id        int64
+	WebhookId string
+	// EventId is the outbox message ID, the event is delivered to the webhook once.
+	EventId      int64
+	EventType    EventType
+	Payload      []byte `json:"-"`
+	Status       DeliveryStatus
+	Attempts     int
+	ResponseCode int    `json:",omitempty"`
+	LastError    string `json:",omitempty"`
+	// NextAttemptAt is the time of the next attempt of the pending delivery.
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// WebhookDispatch is the pending delivery with its webhook.
+type WebhookDispatch struct {
+	Webhook  Webhook
+	Delivery WebhookDelivery
+}
+
+// DeliveryAttempt is the result of the webhook delivery attempt.
+type DeliveryAttempt struct {
+	DeliveryId   int64
+	WebhookId    string
+	ResponseCode int
+	// Err is empty for the successful attempt.
+	Err string
+	// NextAttemptAt is nil if the failed delivery is given up.
+	NextAttemptAt *time.Time
+}
+
+func (a DeliveryAttempt) Succeeded() bool {
+	return len(a.Err) == 0
+}
@@ -0,0 +1,114 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWebhook(t *testing.T) {
+	tests := []struct {
+		name   string
+		url    string
+		events []EventType
+		want   []EventType
+		err    bool
+	}{
+		{
+			name:   "Valid webhook, no error",
+			url:    "https://example.com/hooks/persons",
+			events: []EventType{EventPersonCreated, EventPersonDeleted, EventPersonCreated},
+			want:   []EventType{EventPersonCreated, EventPersonDeleted},
+		},
+		{
+			name:   "Relative url, error",
+			url:    "/hooks/persons",
+			events: []EventType{EventPersonCreated},
+			err:    true,
+		},
+		{
+			name:   "Unsupported scheme, error",
+			url:    "ftp://example.com",
+			events: []EventType{EventPersonCreated},
+			err:    true,
+		},
+		{
+			name:   "Loopback address, error",
+			url:    "http://127.0.0.1:8080/hooks",
+			events: []EventType{EventPersonCreated},
+			err:    true,
+		},
+		{
+			name:   "Mapped loopback address, error",
+			url:    "http://[::ffff:127.0.0.1]/hooks",
+			events: []EventType{EventPersonCreated},
+			err:    true,
+		},
+		{
+			name:   "Link-local metadata address, error",
+			url:    "http://169.254.169.254/latest/meta-data",
+			events: []EventType{EventPersonCreated},
+			err:    true,
+		},
+		{
+			name:   "Private address, error",
+			url:    "https://10.0.0.5/hooks",
+			events: []EventType{EventPersonCreated},
+			err:    true,
+		},
+		{
+			name:   "Unspecified address, error",
+			url:    "http://0.0.0.0/hooks",
+			events: []EventType{EventPersonCreated},
+			err:    true,
+		},
+		{
+			name:   "Localhost name, error",
+			url:    "http://LocalHost:8080/hooks",
+			events: []EventType{EventPersonCreated},
+			err:    true,
+		},
+		{
+			name:   "Public address, no error",
+			url:    "https://93.184.216.34/hooks",
+			events: []EventType{EventPersonCreated},
+			want:   []EventType{EventPersonCreated},
+		},
+		{
+			name: "Empty events, error",
+			url:  "https://example.com",
+			err:  true,
+		},
+		{
+			name:   "Unknown event, error",
+			url:    "https://example.com",
+			events: []EventType{"person.renamed"},
+			err:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := NewWebhook(tt.url, tt.events)
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.url, w.URL)
+			assert.Equal(t, tt.want, w.Events)
+			assert.True(t, w.Enabled)
+			assert.Len(t, w.Secret, 64)
+			assert.NotEmpty(t, w.Id)
+		})
+	}
+}
+
+func TestSignWebhookPayload(t *testing.T) {
+	sig := SignWebhookPayload("secret", 1700000000, []byte(`{"type":"person.created"}`))
+	assert.Len(t, sig, 64)
+	assert.Equal(t, sig, SignWebhookPayload("secret", 1700000000, []byte(`{"type":"person.created"}`)))
+	assert.NotEqual(t, sig, SignWebhookPayload("other", 1700000000, []byte(`{"type":"person.created"}`)))
+	assert.NotEqual(t, sig, SignWebhookPayload("secret", 1700000001, []byte(`{"type":"person.created"}`)))
+}
@@ -0,0 +1,151 @@
+package ports
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/alukart32/effective-mobile-test-task/internal/person/model"
+	"github.com/parquet-go/parquet-go"
+)
+
+const (
+	mimeCSV     = "text/csv"
+	mimeNDJSON  = "application/x-ndjson"
+	mimeParquet = "application/vnd.apache.parquet"
+)
+
+// personEncoder writes the persons one by one. Close flushes the buffered data.
+type personEncoder interface {
+	Encode(model.Person) error
+	Close() error
+}
+
+// newPersonEncoder returns the encoder of the export media type.
+func newPersonEncoder(mediaType string, w io.Writer) personEncoder {
+	switch mediaType {
+	case mimeCSV:
+		return newCSVPersonEncoder(w)
+	case mimeParquet:
+		return newParquetPersonEncoder(w)
+	default:
+		return newNDJSONPersonEncoder(w)
+	}
+}
+
+type csvPersonEncoder struct {
+	w      *csv.Writer
+	header bool
+}
+
+func newCSVPersonEncoder(w io.Writer) *csvPersonEncoder {
+	return &csvPersonEncoder{w: csv.NewWriter(w)}
+}
+
+func (e *csvPersonEncoder) Encode(p model.Person) error {
+	if !e.header {
+		e.header = true
+		err := e.w.Write([]string{
+			"id", "name", "surname", "patronymic", "nation", "gender", "age",
+			"created_at", "updated_at", "enriched_at",
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return e.w.Write([]string{
+		p.Id,
+		p.Name,
+		p.Surname,
+		p.Patronymic,
+		p.Nation,
+		p.Gender,
+		strconv.Itoa(p.Age),
+		p.CreatedAt.Format(time.RFC3339Nano),
+		p.UpdatedAt.Format(time.RFC3339Nano),
+		formatOptionalTime(p.EnrichedAt),
+	})
+}
+
+// formatOptionalTime formats the time or returns an empty string for nil.
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+func (e *csvPersonEncoder) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type ndjsonPersonEncoder struct {
+	enc *json.Encoder
+}
+
+func newNDJSONPersonEncoder(w io.Writer) *ndjsonPersonEncoder {
+	return &ndjsonPersonEncoder{enc: json.NewEncoder(w)}
+}
+
+func (e *ndjsonPersonEncoder) Encode(p model.Person) error {
+	return e.enc.Encode(p)
+}
+
+func (e *ndjsonPersonEncoder) Close() error {
+	return nil
+}
+
+// _parquetRowGroupSize bounds the rows buffered by the parquet writer.
+const _parquetRowGroupSize = 10000
+
+type parquetPerson struct {
+	Id         string `parquet:"id"`
+	Name       string `parquet:"name"`
+	Surname    string `parquet:"surname"`
+	Patronymic string `parquet:"patronymic"`
+	Nation     string `parquet:"nation"`
+	Gender     string `parquet:"gender"`
+	Age        int32  `parquet:"age"`
+
+	CreatedAt  time.Time  `parquet:"created_at"`
+	UpdatedAt  time.Time  `parquet:"updated_at"`
+	EnrichedAt *time.Time `parquet:"enriched_at,optional"`
+}
+
+type parquetPersonEncoder struct {
+	w    *parquet.GenericWriter[parquetPerson]
+	rows []parquetPerson
+}
+
+func newParquetPersonEncoder(w io.Writer) *parquetPersonEncoder {
+	return &parquetPersonEncoder{
+		w: parquet.NewGenericWriter[parquetPerson](w,
+			parquet.MaxRowsPerRowGroup(_parquetRowGroupSize),
+		),
+		rows: make([]parquetPerson, 1),
+	}
+}
+
+func (e *parquetPersonEncoder) Encode(p model.Person) error {
+	e.rows[0] = parquetPerson{
+		Id:         p.Id,
+		Name:       p.Name,
+		Surname:    p.Surname,
+		Patronymic: p.Patronymic,
+		Nation:     p.Nation,
+		Gender:     p.Gender,
+		Age:        int32(p.Age),
+		CreatedAt:  p.CreatedAt,
+		UpdatedAt:  p.UpdatedAt,
+		EnrichedAt: p.EnrichedAt,
+	}
+	_, err := e.w.Write(e.rows)
+	return err
+}
+
+func (e *parquetPersonEncoder) Close() error {
+	return e.w.Close()
+}
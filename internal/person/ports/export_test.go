@@ -0,0 +1,211 @@
+package ports
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alukart32/effective-mobile-test-task/internal/person/model"
+	"github.com/gin-gonic/gin"
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type exporterMock struct {
+	ExportFn func(context.Context, model.PersonFilter, func(model.Person) error) error
+}
+
+func (m *exporterMock) Export(ctx context.Context, filter model.PersonFilter, fn func(model.Person) error) error {
+	return m.ExportFn(ctx, filter, fn)
+}
+
+// exportedPersons are the persons of the exporterMock, the second one
+// isn't enriched.
+func exportedPersons() []model.Person {
+	created := time.Date(2024, 3, 1, 10, 0, 0, 123456000, time.UTC)
+	enriched := created.Add(time.Minute)
+	return []model.Person{
+		{
+			Id:               "1",
+			FIO:              model.FIO{Name: "Ivan", Surname: "Ivanov", Patronymic: "Ivanovich"},
+			PersonalMetaData: model.PersonalMetaData{Nation: "RU", Gender: "male", Age: 30},
+			Version:          2,
+			CreatedAt:        created,
+			UpdatedAt:        enriched,
+			EnrichedAt:       &enriched,
+		},
+		{
+			Id:        "2",
+			FIO:       model.FIO{Name: "Anna", Surname: "Petrova"},
+			CreatedAt: created,
+			UpdatedAt: created,
+		},
+	}
+}
+
+// startExport serves exportPersons of the persons, the export fails with
+// exportErr after them.
+func startExport(t *testing.T, persons []model.Person, exportErr error) *httptest.Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/persons/export", exportPersons(&exporterMock{
+		ExportFn: func(ctx context.Context, filter model.PersonFilter, fn func(model.Person) error) error {
+			for _, p := range persons {
+				if err := fn(p); err != nil {
+					return err
+				}
+			}
+			return exportErr
+		},
+	}))
+	srv := httptest.NewServer(router)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// export requests the export with the Accept header, the body is read, so
+// the trailer is received.
+func export(t *testing.T, srv *httptest.Server, accept string) (*http.Response, []byte) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/persons/export", nil)
+	require.NoError(t, err)
+	if len(accept) != 0 {
+		req.Header.Set("Accept", accept)
+	}
+	resp, err := srv.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, body
+}
+
+func TestExportPersons_Formats(t *testing.T) {
+	persons := exportedPersons()
+	srv := startExport(t, persons, nil)
+
+	t.Run("CSV, header and rows", func(t *testing.T) {
+		resp, body := export(t, srv, mimeCSV)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, mimeCSV, resp.Header.Get("Content-Type"))
+		assert.Empty(t, resp.Trailer.Get("X-Export-Error"))
+
+		rows, err := csv.NewReader(bytes.NewReader(body)).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+		assert.Equal(t, []string{
+			"id", "name", "surname", "patronymic", "nation", "gender", "age",
+			"created_at", "updated_at", "enriched_at",
+		}, rows[0])
+		assert.Equal(t, []string{
+			"1", "Ivan", "Ivanov", "Ivanovich", "RU", "male", "30",
+			"2024-03-01T10:00:00.123456Z", "2024-03-01T10:01:00.123456Z", "2024-03-01T10:01:00.123456Z",
+		}, rows[1])
+		assert.Equal(t, []string{
+			"2", "Anna", "Petrova", "", "", "", "0",
+			"2024-03-01T10:00:00.123456Z", "2024-03-01T10:00:00.123456Z", "",
+		}, rows[2])
+	})
+
+	t.Run("NDJSON, person per line", func(t *testing.T) {
+		resp, body := export(t, srv, mimeNDJSON)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, mimeNDJSON, resp.Header.Get("Content-Type"))
+
+		var got []model.Person
+		scanner := bufio.NewScanner(bytes.NewReader(body))
+		for scanner.Scan() {
+			var p model.Person
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &p))
+			got = append(got, p)
+		}
+		require.NoError(t, scanner.Err())
+		assert.Equal(t, persons, got)
+	})
+
+	t.Run("Parquet, round trip with nil enriched_at", func(t *testing.T) {
+		resp, body := export(t, srv, mimeParquet)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, mimeParquet, resp.Header.Get("Content-Type"))
+
+		rows, err := parquet.Read[parquetPerson](bytes.NewReader(body), int64(len(body)))
+		require.NoError(t, err)
+		require.Len(t, rows, len(persons))
+		for i, p := range persons {
+			row := rows[i]
+			assert.Equal(t, p.Id, row.Id)
+			assert.Equal(t, p.Name, row.Name)
+			assert.Equal(t, p.Surname, row.Surname)
+			assert.Equal(t, p.Patronymic, row.Patronymic)
+			assert.Equal(t, p.Nation, row.Nation)
+			assert.Equal(t, p.Gender, row.Gender)
+			assert.Equal(t, int32(p.Age), row.Age)
+			assert.True(t, p.CreatedAt.Equal(row.CreatedAt), row.CreatedAt)
+			assert.True(t, p.UpdatedAt.Equal(row.UpdatedAt), row.UpdatedAt)
+			if p.EnrichedAt == nil {
+				assert.Nil(t, row.EnrichedAt)
+			} else if assert.NotNil(t, row.EnrichedAt) {
+				assert.True(t, p.EnrichedAt.Equal(*row.EnrichedAt), *row.EnrichedAt)
+			}
+		}
+	})
+}
+
+func TestExportPersons_Accept(t *testing.T) {
+	tests := []struct {
+		name        string
+		accept      string
+		status      int
+		contentType string
+	}{
+		{name: "No Accept, NDJSON", accept: "", status: http.StatusOK, contentType: mimeNDJSON},
+		{name: "Any, NDJSON", accept: "*/*", status: http.StatusOK, contentType: mimeNDJSON},
+		{
+			name:        "Preferred supported, parquet",
+			accept:      "application/json, application/vnd.apache.parquet, text/csv;q=0.5",
+			status:      http.StatusOK,
+			contentType: mimeParquet,
+		},
+		{name: "Text wildcard, CSV", accept: "text/*", status: http.StatusOK, contentType: mimeCSV},
+		{name: "Unsupported, not acceptable", accept: "application/json", status: http.StatusNotAcceptable},
+	}
+
+	srv := startExport(t, exportedPersons(), nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := export(t, srv, tt.accept)
+			require.Equal(t, tt.status, resp.StatusCode, string(body))
+			if tt.status != http.StatusOK {
+				assert.Contains(t, string(body), "unsupported Accept")
+				return
+			}
+			assert.Equal(t, tt.contentType, resp.Header.Get("Content-Type"))
+		})
+	}
+}
+
+func TestExportPersons_ErrorTrailer(t *testing.T) {
+	persons := exportedPersons()
+	srv := startExport(t, persons[:1], errors.New("connection reset"))
+
+	resp, body := export(t, srv, mimeNDJSON)
+	// The status is sent with the first person, the error is in the trailer.
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+	require.Len(t, lines, 1)
+	var p model.Person
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &p))
+	assert.Equal(t, persons[0].Id, p.Id)
+	assert.Equal(t, "export persons: connection reset", resp.Trailer.Get("X-Export-Error"))
+}
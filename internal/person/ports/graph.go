@@ -7,10 +7,11 @@ import (
 	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/alukart32/effective-mobile-test-task/internal/person/ports/graph"
 	gengraph "github.com/alukart32/effective-mobile-test-task/internal/person/ports/graph/generated"
+	"github.com/alukart32/effective-mobile-test-task/internal/pkg/reqmeta"
 	"github.com/gin-gonic/gin"
 )
 
-func Graph(router *gin.Engine, api string, personManager personManager) error {
+func Graph(router gin.IRoutes, api string, personManager personManager) error {
 	if personManager == nil {
 		return fmt.Errorf("init GraphQL schema: personManager is nil")
 	}
@@ -27,7 +28,8 @@ func Graph(router *gin.Engine, api string, personManager personManager) error {
 	srv.AddTransport(transport.POST{})
 
 	router.POST(api, func(c *gin.Context) {
-		srv.ServeHTTP(c.Writer, c.Request)
+		ctx := reqmeta.WithSource(c.Request.Context(), "graph")
+		srv.ServeHTTP(c.Writer, c.Request.WithContext(ctx))
 	})
 	return nil
 }
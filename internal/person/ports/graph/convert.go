@@ -0,0 +1,164 @@
+package graph
+
+import (
+	"fmt"
+	"strconv"
+
+	appmodel "github.com/alukart32/effective-mobile-test-task/internal/person/model"
+	"github.com/alukart32/effective-mobile-test-task/internal/person/ports/graph/model"
+)
+
+// toPersonFilter converts the optional GraphQL filter to the person filter.
+func toPersonFilter(filter *model.CollectPersonsFilter) appmodel.PersonFilter {
+	var personFilter appmodel.PersonFilter
+	if filter == nil {
+		return personFilter
+	}
+	if filter.OlderThan != nil {
+		personFilter.OlderThan = *filter.OlderThan
+	}
+	if filter.YoungerThan != nil {
+		personFilter.YoungerThan = *filter.YoungerThan
+	}
+	if filter.Gender != nil {
+		personFilter.Gender = *filter.Gender
+	}
+	personFilter.Nations = filter.Nations
+	if filter.CreatedAfter != nil {
+		personFilter.CreatedAfter = *filter.CreatedAfter
+	}
+	if filter.CreatedBefore != nil {
+		personFilter.CreatedBefore = *filter.CreatedBefore
+	}
+	if filter.IncludeDeleted != nil {
+		personFilter.IncludeDeleted = *filter.IncludeDeleted
+	}
+	return personFilter
+}
+
+// toPersonPatch converts the update input to the patch. The cleared field
+// can't be set in the same input.
+func toPersonPatch(input model.UpdatePersonInput) (appmodel.PersonPatch, error) {
+	patch := appmodel.PersonPatch{
+		Name:       input.NewName,
+		Surname:    input.NewSurname,
+		Patronymic: input.NewPatronymic,
+		Nation:     input.NewNation,
+		Gender:     input.NewGender,
+		Age:        input.NewAge,
+	}
+	var empty string
+	for _, field := range input.Clear {
+		var set bool
+		switch field {
+		case model.PersonFieldPatronymic:
+			set, patch.Patronymic = patch.Patronymic != nil, &empty
+		case model.PersonFieldNation:
+			set, patch.Nation = patch.Nation != nil, &empty
+		case model.PersonFieldGender:
+			set, patch.Gender = patch.Gender != nil, &empty
+		case model.PersonFieldAge:
+			var zero int
+			set, patch.Age = patch.Age != nil, &zero
+		}
+		if set {
+			return appmodel.PersonPatch{}, fmt.Errorf("%s is both set and cleared", field)
+		}
+	}
+	return patch, nil
+}
+
+func toAttrNames(attrs []appmodel.MetaAttr) []string {
+	names := make([]string, len(attrs))
+	for i, a := range attrs {
+		names[i] = string(a)
+	}
+	return names
+}
+
+// toVersion converts the optional expected version, zero skips the version check.
+func toVersion(v *int) int64 {
+	if v == nil {
+		return 0
+	}
+	return int64(*v)
+}
+
+func toPerson(p appmodel.Person) *model.Person {
+	return &model.Person{
+		ID:         p.Id,
+		Name:       p.Name,
+		Surname:    p.Surname,
+		Patronymic: p.Patronymic,
+		Nation:     p.Nation,
+		Gender:     p.Gender,
+		Age:        p.Age,
+		Version:    int(p.Version),
+		CreatedAt:  p.CreatedAt,
+		UpdatedAt:  p.UpdatedAt,
+		EnrichedAt: p.EnrichedAt,
+		DeletedAt:  p.DeletedAt,
+	}
+}
+
+func toPersonSnapshot(p *appmodel.Person) *model.PersonSnapshot {
+	if p == nil {
+		return nil
+	}
+	return &model.PersonSnapshot{
+		ID:         p.Id,
+		Name:       p.Name,
+		Surname:    p.Surname,
+		Patronymic: p.Patronymic,
+		Nation:     p.Nation,
+		Gender:     p.Gender,
+		Age:        p.Age,
+	}
+}
+
+func toPersonChange(c appmodel.PersonChange) *model.PersonChange {
+	return &model.PersonChange{
+		ID:            strconv.FormatInt(c.Id, 10),
+		Op:            string(c.Op),
+		Before:        toPersonSnapshot(c.Before),
+		After:         toPersonSnapshot(c.After),
+		Actor:         c.Actor,
+		Source:        c.Source,
+		CorrelationID: c.CorrelationId,
+		ChangedAt:     c.ChangedAt,
+	}
+}
+
+func toPersonStats(stats appmodel.PersonStats) *model.PersonStats {
+	res := &model.PersonStats{
+		Total:        stats.Total,
+		Genders:      make([]*model.GenderStats, len(stats.Genders)),
+		Nations:      make([]*model.NationStats, len(stats.Nations)),
+		AgeHistogram: make([]*model.AgeBucket, len(stats.AgeHistogram)),
+	}
+	for i, g := range stats.Genders {
+		res.Genders[i] = &model.GenderStats{
+			Gender: g.Gender,
+			Count:  g.Count,
+		}
+	}
+	for i, n := range stats.Nations {
+		res.Nations[i] = &model.NationStats{
+			Nation: n.Nation,
+			Count:  n.Count,
+			AvgAge: n.AvgAge,
+		}
+	}
+	for i, b := range stats.AgeHistogram {
+		bucket := &model.AgeBucket{
+			From:  b.From,
+			Count: b.Count,
+		}
+		if b.To != 0 {
+			to := b.To
+			bucket.To = &to
+		}
+		res.AgeHistogram[i] = bucket
+	}
+	return res
+}
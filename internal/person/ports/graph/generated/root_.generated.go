@@ -32,6 +32,7 @@ type Config struct {
 
 type ResolverRoot interface {
 	Mutation() MutationResolver
+	Person() PersonResolver
 	Query() QueryResolver
 }
 
@@ -39,22 +40,77 @@ type DirectiveRoot struct {
 }
 
 type ComplexityRoot struct {
+	AgeBucket struct {
+		Count func(childComplexity int) int
+		From  func(childComplexity int) int
+		To    func(childComplexity int) int
+	}
+
 	CreatePersonResponse struct {
 		PersonID func(childComplexity int) int
 		Success  func(childComplexity int) int
 	}
 
+	CreatePersonResult struct {
+		Err      func(childComplexity int) int
+		PersonID func(childComplexity int) int
+	}
+
+	CreatePersonsResponse struct {
+		Results func(childComplexity int) int
+	}
+
 	DeletePersonResponse struct {
 		Success func(childComplexity int) int
 	}
 
+	GenderStats struct {
+		Count  func(childComplexity int) int
+		Gender func(childComplexity int) int
+	}
+
 	Mutation struct {
-		CreatePerson func(childComplexity int, input model.CreatePersonInput) int
-		DeletePerson func(childComplexity int, input model.DeletePersonInput) int
-		UpdatePerson func(childComplexity int, input model.UpdatePersonInput) int
+		CreatePerson  func(childComplexity int, input model.CreatePersonInput) int
+		CreatePersons func(childComplexity int, input []*model.CreatePersonInput) int
+		DeletePerson  func(childComplexity int, input model.DeletePersonInput) int
+		RestorePerson func(childComplexity int, input model.RestorePersonInput) int
+		UpdatePerson  func(childComplexity int, input model.UpdatePersonInput) int
+	}
+
+	NationStats struct {
+		AvgAge func(childComplexity int) int
+		Count  func(childComplexity int) int
+		Nation func(childComplexity int) int
 	}
 
 	Person struct {
+		Age        func(childComplexity int) int
+		CreatedAt  func(childComplexity int) int
+		DeletedAt  func(childComplexity int) int
+		EnrichedAt func(childComplexity int) int
+		Gender     func(childComplexity int) int
+		History    func(childComplexity int, limit *int, offset *int) int
+		ID         func(childComplexity int) int
+		Name       func(childComplexity int) int
+		Nation     func(childComplexity int) int
+		Patronymic func(childComplexity int) int
+		Surname    func(childComplexity int) int
+		UpdatedAt  func(childComplexity int) int
+		Version    func(childComplexity int) int
+	}
+
+	PersonChange struct {
+		Actor         func(childComplexity int) int
+		After         func(childComplexity int) int
+		Before        func(childComplexity int) int
+		ChangedAt     func(childComplexity int) int
+		CorrelationID func(childComplexity int) int
+		ID            func(childComplexity int) int
+		Op            func(childComplexity int) int
+		Source        func(childComplexity int) int
+	}
+
+	PersonSnapshot struct {
 		Age        func(childComplexity int) int
 		Gender     func(childComplexity int) int
 		ID         func(childComplexity int) int
@@ -64,15 +120,28 @@ type ComplexityRoot struct {
 		Surname    func(childComplexity int) int
 	}
 
+	PersonStats struct {
+		AgeHistogram func(childComplexity int) int
+		Genders      func(childComplexity int) int
+		Nations      func(childComplexity int) int
+		Total        func(childComplexity int) int
+	}
+
 	Query struct {
 		CollectPersons func(childComplexity int, limit *int, offset *int, filter *model.CollectPersonsFilter) int
-		FindByID       func(childComplexity int, personID string) int
+		FindByID       func(childComplexity int, personID string, includeDeleted *bool) int
 		GetAllPersons  func(childComplexity int) int
+		PersonStats    func(childComplexity int, filter *model.CollectPersonsFilter, ageBuckets []int) int
 	}
 
-	UpdatePersonResponse struct {
+	RestorePersonResponse struct {
 		Success func(childComplexity int) int
 	}
+
+	UpdatePersonResponse struct {
+		Recomputed func(childComplexity int) int
+		Success    func(childComplexity int) int
+	}
 }
 
 type executableSchema struct {
@@ -90,6 +159,27 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 	_ = ec
 	switch typeName + "." + field {
 
+	case "AgeBucket.count":
+		if e.complexity.AgeBucket.Count == nil {
+			break
+		}
+
+		return e.complexity.AgeBucket.Count(childComplexity), true
+
+	case "AgeBucket.from":
+		if e.complexity.AgeBucket.From == nil {
+			break
+		}
+
+		return e.complexity.AgeBucket.From(childComplexity), true
+
+	case "AgeBucket.to":
+		if e.complexity.AgeBucket.To == nil {
+			break
+		}
+
+		return e.complexity.AgeBucket.To(childComplexity), true
+
 	case "CreatePersonResponse.personId":
 		if e.complexity.CreatePersonResponse.PersonID == nil {
 			break
@@ -104,6 +194,27 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.CreatePersonResponse.Success(childComplexity), true
 
+	case "CreatePersonResult.err":
+		if e.complexity.CreatePersonResult.Err == nil {
+			break
+		}
+
+		return e.complexity.CreatePersonResult.Err(childComplexity), true
+
+	case "CreatePersonResult.personId":
+		if e.complexity.CreatePersonResult.PersonID == nil {
+			break
+		}
+
+		return e.complexity.CreatePersonResult.PersonID(childComplexity), true
+
+	case "CreatePersonsResponse.results":
+		if e.complexity.CreatePersonsResponse.Results == nil {
+			break
+		}
+
+		return e.complexity.CreatePersonsResponse.Results(childComplexity), true
+
 	case "DeletePersonResponse.success":
 		if e.complexity.DeletePersonResponse.Success == nil {
 			break
@@ -111,6 +222,20 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.DeletePersonResponse.Success(childComplexity), true
 
+	case "GenderStats.count":
+		if e.complexity.GenderStats.Count == nil {
+			break
+		}
+
+		return e.complexity.GenderStats.Count(childComplexity), true
+
+	case "GenderStats.gender":
+		if e.complexity.GenderStats.Gender == nil {
+			break
+		}
+
+		return e.complexity.GenderStats.Gender(childComplexity), true
+
 	case "Mutation.CreatePerson":
 		if e.complexity.Mutation.CreatePerson == nil {
 			break
@@ -123,6 +248,18 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Mutation.CreatePerson(childComplexity, args["input"].(model.CreatePersonInput)), true
 
+	case "Mutation.CreatePersons":
+		if e.complexity.Mutation.CreatePersons == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_CreatePersons_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.CreatePersons(childComplexity, args["input"].([]*model.CreatePersonInput)), true
+
 	case "Mutation.DeletePerson":
 		if e.complexity.Mutation.DeletePerson == nil {
 			break
@@ -135,6 +272,18 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Mutation.DeletePerson(childComplexity, args["input"].(model.DeletePersonInput)), true
 
+	case "Mutation.RestorePerson":
+		if e.complexity.Mutation.RestorePerson == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_RestorePerson_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.RestorePerson(childComplexity, args["input"].(model.RestorePersonInput)), true
+
 	case "Mutation.UpdatePerson":
 		if e.complexity.Mutation.UpdatePerson == nil {
 			break
@@ -147,6 +296,27 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Mutation.UpdatePerson(childComplexity, args["input"].(model.UpdatePersonInput)), true
 
+	case "NationStats.avgAge":
+		if e.complexity.NationStats.AvgAge == nil {
+			break
+		}
+
+		return e.complexity.NationStats.AvgAge(childComplexity), true
+
+	case "NationStats.count":
+		if e.complexity.NationStats.Count == nil {
+			break
+		}
+
+		return e.complexity.NationStats.Count(childComplexity), true
+
+	case "NationStats.nation":
+		if e.complexity.NationStats.Nation == nil {
+			break
+		}
+
+		return e.complexity.NationStats.Nation(childComplexity), true
+
 	case "Person.age":
 		if e.complexity.Person.Age == nil {
 			break
@@ -154,6 +324,27 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Person.Age(childComplexity), true
 
+	case "Person.createdAt":
+		if e.complexity.Person.CreatedAt == nil {
+			break
+		}
+
+		return e.complexity.Person.CreatedAt(childComplexity), true
+
+	case "Person.deletedAt":
+		if e.complexity.Person.DeletedAt == nil {
+			break
+		}
+
+		return e.complexity.Person.DeletedAt(childComplexity), true
+
+	case "Person.enrichedAt":
+		if e.complexity.Person.EnrichedAt == nil {
+			break
+		}
+
+		return e.complexity.Person.EnrichedAt(childComplexity), true
+
 	case "Person.gender":
 		if e.complexity.Person.Gender == nil {
 			break
@@ -161,6 +352,18 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Person.Gender(childComplexity), true
 
+	case "Person.history":
+		if e.complexity.Person.History == nil {
+			break
+		}
+
+		args, err := ec.field_Person_history_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Person.History(childComplexity, args["limit"].(*int), args["offset"].(*int)), true
+
 	case "Person.id":
 		if e.complexity.Person.ID == nil {
 			break
@@ -196,6 +399,153 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Person.Surname(childComplexity), true
 
+	case "Person.updatedAt":
+		if e.complexity.Person.UpdatedAt == nil {
+			break
+		}
+
+		return e.complexity.Person.UpdatedAt(childComplexity), true
+
+	case "Person.version":
+		if e.complexity.Person.Version == nil {
+			break
+		}
+
+		return e.complexity.Person.Version(childComplexity), true
+
+	case "PersonChange.actor":
+		if e.complexity.PersonChange.Actor == nil {
+			break
+		}
+
+		return e.complexity.PersonChange.Actor(childComplexity), true
+
+	case "PersonChange.after":
+		if e.complexity.PersonChange.After == nil {
+			break
+		}
+
+		return e.complexity.PersonChange.After(childComplexity), true
+
+	case "PersonChange.before":
+		if e.complexity.PersonChange.Before == nil {
+			break
+		}
+
+		return e.complexity.PersonChange.Before(childComplexity), true
+
+	case "PersonChange.changedAt":
+		if e.complexity.PersonChange.ChangedAt == nil {
+			break
+		}
+
+		return e.complexity.PersonChange.ChangedAt(childComplexity), true
+
+	case "PersonChange.correlationId":
+		if e.complexity.PersonChange.CorrelationID == nil {
+			break
+		}
+
+		return e.complexity.PersonChange.CorrelationID(childComplexity), true
+
+	case "PersonChange.id":
+		if e.complexity.PersonChange.ID == nil {
+			break
+		}
+
+		return e.complexity.PersonChange.ID(childComplexity), true
+
+	case "PersonChange.op":
+		if e.complexity.PersonChange.Op == nil {
+			break
+		}
+
+		return e.complexity.PersonChange.Op(childComplexity), true
+
+	case "PersonChange.source":
+		if e.complexity.PersonChange.Source == nil {
+			break
+		}
+
+		return e.complexity.PersonChange.Source(childComplexity), true
+
+	case "PersonSnapshot.age":
+		if e.complexity.PersonSnapshot.Age == nil {
+			break
+		}
+
+		return e.complexity.PersonSnapshot.Age(childComplexity), true
+
+	case "PersonSnapshot.gender":
+		if e.complexity.PersonSnapshot.Gender == nil {
+			break
+		}
+
+		return e.complexity.PersonSnapshot.Gender(childComplexity), true
+
+	case "PersonSnapshot.id":
+		if e.complexity.PersonSnapshot.ID == nil {
+			break
+		}
+
+		return e.complexity.PersonSnapshot.ID(childComplexity), true
+
+	case "PersonSnapshot.name":
+		if e.complexity.PersonSnapshot.Name == nil {
+			break
+		}
+
+		return e.complexity.PersonSnapshot.Name(childComplexity), true
+
+	case "PersonSnapshot.nation":
+		if e.complexity.PersonSnapshot.Nation == nil {
+			break
+		}
+
+		return e.complexity.PersonSnapshot.Nation(childComplexity), true
+
+	case "PersonSnapshot.patronymic":
+		if e.complexity.PersonSnapshot.Patronymic == nil {
+			break
+		}
+
+		return e.complexity.PersonSnapshot.Patronymic(childComplexity), true
+
+	case "PersonSnapshot.surname":
+		if e.complexity.PersonSnapshot.Surname == nil {
+			break
+		}
+
+		return e.complexity.PersonSnapshot.Surname(childComplexity), true
+
+	case "PersonStats.ageHistogram":
+		if e.complexity.PersonStats.AgeHistogram == nil {
+			break
+		}
+
+		return e.complexity.PersonStats.AgeHistogram(childComplexity), true
+
+	case "PersonStats.genders":
+		if e.complexity.PersonStats.Genders == nil {
+			break
+		}
+
+		return e.complexity.PersonStats.Genders(childComplexity), true
+
+	case "PersonStats.nations":
+		if e.complexity.PersonStats.Nations == nil {
+			break
+		}
+
+		return e.complexity.PersonStats.Nations(childComplexity), true
+
+	case "PersonStats.total":
+		if e.complexity.PersonStats.Total == nil {
+			break
+		}
+
+		return e.complexity.PersonStats.Total(childComplexity), true
+
 	case "Query.CollectPersons":
 		if e.complexity.Query.CollectPersons == nil {
 			break
@@ -218,7 +568,7 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 			return 0, false
 		}
 
-		return e.complexity.Query.FindByID(childComplexity, args["PersonId"].(string)), true
+		return e.complexity.Query.FindByID(childComplexity, args["PersonId"].(string), args["includeDeleted"].(*bool)), true
 
 	case "Query.GetAllPersons":
 		if e.complexity.Query.GetAllPersons == nil {
@@ -227,6 +577,32 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Query.GetAllPersons(childComplexity), true
 
+	case "Query.PersonStats":
+		if e.complexity.Query.PersonStats == nil {
+			break
+		}
+
+		args, err := ec.field_Query_PersonStats_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.PersonStats(childComplexity, args["filter"].(*model.CollectPersonsFilter), args["ageBuckets"].([]int)), true
+
+	case "RestorePersonResponse.success":
+		if e.complexity.RestorePersonResponse.Success == nil {
+			break
+		}
+
+		return e.complexity.RestorePersonResponse.Success(childComplexity), true
+
+	case "UpdatePersonResponse.recomputed":
+		if e.complexity.UpdatePersonResponse.Recomputed == nil {
+			break
+		}
+
+		return e.complexity.UpdatePersonResponse.Recomputed(childComplexity), true
+
 	case "UpdatePersonResponse.success":
 		if e.complexity.UpdatePersonResponse.Success == nil {
 			break
@@ -245,6 +621,7 @@ func (e *executableSchema) Exec(ctx context.Context) graphql.ResponseHandler {
 		ec.unmarshalInputCollectPersonsFilter,
 		ec.unmarshalInputCreatePersonInput,
 		ec.unmarshalInputDeletePersonInput,
+		ec.unmarshalInputRestorePersonInput,
 		ec.unmarshalInputUpdatePersonInput,
 	)
 	first := true
@@ -343,7 +720,25 @@ func (ec *executionContext) introspectType(name string) (*introspection.Type, er
 }
 
 var sources = []*ast.Source{
-	{Name: "../../../../../api/graph/schema.graphqls", Input: `type Person {
+	{Name: "../../../../../api/graph/schema.graphqls", Input: `scalar Time
+
+type Person {
+    id: String!
+    name: String!
+    surname: String!
+    patronymic: String!
+    nation: String!
+    gender: String!
+    age: Int!
+    version: Int!
+    createdAt: Time!
+    updatedAt: Time!
+    enrichedAt: Time
+    deletedAt: Time
+    history(limit: Int, offset: Int): [PersonChange!]!
+}
+
+type PersonSnapshot {
     id: String!
     name: String!
     surname: String!
@@ -353,10 +748,46 @@ var sources = []*ast.Source{
     age: Int!
 }
 
+type PersonChange {
+    id: ID!
+    op: String!
+    before: PersonSnapshot
+    after: PersonSnapshot
+    actor: String!
+    source: String!
+    correlationId: String!
+    changedAt: Time!
+}
+
+type GenderStats {
+  gender: String!
+  count: Int!
+}
+
+type NationStats {
+  nation: String!
+  count: Int!
+  avgAge: Float!
+}
+
+type AgeBucket {
+  from: Int!
+  to: Int
+  count: Int!
+}
+
+type PersonStats {
+  total: Int!
+  genders: [GenderStats!]!
+  nations: [NationStats!]!
+  ageHistogram: [AgeBucket!]!
+}
+
 type Query {
   GetAllPersons: [Person!]!
   CollectPersons(limit: Int, offset: Int, filter: CollectPersonsFilter): [Person!]
-  FindById(PersonId: String!): Person
+  FindById(PersonId: String!, includeDeleted: Boolean): Person
+  PersonStats(filter: CollectPersonsFilter, ageBuckets: [Int!]): PersonStats!
 }
 
 input CreatePersonInput {
@@ -370,37 +801,74 @@ type CreatePersonResponse {
   personId: String!
 }
 
+type CreatePersonResult {
+  personId: String
+  err: String
+}
+
+type CreatePersonsResponse {
+  results: [CreatePersonResult!]!
+}
+
 input CollectPersonsFilter {
   olderThan: Int
   youngerThan: Int
 	gender: String
 	nations: [String!]
+  createdAfter: Time
+  createdBefore: Time
+  includeDeleted: Boolean
 }
 
 
+enum PersonField {
+  PATRONYMIC
+  NATION
+  GENDER
+  AGE
+}
+
 input UpdatePersonInput {
   personId: String!
+  newName: String
+  newSurname: String
+  newPatronymic: String
   newNation: String
   newGender: String
   newAge: Int
+  clear: [PersonField!]
+  expectedVersion: Int
+  reenrich: Boolean
 }
 
 type UpdatePersonResponse {
   success: Boolean!
+  recomputed: [String!]!
 }
 
 input DeletePersonInput {
   personId: String!
+  expectedVersion: Int
 }
 
 type DeletePersonResponse {
   success: Boolean!
 }
 
+input RestorePersonInput {
+  personId: String!
+}
+
+type RestorePersonResponse {
+  success: Boolean!
+}
+
 type Mutation {
   CreatePerson(input: CreatePersonInput!): CreatePersonResponse!
+  CreatePersons(input: [CreatePersonInput!]!): CreatePersonsResponse!
   UpdatePerson(input: UpdatePersonInput!): UpdatePersonResponse!
   DeletePerson(input: DeletePersonInput!): DeletePersonResponse!
+  RestorePerson(input: RestorePersonInput!): RestorePersonResponse!
 }
 `, BuiltIn: false},
 }
@@ -2,11 +2,27 @@
 
 package model
 
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+type AgeBucket struct {
+	From  int  `json:"from"`
+	To    *int `json:"to,omitempty"`
+	Count int  `json:"count"`
+}
+
 type CollectPersonsFilter struct {
-	OlderThan   *int     `json:"olderThan,omitempty"`
-	YoungerThan *int     `json:"youngerThan,omitempty"`
-	Gender      *string  `json:"gender,omitempty"`
-	Nations     []string `json:"nations,omitempty"`
+	OlderThan      *int       `json:"olderThan,omitempty"`
+	YoungerThan    *int       `json:"youngerThan,omitempty"`
+	Gender         *string    `json:"gender,omitempty"`
+	Nations        []string   `json:"nations,omitempty"`
+	CreatedAfter   *time.Time `json:"createdAfter,omitempty"`
+	CreatedBefore  *time.Time `json:"createdBefore,omitempty"`
+	IncludeDeleted *bool      `json:"includeDeleted,omitempty"`
 }
 
 type CreatePersonInput struct {
@@ -20,15 +36,63 @@ type CreatePersonResponse struct {
 	PersonID string `json:"personId"`
 }
 
+type CreatePersonResult struct {
+	PersonID *string `json:"personId,omitempty"`
+	Err      *string `json:"err,omitempty"`
+}
+
+type CreatePersonsResponse struct {
+	Results []*CreatePersonResult `json:"results"`
+}
+
 type DeletePersonInput struct {
-	PersonID string `json:"personId"`
+	PersonID        string `json:"personId"`
+	ExpectedVersion *int   `json:"expectedVersion,omitempty"`
 }
 
 type DeletePersonResponse struct {
 	Success bool `json:"success"`
 }
 
+type GenderStats struct {
+	Gender string `json:"gender"`
+	Count  int    `json:"count"`
+}
+
+type NationStats struct {
+	Nation string  `json:"nation"`
+	Count  int     `json:"count"`
+	AvgAge float64 `json:"avgAge"`
+}
+
 type Person struct {
+	ID         string          `json:"id"`
+	Name       string          `json:"name"`
+	Surname    string          `json:"surname"`
+	Patronymic string          `json:"patronymic"`
+	Nation     string          `json:"nation"`
+	Gender     string          `json:"gender"`
+	Age        int             `json:"age"`
+	Version    int             `json:"version"`
+	CreatedAt  time.Time       `json:"createdAt"`
+	UpdatedAt  time.Time       `json:"updatedAt"`
+	EnrichedAt *time.Time      `json:"enrichedAt,omitempty"`
+	DeletedAt  *time.Time      `json:"deletedAt,omitempty"`
+	History    []*PersonChange `json:"history"`
+}
+
+type PersonChange struct {
+	ID            string          `json:"id"`
+	Op            string          `json:"op"`
+	Before        *PersonSnapshot `json:"before,omitempty"`
+	After         *PersonSnapshot `json:"after,omitempty"`
+	Actor         string          `json:"actor"`
+	Source        string          `json:"source"`
+	CorrelationID string          `json:"correlationId"`
+	ChangedAt     time.Time       `json:"changedAt"`
+}
+
+type PersonSnapshot struct {
 	ID         string `json:"id"`
 	Name       string `json:"name"`
 	Surname    string `json:"surname"`
@@ -38,13 +102,80 @@ type Person struct {
 	Age        int    `json:"age"`
 }
 
+type PersonStats struct {
+	Total        int            `json:"total"`
+	Genders      []*GenderStats `json:"genders"`
+	Nations      []*NationStats `json:"nations"`
+	AgeHistogram []*AgeBucket   `json:"ageHistogram"`
+}
+
+type RestorePersonInput struct {
+	PersonID string `json:"personId"`
+}
+
+type RestorePersonResponse struct {
+	Success bool `json:"success"`
+}
+
 type UpdatePersonInput struct {
-	PersonID  string  `json:"personId"`
-	NewNation *string `json:"newNation,omitempty"`
-	NewGender *string `json:"newGender,omitempty"`
-	NewAge    *int    `json:"newAge,omitempty"`
+	PersonID        string        `json:"personId"`
+	NewName         *string       `json:"newName,omitempty"`
+	NewSurname      *string       `json:"newSurname,omitempty"`
+	NewPatronymic   *string       `json:"newPatronymic,omitempty"`
+	NewNation       *string       `json:"newNation,omitempty"`
+	NewGender       *string       `json:"newGender,omitempty"`
+	NewAge          *int          `json:"newAge,omitempty"`
+	Clear           []PersonField `json:"clear,omitempty"`
+	ExpectedVersion *int          `json:"expectedVersion,omitempty"`
+	Reenrich        *bool         `json:"reenrich,omitempty"`
 }
 
 type UpdatePersonResponse struct {
-	Success bool `json:"success"`
+	Success    bool     `json:"success"`
+	Recomputed []string `json:"recomputed"`
+}
+
+type PersonField string
+
+const (
+	PersonFieldPatronymic PersonField = "PATRONYMIC"
+	PersonFieldNation     PersonField = "NATION"
+	PersonFieldGender     PersonField = "GENDER"
+	PersonFieldAge        PersonField = "AGE"
+)
+
+var AllPersonField = []PersonField{
+	PersonFieldPatronymic,
+	PersonFieldNation,
+	PersonFieldGender,
+	PersonFieldAge,
+}
+
+func (e PersonField) IsValid() bool {
+	switch e {
+	case PersonFieldPatronymic, PersonFieldNation, PersonFieldGender, PersonFieldAge:
+		return true
+	}
+	return false
+}
+
+func (e PersonField) String() string {
+	return string(e)
+}
+
+func (e *PersonField) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PersonField(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PersonField", str)
+	}
+	return nil
+}
+
+func (e PersonField) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
 }
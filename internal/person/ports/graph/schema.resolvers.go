@@ -42,12 +42,66 @@ func (r *mutationResolver) CreatePerson(ctx context.Context, input model.CreateP
 	}, nil
 }
 
+// CreatePersons is the resolver for the CreatePersons field.
+func (r *mutationResolver) CreatePersons(ctx context.Context, input []*model.CreatePersonInput) (*model.CreatePersonsResponse, error) {
+	logger := zerologx.Get().With().Ctx(ctx).Logger()
+	logger.UpdateContext(func(c zerolog.Context) zerolog.Context {
+		return c.Str("port", "graph").Str("op", "create persons")
+	})
+	if len(input) == 0 {
+		err := fmt.Errorf("empty input")
+		logger.Err(err).Send()
+		return nil, fmt.Errorf("create persons: %w", err)
+	}
+
+	// Invalid FIOs are reported without being passed to the creator.
+	results := make([]*model.CreatePersonResult, len(input))
+	fios := make([]appmodel.FIO, 0, len(input))
+	idxs := make([]int, 0, len(input))
+	for i, in := range input {
+		results[i] = &model.CreatePersonResult{}
+		fio, err := appmodel.NewFIO(in.Name, in.Surname, in.Patronymic)
+		if err != nil {
+			msg := err.Error()
+			results[i].Err = &msg
+			continue
+		}
+		fios = append(fios, fio)
+		idxs = append(idxs, i)
+	}
+	logger.Info().
+		Dict("params", zerolog.Dict().
+			Int("total", len(input)).
+			Int("valid", len(fios)),
+		).
+		Msg(">> create persons")
+
+	if len(fios) > 0 {
+		created, err := r.PersonManager.CreateFromBatch(ctx, fios)
+		if err != nil {
+			logger.Err(err).Send()
+			return nil, fmt.Errorf("create persons: %w", err)
+		}
+		for i, res := range created {
+			if res.Err != nil {
+				msg := res.Err.Error()
+				results[idxs[i]].Err = &msg
+				continue
+			}
+			id := res.Id
+			results[idxs[i]].PersonID = &id
+		}
+	}
+	logger.Info().Str("status", "ok").Msg("<< create persons")
+
+	return &model.CreatePersonsResponse{Results: results}, nil
+}
+
 // UpdatePerson is the resolver for the UpdatePerson field.
 func (r *mutationResolver) UpdatePerson(ctx context.Context, input model.UpdatePersonInput) (*model.UpdatePersonResponse, error) {
-	metaData := appmodel.PersonalMetaData{
-		Nation: *input.NewNation,
-		Gender: *input.NewGender,
-		Age:    *input.NewAge,
+	patch, err := toPersonPatch(input)
+	if err != nil {
+		return nil, fmt.Errorf("update person: %w", err)
 	}
 
 	logger := zerologx.Get().With().Ctx(ctx).Logger()
@@ -56,18 +110,20 @@ func (r *mutationResolver) UpdatePerson(ctx context.Context, input model.UpdateP
 			Str("op", "update person").
 			Dict("params", zerolog.Dict().
 				Str("id", input.PersonID).
-				Object("meta", metaData),
+				Object("patch", patch),
 			)
 	})
 	logger.Info().Msg(">> update person")
 
-	if err := r.PersonManager.Update(ctx, input.PersonID, metaData); err != nil {
+	reenrich := input.Reenrich != nil && *input.Reenrich
+	_, recomputed, err := r.PersonManager.Update(ctx, input.PersonID, patch, toVersion(input.ExpectedVersion), reenrich)
+	if err != nil {
 		logger.Err(err).Send()
 		return nil, fmt.Errorf("update person: %w", err)
 	}
 	logger.Info().Str("status", "ok").Msg("<< update person")
 
-	return &model.UpdatePersonResponse{Success: true}, nil
+	return &model.UpdatePersonResponse{Success: true, Recomputed: toAttrNames(recomputed)}, nil
 }
 
 // DeletePerson is the resolver for the DeletePerson field.
@@ -80,7 +136,7 @@ func (r *mutationResolver) DeletePerson(ctx context.Context, input model.DeleteP
 	})
 	logger.Info().Msg(">> delete person")
 
-	if err := r.PersonManager.Delete(ctx, input.PersonID); err != nil {
+	if err := r.PersonManager.Delete(ctx, input.PersonID, toVersion(input.ExpectedVersion)); err != nil {
 		logger.Err(err).Send()
 		return nil, fmt.Errorf("delete person: %w", err)
 	}
@@ -89,6 +145,64 @@ func (r *mutationResolver) DeletePerson(ctx context.Context, input model.DeleteP
 	return &model.DeletePersonResponse{Success: true}, nil
 }
 
+// RestorePerson is the resolver for the RestorePerson field.
+func (r *mutationResolver) RestorePerson(ctx context.Context, input model.RestorePersonInput) (*model.RestorePersonResponse, error) {
+	logger := zerologx.Get().With().Ctx(ctx).Logger()
+	logger.UpdateContext(func(c zerolog.Context) zerolog.Context {
+		return c.Str("port", "graph").
+			Str("op", "restore person").
+			Str("param", input.PersonID)
+	})
+	logger.Info().Msg(">> restore person")
+
+	if err := r.PersonManager.Restore(ctx, input.PersonID); err != nil {
+		logger.Err(err).Send()
+		return nil, fmt.Errorf("restore person: %w", err)
+	}
+	logger.Info().Str("status", "ok").Msg("<< restore person")
+
+	return &model.RestorePersonResponse{Success: true}, nil
+}
+
+// History is the resolver for the history field.
+func (r *personResolver) History(ctx context.Context, obj *model.Person, limit *int, offset *int) ([]*model.PersonChange, error) {
+	var (
+		resultLimit  int
+		resultOffset int
+	)
+	if limit != nil {
+		resultLimit = *limit
+	}
+	if offset != nil {
+		resultOffset = *offset
+	}
+
+	logger := zerologx.Get().With().Ctx(ctx).Logger()
+	logger.UpdateContext(func(c zerolog.Context) zerolog.Context {
+		return c.Str("port", "graph").
+			Str("op", "person history").
+			Dict("params", zerolog.Dict().
+				Str("id", obj.ID).
+				Int("limit", resultLimit).
+				Int("offset", resultOffset),
+			)
+	})
+	logger.Info().Msg(">> person history")
+
+	changes, err := r.PersonManager.History(ctx, obj.ID, resultLimit, resultOffset)
+	if err != nil {
+		logger.Err(err).Send()
+		return nil, fmt.Errorf("person history: %w", err)
+	}
+	logger.Info().Str("status", "ok").Msg("<< person history")
+
+	list := make([]*model.PersonChange, len(changes))
+	for i, c := range changes {
+		list[i] = toPersonChange(c)
+	}
+	return list, nil
+}
+
 // GetAllPersons is the resolver for the GetAllPersons field.
 func (r *queryResolver) GetAllPersons(ctx context.Context) ([]*model.Person, error) {
 	logger := zerologx.Get().With().Ctx(ctx).Logger()
@@ -106,15 +220,7 @@ func (r *queryResolver) GetAllPersons(ctx context.Context) ([]*model.Person, err
 
 	list := make([]*model.Person, len(persons))
 	for i, p := range persons {
-		list[i] = &model.Person{
-			ID:         p.Id,
-			Name:       p.Name,
-			Surname:    p.Surname,
-			Patronymic: p.Patronymic,
-			Nation:     p.Nation,
-			Gender:     p.Gender,
-			Age:        p.Age,
-		}
+		list[i] = toPerson(p)
 	}
 	return list, nil
 }
@@ -132,15 +238,7 @@ func (r *queryResolver) CollectPersons(ctx context.Context, limit *int, offset *
 		resultOffset = *offset
 	}
 
-	var personFilter appmodel.PersonFilter
-	if filter != nil {
-		personFilter = appmodel.PersonFilter{
-			OlderThan:   *filter.OlderThan,
-			YoungerThan: *filter.YoungerThan,
-			Gender:      *filter.Gender,
-			Nations:     filter.Nations,
-		}
-	}
+	personFilter := toPersonFilter(filter)
 
 	logger := zerologx.Get().With().Ctx(ctx).Logger()
 	logger.UpdateContext(func(c zerolog.Context) zerolog.Context {
@@ -163,21 +261,13 @@ func (r *queryResolver) CollectPersons(ctx context.Context, limit *int, offset *
 
 	list := make([]*model.Person, len(persons))
 	for i, p := range persons {
-		list[i] = &model.Person{
-			ID:         p.Id,
-			Name:       p.Name,
-			Surname:    p.Surname,
-			Patronymic: p.Patronymic,
-			Nation:     p.Nation,
-			Gender:     p.Gender,
-			Age:        p.Age,
-		}
+		list[i] = toPerson(p)
 	}
 	return list, nil
 }
 
 // FindByID is the resolver for the FindById field.
-func (r *queryResolver) FindByID(ctx context.Context, personID string) (*model.Person, error) {
+func (r *queryResolver) FindByID(ctx context.Context, personID string, includeDeleted *bool) (*model.Person, error) {
 	logger := zerologx.Get().With().Ctx(ctx).Logger()
 	logger.UpdateContext(func(c zerolog.Context) zerolog.Context {
 		return c.Str("port", "graph").
@@ -186,29 +276,57 @@ func (r *queryResolver) FindByID(ctx context.Context, personID string) (*model.P
 	})
 	logger.Info().Msg(">> find person")
 
-	person, err := r.PersonManager.FindById(ctx, personID)
+	person, err := r.PersonManager.FindById(ctx, personID, includeDeleted != nil && *includeDeleted)
 	if err != nil {
 		logger.Err(err).Send()
 		return nil, fmt.Errorf("find person by id: %w", err)
 	}
 	logger.Info().Str("status", "ok").Object("person", person).Msg("<< find person")
 
-	return &model.Person{
-		ID:         person.Id,
-		Name:       person.Name,
-		Surname:    person.Surname,
-		Patronymic: person.Patronymic,
-		Nation:     person.Nation,
-		Gender:     person.Gender,
-		Age:        person.Age,
-	}, nil
+	return toPerson(person), nil
+}
+
+// PersonStats is the resolver for the PersonStats field.
+func (r *queryResolver) PersonStats(ctx context.Context, filter *model.CollectPersonsFilter, ageBuckets []int) (*model.PersonStats, error) {
+	logger := zerologx.Get().With().Ctx(ctx).Logger()
+	logger.UpdateContext(func(c zerolog.Context) zerolog.Context {
+		return c.Str("port", "graph").Str("op", "person stats")
+	})
+
+	buckets, err := appmodel.NewAgeBuckets(ageBuckets)
+	if err != nil {
+		logger.Err(err).Send()
+		return nil, fmt.Errorf("person stats: %w", err)
+	}
+	personFilter := toPersonFilter(filter)
+
+	logger.UpdateContext(func(c zerolog.Context) zerolog.Context {
+		return c.Dict("params", zerolog.Dict().
+			Object("filters", personFilter).
+			Array("buckets", buckets),
+		)
+	})
+	logger.Info().Msg(">> person stats")
+
+	stats, err := r.PersonManager.Stats(ctx, personFilter, buckets)
+	if err != nil {
+		logger.Err(err).Send()
+		return nil, fmt.Errorf("person stats: %w", err)
+	}
+	logger.Info().Str("status", "ok").Msg("<< person stats")
+
+	return toPersonStats(stats), nil
 }
 
 // Mutation returns generated.MutationResolver implementation.
 func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResolver{r} }
 
+// Person returns generated.PersonResolver implementation.
+func (r *Resolver) Person() generated.PersonResolver { return &personResolver{r} }
+
 // Query returns generated.QueryResolver implementation.
 func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
 
 type mutationResolver struct{ *Resolver }
+type personResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
@@ -1,34 +1,125 @@
 package ports
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"os"
 	"strconv"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/alukart32/effective-mobile-test-task/internal/person/model"
+	"github.com/alukart32/effective-mobile-test-task/internal/person/service/fioimport"
+	"github.com/alukart32/effective-mobile-test-task/internal/pkg/reqmeta"
 	"github.com/alukart32/effective-mobile-test-task/internal/pkg/zerologx"
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog"
 )
 
-func HttpRoutes(router *gin.Engine, manager personManager) error {
+func HttpRoutes(router gin.IRouter, manager personManager) error {
 	if manager == nil {
 		return fmt.Errorf("init HTTP routes: personManager is nil")
 	}
+	importer, err := fioimport.Importer(manager, 0)
+	if err != nil {
+		return fmt.Errorf("init HTTP routes: %w", err)
+	}
 
-	g := router.Group("/persons")
+	g := router.Group("/persons", withSource("http"))
 	{
 		g.GET("/", collectPersons(manager))
 		g.POST("/", createPerson(manager))
+		g.POST("/batch", createPersons(manager))
+		g.POST("/import", importPersons(importer))
+		g.GET("/stats", personStats(manager))
+		g.GET("/export", exportPersons(manager))
 		g.GET("/:id", getPerson(manager))
+		g.GET("/:id/history", personHistory(manager))
 		g.DELETE("/:id", deletePerson(manager))
+		g.POST("/:id/restore", restorePerson(manager))
 		g.PATCH("/:id", updatePerson(manager))
+		g.PUT("/:id", replacePerson(manager))
 	}
 
 	return nil
 }
 
+// withSource marks the request context with the source port.
+func withSource(source string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(reqmeta.WithSource(c.Request.Context(), source))
+		c.Next()
+	}
+}
+
+// includeDeleted parses the include_deleted query param.
+func includeDeleted(c *gin.Context) (bool, error) {
+	v := c.Query("include_deleted")
+	if len(v) == 0 {
+		return false, nil
+	}
+	include, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("invalid value for include_deleted: %s", v)
+	}
+	return include, nil
+}
+
+// reenrichQuery parses the reenrich query param.
+func reenrichQuery(c *gin.Context) (bool, error) {
+	v := c.Query("reenrich")
+	if len(v) == 0 {
+		return false, nil
+	}
+	reenrich, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("invalid value for reenrich: %s", v)
+	}
+	return reenrich, nil
+}
+
+// errStatus returns the HTTP status of the manager error.
+func errStatus(err error) int {
+	if errors.Is(err, model.ErrNotFound) {
+		return http.StatusNotFound
+	}
+	if errors.Is(err, model.ErrVersionMismatch) {
+		return http.StatusPreconditionFailed
+	}
+	if errors.Is(err, model.ErrQuotaExceeded) {
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
+
+// etag returns the strong entity tag of the person version.
+func etag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// ifMatchVersion parses the person version of the If-Match header.
+// The missing header and "*" are zero, the version isn't checked then.
+func ifMatchVersion(c *gin.Context) (int64, error) {
+	v := strings.TrimSpace(c.GetHeader("If-Match"))
+	if len(v) == 0 || v == "*" {
+		return 0, nil
+	}
+	unquoted, err := strconv.Unquote(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value for If-Match: %s", v)
+	}
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, fmt.Errorf("invalid value for If-Match: %s", v)
+	}
+	return version, nil
+}
+
 type createPersonRequest struct {
 	Name       string
 	Surname    string
@@ -70,7 +161,7 @@ func createPerson(creator personCreator) gin.HandlerFunc {
 		var personId string
 		if personId, err = creator.CreateFrom(c.Request.Context(), fio); err != nil {
 			logger.Err(err).Send()
-			c.JSON(http.StatusInternalServerError,
+			c.JSON(errStatus(err),
 				gin.H{"err": fmt.Errorf("create person: %w", err).Error()})
 			return
 		}
@@ -79,6 +170,186 @@ func createPerson(creator personCreator) gin.HandlerFunc {
 	}
 }
 
+// _maxCreateBatch limits the number of FIOs in one batch create request.
+const _maxCreateBatch = 50000
+
+type createPersonResult struct {
+	Id  string `json:",omitempty"`
+	Err string `json:",omitempty"`
+}
+
+type createPersonsResponse struct {
+	Results []createPersonResult
+}
+
+func createPersons(creator personBatchCreator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger := zerologx.Get().With().Ctx(c.Request.Context()).Logger()
+		logger.UpdateContext(func(c zerolog.Context) zerolog.Context {
+			return c.Str("port", "http").Str("op", "create persons")
+		})
+
+		var reqData []createPersonRequest
+		err := c.ShouldBindJSON(&reqData)
+		if err != nil {
+			logger.Err(err).Send()
+			c.JSON(http.StatusBadRequest,
+				gin.H{"err": fmt.Errorf("create persons: %w", err).Error()})
+			return
+		}
+		if len(reqData) == 0 || len(reqData) > _maxCreateBatch {
+			msg := fmt.Sprintf("invalid batch size: %d, expected 1..%d", len(reqData), _maxCreateBatch)
+			logger.Error().Msg(msg)
+			c.JSON(http.StatusBadRequest,
+				gin.H{"err": msg})
+			return
+		}
+
+		// Invalid FIOs are reported without being passed to the creator.
+		results := make([]createPersonResult, len(reqData))
+		fios := make([]model.FIO, 0, len(reqData))
+		idxs := make([]int, 0, len(reqData))
+		for i, r := range reqData {
+			fio, err := model.NewFIO(r.Name, r.Surname, r.Patronymic)
+			if err != nil {
+				results[i].Err = err.Error()
+				continue
+			}
+			fios = append(fios, fio)
+			idxs = append(idxs, i)
+		}
+		logger.UpdateContext(func(c zerolog.Context) zerolog.Context {
+			return c.Dict("params", zerolog.Dict().
+				Int("total", len(reqData)).
+				Int("valid", len(fios)),
+			)
+		})
+		logger.Info().Msg(">> create persons")
+
+		if len(fios) > 0 {
+			created, err := creator.CreateFromBatch(c.Request.Context(), fios)
+			if err != nil {
+				logger.Err(err).Send()
+				c.JSON(errStatus(err),
+					gin.H{"err": fmt.Errorf("create persons: %w", err).Error()})
+				return
+			}
+			for i, res := range created {
+				results[idxs[i]].Id = res.Id
+				if res.Err != nil {
+					results[idxs[i]].Err = res.Err.Error()
+				}
+			}
+		}
+		logger.Info().Str("status", "ok").Msg("<< create persons")
+		c.JSON(http.StatusOK, createPersonsResponse{Results: results})
+	}
+}
+
+type fioImporter interface {
+	Import(ctx context.Context, r fioimport.Reader, report io.Writer) (fioimport.Summary, error)
+}
+
+// importPersons streams the CSV or NDJSON request body to the importer.
+// The response is the CSV report of the rejected rows, the import summary
+// is in the X-Import-* headers.
+func importPersons(importer fioImporter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger := zerologx.Get().With().Ctx(c.Request.Context()).Logger()
+		logger.UpdateContext(func(c zerolog.Context) zerolog.Context {
+			return c.Str("port", "http").Str("op", "import persons")
+		})
+
+		format := c.Query("format")
+		if len(format) == 0 {
+			mediaType, _, _ := mime.ParseMediaType(c.ContentType())
+			switch mediaType {
+			case "application/x-ndjson", "application/jsonl":
+				format = string(fioimport.NDJSON)
+			default:
+				format = string(fioimport.CSV)
+			}
+		}
+		importFormat, err := fioimport.ParseFormat(format)
+		if err != nil {
+			logger.Err(err).Send()
+			c.JSON(http.StatusBadRequest,
+				gin.H{"err": fmt.Errorf("import persons: %w", err).Error()})
+			return
+		}
+
+		var r fioimport.Reader
+		switch importFormat {
+		case fioimport.NDJSON:
+			r = fioimport.NDJSONReader(c.Request.Body)
+		case fioimport.CSV:
+			opts := fioimport.CSVOptions{
+				Header: c.Query("header") == "true",
+			}
+			if comma := c.Query("comma"); len(comma) != 0 {
+				opts.Comma, _ = utf8.DecodeRuneInString(comma)
+			}
+			opts.Columns, err = fioimport.ParseColumnMapping(c.Query("columns"))
+			if err == nil {
+				r, err = fioimport.CSVReader(c.Request.Body, opts)
+			}
+			if err != nil {
+				logger.Err(err).Send()
+				c.JSON(http.StatusBadRequest,
+					gin.H{"err": fmt.Errorf("import persons: %w", err).Error()})
+				return
+			}
+		}
+		logger.UpdateContext(func(c zerolog.Context) zerolog.Context {
+			return c.Str("format", string(importFormat))
+		})
+		logger.Info().Msg(">> import persons")
+
+		// The report is kept on disk until the whole body is read.
+		report, err := os.CreateTemp("", "persons-import-*.csv")
+		if err != nil {
+			logger.Err(err).Send()
+			c.JSON(http.StatusInternalServerError,
+				gin.H{"err": fmt.Errorf("import persons: %w", err).Error()})
+			return
+		}
+		defer func() {
+			report.Close()
+			os.Remove(report.Name())
+		}()
+
+		summary, err := importer.Import(c.Request.Context(), r, report)
+		if err != nil {
+			logger.Err(err).Send()
+			c.JSON(http.StatusInternalServerError,
+				gin.H{"err": fmt.Errorf("import persons: %w", err).Error()})
+			return
+		}
+		logger.Info().
+			Int("total", summary.Total).
+			Int("imported", summary.Imported).
+			Int("rejected", summary.Rejected).
+			Msg("<< import persons")
+
+		size, err := report.Seek(0, io.SeekCurrent)
+		if err == nil {
+			_, err = report.Seek(0, io.SeekStart)
+		}
+		if err != nil {
+			logger.Err(err).Send()
+			c.JSON(http.StatusInternalServerError,
+				gin.H{"err": fmt.Errorf("import persons: %w", err).Error()})
+			return
+		}
+		c.DataFromReader(http.StatusOK, size, "text/csv; charset=utf-8", report, map[string]string{
+			"Content-Disposition": `attachment; filename="import-report.csv"`,
+			"X-Import-Total":      strconv.Itoa(summary.Total),
+			"X-Import-Imported":   strconv.Itoa(summary.Imported),
+			"X-Import-Rejected":   strconv.Itoa(summary.Rejected),
+		})
+	}
+}
+
 func getPerson(finder personFinder) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id := c.Param("id")
@@ -97,12 +368,19 @@ func getPerson(finder personFinder) gin.HandlerFunc {
 				gin.H{"err": msg})
 			return
 		}
+		withDeleted, err := includeDeleted(c)
+		if err != nil {
+			logger.Err(err).Send()
+			c.JSON(http.StatusBadRequest,
+				gin.H{"err": err.Error()})
+			return
+		}
 		logger.Info().Msg(">> find person")
 
-		person, err := finder.FindById(c.Request.Context(), id)
+		person, err := finder.FindById(c.Request.Context(), id, withDeleted)
 		if err != nil {
 			logger.Err(err).Send()
-			c.JSON(http.StatusInternalServerError,
+			c.JSON(errStatus(err),
 				gin.H{"err": fmt.Errorf("get person: %w", err).Error()})
 			return
 		}
@@ -115,6 +393,7 @@ func getPerson(finder personFinder) gin.HandlerFunc {
 				gin.H{"err": fmt.Errorf("get person: %w", err).Error()})
 			return
 		}
+		c.Header("ETag", etag(person.Version))
 		c.Data(http.StatusOK, "application/json; charset=utf-8", respBody)
 	}
 }
@@ -164,6 +443,13 @@ func collectPersons(collector personCollector) gin.HandlerFunc {
 				return
 			}
 		}
+		filter.IncludeDeleted, err = includeDeleted(c)
+		if err != nil {
+			logger.Err(err).Send()
+			c.JSON(http.StatusBadRequest,
+				gin.H{"err": err.Error()})
+			return
+		}
 
 		logger.UpdateContext(func(c zerolog.Context) zerolog.Context {
 			return c.Dict("params", zerolog.Dict().
@@ -201,12 +487,245 @@ func collectPersons(collector personCollector) gin.HandlerFunc {
 	}
 }
 
+// exportPersons streams the filtered persons in the format negotiated by the
+// Accept header. The error after the first written person is reported in
+// the X-Export-Error trailer.
+func exportPersons(exporter personExporter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			filter model.PersonFilter
+			err    error
+		)
+		logger := zerologx.Get().With().Ctx(c.Request.Context()).Logger()
+		logger.UpdateContext(func(c zerolog.Context) zerolog.Context {
+			return c.Str("port", "http").Str("op", "export persons")
+		})
+		filters := c.QueryArray("filter")
+		if len(filters) > 0 {
+			filter, err = model.NewPersonFilter(filters)
+			if err != nil {
+				msg := "invalid value for filter"
+				logger.Err(err).Msg(msg)
+				c.JSON(http.StatusBadRequest,
+					gin.H{"err": fmt.Errorf("%s: %w", msg, err).Error()})
+				return
+			}
+		}
+
+		mediaType := c.NegotiateFormat(mimeNDJSON, mimeCSV, mimeParquet)
+		if len(mediaType) == 0 {
+			msg := "unsupported Accept: " + c.GetHeader("Accept")
+			logger.Error().Msg(msg)
+			c.JSON(http.StatusNotAcceptable,
+				gin.H{"err": msg})
+			return
+		}
+
+		logger.UpdateContext(func(c zerolog.Context) zerolog.Context {
+			return c.Dict("params", zerolog.Dict().
+				Object("filters", filter).
+				Str("format", mediaType),
+			)
+		})
+		logger.Info().Msg(">> export persons")
+
+		c.Header("Content-Type", mediaType)
+		c.Header("Trailer", "X-Export-Error")
+		c.Status(http.StatusOK)
+
+		var exported int
+		enc := newPersonEncoder(mediaType, c.Writer)
+		err = exporter.Export(c.Request.Context(), filter, func(p model.Person) error {
+			exported++
+			return enc.Encode(p)
+		})
+		if closeErr := enc.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			err = fmt.Errorf("export persons: %w", err)
+			logger.Err(err).Int("exported", exported).Send()
+			c.Writer.Header().Set("X-Export-Error", err.Error())
+			return
+		}
+		logger.Info().Str("status", "ok").Int("exported", exported).Msg("<< export persons")
+	}
+}
+
+func personStats(statsCollector personStatsCollector) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			filter  model.PersonFilter
+			buckets model.AgeBuckets
+			err     error
+		)
+		logger := zerologx.Get().With().Ctx(c.Request.Context()).Logger()
+		logger.UpdateContext(func(c zerolog.Context) zerolog.Context {
+			return c.Str("port", "http").Str("op", "person stats")
+		})
+		filters := c.QueryArray("filter")
+		if len(filters) > 0 {
+			filter, err = model.NewPersonFilter(filters)
+			if err != nil {
+				msg := "invalid value for filter"
+				logger.Err(err).Msg(msg)
+				c.JSON(http.StatusBadRequest,
+					gin.H{"err": fmt.Errorf("%s: %w", msg, err).Error()})
+				return
+			}
+		}
+		buckets, err = model.ParseAgeBuckets(c.Query("buckets"))
+		if err != nil {
+			msg := "invalid value for buckets"
+			logger.Err(err).Msg(msg)
+			c.JSON(http.StatusBadRequest,
+				gin.H{"err": fmt.Errorf("%s: %w", msg, err).Error()})
+			return
+		}
+
+		logger.UpdateContext(func(c zerolog.Context) zerolog.Context {
+			return c.Dict("params", zerolog.Dict().
+				Object("filters", filter).
+				Array("buckets", buckets),
+			)
+		})
+		logger.Info().Msg(">> person stats")
+
+		stats, err := statsCollector.Stats(c.Request.Context(), filter, buckets)
+		if err != nil {
+			err = fmt.Errorf("person stats: %w", err)
+			logger.Err(err).Send()
+			c.JSON(http.StatusInternalServerError,
+				gin.H{"err": err.Error()})
+			return
+		}
+		logger.Info().Str("status", "ok").Msg("<< person stats")
+
+		respBody, err := json.Marshal(stats)
+		if err != nil {
+			logger.Err(err).Send()
+			c.JSON(http.StatusInternalServerError,
+				gin.H{"err": fmt.Errorf("person stats: %w", err).Error()})
+			return
+		}
+		c.Data(http.StatusOK, "application/json; charset=utf-8", respBody)
+	}
+}
+
+func personHistory(historian personHistorian) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			id     = c.Param("id")
+			limit  int
+			offset int
+			err    error
+		)
+		logger := zerologx.Get().With().Ctx(c.Request.Context()).Logger()
+		logger.UpdateContext(func(c zerolog.Context) zerolog.Context {
+			return c.Str("port", "http").
+				Str("op", "person history").
+				Str("param", id)
+		})
+
+		if len(id) == 0 {
+			msg := "invalid value for id: empty"
+			logger.Error().Msg(msg)
+			c.JSON(http.StatusBadRequest,
+				gin.H{"err": msg})
+			return
+		}
+		limitFromQuery := c.Query("limit")
+		if limitFromQuery != "" {
+			limit, err = strconv.Atoi(limitFromQuery)
+			if err != nil || limit < -1 {
+				msg := "invalid value for limit: " + limitFromQuery
+				logger.Error().Msg(msg)
+				c.JSON(http.StatusBadRequest,
+					gin.H{"err": msg})
+				return
+			}
+		}
+		offsetFromQuery := c.Query("offset")
+		if offsetFromQuery != "" {
+			offset, err = strconv.Atoi(offsetFromQuery)
+			if err != nil || offset < -1 {
+				msg := "invalid value for offset: " + offsetFromQuery
+				logger.Error().Msg(msg)
+				c.JSON(http.StatusBadRequest,
+					gin.H{"err": msg})
+				return
+			}
+		}
+		logger.Info().Msg(">> person history")
+
+		changes, err := historian.History(c.Request.Context(), id, limit, offset)
+		if err != nil {
+			logger.Err(err).Send()
+			c.JSON(http.StatusInternalServerError,
+				gin.H{"err": fmt.Errorf("person history: %w", err).Error()})
+			return
+		}
+		logger.Info().Str("status", "ok").Int("changes", len(changes)).Msg("<< person history")
+
+		if len(changes) == 0 {
+			c.Status(http.StatusNoContent)
+			return
+		}
+
+		respBody, err := json.Marshal(changes)
+		if err != nil {
+			logger.Err(err).Send()
+			c.JSON(http.StatusInternalServerError,
+				gin.H{"err": fmt.Errorf("person history: %w", err).Error()})
+			return
+		}
+		c.Data(http.StatusOK, "application/json; charset=utf-8", respBody)
+	}
+}
+
+const mimeMergePatch = "application/merge-patch+json"
+
+// updatePersonRequest is the plain JSON update of the person. The zero
+// fields are kept as is, the unknown fields are ignored.
 type updatePersonRequest struct {
-	Nation string
-	Gender string
-	Age    int
+	Name       string
+	Surname    string
+	Patronymic string
+	Nation     string
+	Gender     string
+	Age        int
+}
+
+// patch returns the patch of the non zero fields.
+func (r updatePersonRequest) patch() model.PersonPatch {
+	str := func(v string) *string {
+		if len(v) == 0 {
+			return nil
+		}
+		return &v
+	}
+	patch := model.PersonPatch{
+		Name:       str(r.Name),
+		Surname:    str(r.Surname),
+		Patronymic: str(r.Patronymic),
+		Nation:     str(r.Nation),
+		Gender:     str(r.Gender),
+	}
+	if r.Age != 0 {
+		patch.Age = &r.Age
+	}
+	return patch
+}
+
+type updatePersonResponse struct {
+	// Recomputed are the re-enriched metadata attributes.
+	Recomputed []model.MetaAttr `json:",omitempty"`
 }
 
+// updatePerson applies the JSON Merge Patch (RFC 7396) of the
+// application/merge-patch+json request body, null clears the field. The
+// application/json body sets its non zero fields only. With reenrich=true the metadata of the renamed
+// person is recomputed except the manual overrides.
 func updatePerson(updater personUpdater) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id := c.Param("id")
@@ -226,29 +745,158 @@ func updatePerson(updater personUpdater) gin.HandlerFunc {
 			return
 		}
 
-		var reqData updatePersonRequest
-		err := c.ShouldBindJSON(&reqData)
+		mediaType, _, _ := mime.ParseMediaType(c.ContentType())
+		if mediaType != "application/json" && mediaType != mimeMergePatch {
+			msg := "unsupported content type: " + c.ContentType()
+			logger.Error().Msg(msg)
+			c.JSON(http.StatusUnsupportedMediaType,
+				gin.H{"err": msg})
+			return
+		}
+		body, err := io.ReadAll(c.Request.Body)
 		if err != nil {
 			logger.Err(err).Send()
 			c.JSON(http.StatusBadRequest,
 				gin.H{"err": fmt.Errorf("update person: %w", err).Error()})
 			return
 		}
-		metaData := model.PersonalMetaData(reqData)
+		var patch model.PersonPatch
+		if mediaType == mimeMergePatch {
+			patch, err = model.NewMergePatch(body)
+		} else {
+			var reqData updatePersonRequest
+			if err = json.Unmarshal(body, &reqData); err == nil {
+				patch = reqData.patch()
+			}
+		}
+		if err == nil {
+			err = patch.Validate()
+		}
+		if err != nil {
+			logger.Err(err).Send()
+			c.JSON(http.StatusBadRequest,
+				gin.H{"err": fmt.Errorf("update person: %w", err).Error()})
+			return
+		}
+		version, err := ifMatchVersion(c)
+		if err != nil {
+			logger.Err(err).Send()
+			c.JSON(http.StatusBadRequest,
+				gin.H{"err": err.Error()})
+			return
+		}
+		reenrich, err := reenrichQuery(c)
+		if err != nil {
+			logger.Err(err).Send()
+			c.JSON(http.StatusBadRequest,
+				gin.H{"err": err.Error()})
+			return
+		}
 		logger.UpdateContext(func(c zerolog.Context) zerolog.Context {
-			return c.Object("param", metaData)
+			return c.Object("param", patch).
+				Int64("if-match", version).
+				Bool("reenrich", reenrich)
 		})
 		logger.Info().Msg(">> update person")
 
-		err = updater.Update(c.Request.Context(), id, metaData)
+		newVersion, recomputed, err := updater.Update(c.Request.Context(), id, patch, version, reenrich)
 		if err != nil {
 			logger.Err(err).Send()
-			c.JSON(http.StatusInternalServerError,
+			c.JSON(errStatus(err),
 				gin.H{"err": fmt.Errorf("update person: %w", err).Error()})
 			return
 		}
 		logger.Info().Str("status", "ok").Msg("<< update person")
-		c.Status(http.StatusOK)
+		c.Header("ETag", etag(newVersion))
+		c.JSON(http.StatusOK, updatePersonResponse{Recomputed: recomputed})
+	}
+}
+
+type replacePersonRequest struct {
+	Name       string
+	Surname    string
+	Patronymic string
+	Nation     string
+	Gender     string
+	Age        int
+}
+
+// replacePerson replaces all the person fields, the missing metadata is cleared.
+// With reenrich=true the missing metadata of the renamed person is recomputed instead.
+func replacePerson(replacer personReplacer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+
+		logger := zerologx.Get().With().Ctx(c.Request.Context()).Logger()
+		logger.UpdateContext(func(c zerolog.Context) zerolog.Context {
+			return c.Str("port", "http").
+				Str("op", "replace person").
+				Str("param", id)
+		})
+
+		if len(id) == 0 {
+			msg := "invalid value for id: empty"
+			logger.Error().Msg(msg)
+			c.JSON(http.StatusBadRequest,
+				gin.H{"err": msg})
+			return
+		}
+
+		var reqData replacePersonRequest
+		err := c.ShouldBindJSON(&reqData)
+		if err != nil {
+			logger.Err(err).Send()
+			c.JSON(http.StatusBadRequest,
+				gin.H{"err": fmt.Errorf("replace person: %w", err).Error()})
+			return
+		}
+		fio, err := model.NewFIO(reqData.Name, reqData.Surname, reqData.Patronymic)
+		if err == nil && reqData.Age < 0 {
+			err = fmt.Errorf("negative age: %d", reqData.Age)
+		}
+		if err != nil {
+			logger.Err(err).Send()
+			c.JSON(http.StatusBadRequest,
+				gin.H{"err": fmt.Errorf("replace person: %w", err).Error()})
+			return
+		}
+		meta := model.PersonalMetaData{
+			Nation: reqData.Nation,
+			Gender: reqData.Gender,
+			Age:    reqData.Age,
+		}
+		version, err := ifMatchVersion(c)
+		if err != nil {
+			logger.Err(err).Send()
+			c.JSON(http.StatusBadRequest,
+				gin.H{"err": err.Error()})
+			return
+		}
+		reenrich, err := reenrichQuery(c)
+		if err != nil {
+			logger.Err(err).Send()
+			c.JSON(http.StatusBadRequest,
+				gin.H{"err": err.Error()})
+			return
+		}
+		logger.UpdateContext(func(c zerolog.Context) zerolog.Context {
+			return c.Object("fio", fio).
+				Object("meta", meta).
+				Int64("if-match", version).
+				Bool("reenrich", reenrich)
+		})
+		logger.Info().Msg(">> replace person")
+
+		newVersion, recomputed, err := replacer.Replace(c.Request.Context(), id, fio, meta, version, reenrich)
+		if err != nil {
+			logger.Err(err).Send()
+			c.JSON(errStatus(err),
+				gin.H{"err": fmt.Errorf("replace person: %w", err).Error()})
+			return
+		}
+		logger.Info().Str("status", "ok").Msg("<< replace person")
+		c.Header("ETag", etag(newVersion))
+		c.JSON(http.StatusOK, updatePersonResponse{Recomputed: recomputed})
 	}
 }
 
@@ -270,10 +918,18 @@ func deletePerson(deleter personDeleter) gin.HandlerFunc {
 			return
 		}
 
-		err := deleter.Delete(c.Request.Context(), id)
+		version, err := ifMatchVersion(c)
 		if err != nil {
 			logger.Err(err).Send()
-			c.JSON(http.StatusInternalServerError,
+			c.JSON(http.StatusBadRequest,
+				gin.H{"err": err.Error()})
+			return
+		}
+
+		err = deleter.Delete(c.Request.Context(), id, version)
+		if err != nil {
+			logger.Err(err).Send()
+			c.JSON(errStatus(err),
 				gin.H{"err": fmt.Errorf("delete person: %w", err).Error()})
 			return
 		}
@@ -281,3 +937,33 @@ func deletePerson(deleter personDeleter) gin.HandlerFunc {
 		c.Status(http.StatusOK)
 	}
 }
+
+func restorePerson(restorer personRestorer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+		logger := zerologx.Get().With().Ctx(c.Request.Context()).Logger()
+		logger.UpdateContext(func(c zerolog.Context) zerolog.Context {
+			return c.Str("port", "http").
+				Str("op", "restore person").
+				Str("param", id)
+		})
+		logger.Info().Msg(">> restore person")
+
+		if len(id) == 0 {
+			logger.Error().Msg("invalid value for id: empty")
+			c.JSON(http.StatusBadRequest,
+				gin.H{"err": "invalid value for id: empty"})
+			return
+		}
+
+		err := restorer.Restore(c.Request.Context(), id)
+		if err != nil {
+			logger.Err(err).Send()
+			c.JSON(errStatus(err),
+				gin.H{"err": fmt.Errorf("restore person: %w", err).Error()})
+			return
+		}
+		logger.Info().Str("status", "ok").Msg("<< restore person")
+		c.Status(http.StatusOK)
+	}
+}
@@ -0,0 +1,168 @@
+package ports
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alukart32/effective-mobile-test-task/internal/person/model"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type updaterMock struct {
+	UpdateFn func(context.Context, string, model.PersonPatch, int64, bool) (int64, []model.MetaAttr, error)
+}
+
+func (m *updaterMock) Update(
+	ctx context.Context,
+	id string,
+	patch model.PersonPatch,
+	expectedVersion int64,
+	reenrich bool,
+) (int64, []model.MetaAttr, error) {
+	return m.UpdateFn(ctx, id, patch, expectedVersion, reenrich)
+}
+
+type replacerMock struct {
+	ReplaceFn func(context.Context, string, model.FIO, model.PersonalMetaData, int64, bool) (int64, []model.MetaAttr, error)
+}
+
+func (m *replacerMock) Replace(
+	ctx context.Context,
+	id string,
+	fio model.FIO,
+	meta model.PersonalMetaData,
+	expectedVersion int64,
+	reenrich bool,
+) (int64, []model.MetaAttr, error) {
+	return m.ReplaceFn(ctx, id, fio, meta, expectedVersion, reenrich)
+}
+
+func TestUpdatePerson(t *testing.T) {
+	str := func(v string) *string { return &v }
+	num := func(v int) *int { return &v }
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		status      int
+		want        model.PersonPatch
+	}{
+		{
+			name:        "Plain JSON, zero values kept",
+			contentType: "application/json",
+			body:        `{"id": "1", "name": "Petr", "gender": "", "age": 0, "nation": "RU"}`,
+			status:      http.StatusOK,
+			want:        model.PersonPatch{Name: str("Petr"), Nation: str("RU")},
+		},
+		{
+			name:        "Plain JSON with charset, age set",
+			contentType: "application/json; charset=utf-8",
+			body:        `{"Age": 30}`,
+			status:      http.StatusOK,
+			want:        model.PersonPatch{Age: num(30)},
+		},
+		{
+			name:        "Merge patch, null and zero clear",
+			contentType: "application/merge-patch+json",
+			body:        `{"name": "Petr", "gender": null, "age": 0}`,
+			status:      http.StatusOK,
+			want:        model.PersonPatch{Name: str("Petr"), Gender: str(""), Age: num(0)},
+		},
+		{
+			name:        "Merge patch with unknown field, bad request",
+			contentType: "application/merge-patch+json",
+			body:        `{"id": "1", "name": "Petr"}`,
+			status:      http.StatusBadRequest,
+		},
+		{
+			name:        "Plain JSON with invalid name, bad request",
+			contentType: "application/json",
+			body:        `{"name": "Petr1"}`,
+			status:      http.StatusBadRequest,
+		},
+		{
+			name:        "Unsupported content type, error",
+			contentType: "text/plain",
+			body:        `{"name": "Petr"}`,
+			status:      http.StatusUnsupportedMediaType,
+		},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got model.PersonPatch
+			router := gin.New()
+			router.PATCH("/persons/:id", updatePerson(&updaterMock{
+				UpdateFn: func(ctx context.Context, id string, patch model.PersonPatch, v int64, r bool) (int64, []model.MetaAttr, error) {
+					got = patch
+					return 2, nil, nil
+				},
+			}))
+
+			req := httptest.NewRequest(http.MethodPatch, "/persons/1", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.status, rec.Code, rec.Body.String())
+			if tt.status == http.StatusOK {
+				assert.Equal(t, tt.want, got)
+				assert.Equal(t, `"2"`, rec.Header().Get("ETag"))
+			}
+		})
+	}
+}
+
+func TestReplacePerson(t *testing.T) {
+	tests := []struct {
+		name    string
+		ifMatch string
+		err     error
+		status  int
+		etag    string
+	}{
+		{
+			name:    "Replaced, new version in ETag",
+			ifMatch: `"3"`,
+			status:  http.StatusOK,
+			etag:    `"4"`,
+		},
+		{
+			name:    "Version mismatch, no ETag",
+			ifMatch: `"2"`,
+			err:     model.ErrVersionMismatch,
+			status:  http.StatusPreconditionFailed,
+		},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.PUT("/persons/:id", replacePerson(&replacerMock{
+				ReplaceFn: func(ctx context.Context, id string, fio model.FIO, meta model.PersonalMetaData,
+					v int64, r bool) (int64, []model.MetaAttr, error) {
+					if tt.err != nil {
+						return 0, nil, tt.err
+					}
+					return v + 1, nil, nil
+				},
+			}))
+
+			body := `{"name": "Petr", "surname": "Ivanov", "age": 30}`
+			req := httptest.NewRequest(http.MethodPut, "/persons/1", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("If-Match", tt.ifMatch)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.status, rec.Code, rec.Body.String())
+			assert.Equal(t, tt.etag, rec.Header().Get("ETag"))
+		})
+	}
+}
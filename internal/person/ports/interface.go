@@ -10,26 +10,78 @@ type personCreator interface {
 	CreateFrom(ctx context.Context, fio model.FIO) (string, error)
 }
 
+type personBatchCreator interface {
+	CreateFromBatch(ctx context.Context, fios []model.FIO) ([]model.BatchResult, error)
+}
+
 type personFinder interface {
-	FindById(ctx context.Context, id string) (model.Person, error)
+	FindById(ctx context.Context, id string, includeDeleted bool) (model.Person, error)
 }
 
 type personCollector interface {
 	Collect(ctx context.Context, filter model.PersonFilter, limit, offset int) ([]model.Person, error)
 }
 
+type personExporter interface {
+	Export(ctx context.Context, filter model.PersonFilter, fn func(model.Person) error) error
+}
+
+type personStatsCollector interface {
+	Stats(ctx context.Context, filter model.PersonFilter, buckets model.AgeBuckets) (model.PersonStats, error)
+}
+
+type personHistorian interface {
+	History(ctx context.Context, id string, limit, offset int) ([]model.PersonChange, error)
+}
+
 type personUpdater interface {
-	Update(ctx context.Context, id string, meta model.PersonalMetaData) error
+	Update(
+		ctx context.Context,
+		id string,
+		patch model.PersonPatch,
+		expectedVersion int64,
+		reenrich bool,
+	) (int64, []model.MetaAttr, error)
+}
+
+type personReplacer interface {
+	Replace(
+		ctx context.Context,
+		id string,
+		fio model.FIO,
+		meta model.PersonalMetaData,
+		expectedVersion int64,
+		reenrich bool,
+	) (int64, []model.MetaAttr, error)
 }
 
 type personDeleter interface {
-	Delete(ctx context.Context, id string) error
+	Delete(ctx context.Context, id string, expectedVersion int64) error
+}
+
+type personRestorer interface {
+	Restore(ctx context.Context, id string) error
 }
 
 type personManager interface {
 	personCreator
+	personBatchCreator
 	personFinder
 	personCollector
+	personExporter
+	personStatsCollector
+	personHistorian
 	personUpdater
+	personReplacer
 	personDeleter
+	personRestorer
+}
+
+type webhookManager interface {
+	Register(ctx context.Context, w model.Webhook) error
+	FindById(ctx context.Context, id string) (model.Webhook, error)
+	List(ctx context.Context, limit, offset int) ([]model.Webhook, error)
+	Delete(ctx context.Context, id string) error
+	Enable(ctx context.Context, id string) error
+	Deliveries(ctx context.Context, webhookId string, limit, offset int) ([]model.WebhookDelivery, error)
 }
@@ -3,21 +3,30 @@ package ports
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
+	"hash/fnv"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/alukart32/effective-mobile-test-task/internal/person/model"
+	"github.com/alukart32/effective-mobile-test-task/internal/pkg/reqmeta"
 	"github.com/alukart32/effective-mobile-test-task/internal/pkg/zerologx"
+	"github.com/rs/xid"
 	"github.com/rs/zerolog"
 	kafka "github.com/segmentio/kafka-go"
 )
 
+// _tenantHeader is the Kafka message header of the FIO tenant, the message
+// without it is of the default tenant.
+const _tenantHeader = "tenant-id"
+
 type fioMsg struct {
 	Name       string
 	Surname    string
 	Patronymic string
+	// Tenant is the _tenantHeader value.
+	Tenant string `json:"-"`
 }
 
 func (f fioMsg) MarshalZerologObject(e *zerolog.Event) {
@@ -41,97 +50,310 @@ func (f fioErrorMsg) MarshalZerologObject(e *zerolog.Event) {
 	e.Object("fio", f.Msg).Str("err", f.Err)
 }
 
+// The orders of handling the FIO messages of KafkaFIOConfig.Order.
+const (
+	// FIOOrderPartition handles the messages of a partition one by one.
+	FIOOrderPartition = "partition"
+	// FIOOrderKey handles the messages with the same key one by one, the
+	// messages without the key are handled in any order.
+	FIOOrderKey = "key"
+	// FIOOrderNone handles the messages in any order.
+	FIOOrderNone = "none"
+)
+
+type KafkaFIOConfig struct {
+	ReadTopic  string
+	ErrorTopic string
+	Brokers    []string
+	// GroupID is the consumer group, the offsets aren't committed and the
+	// reader starts from the first offset without it.
+	GroupID string
+	// Workers is the number of the messages handled concurrently.
+	Workers int
+	// Order is one of FIOOrderPartition, FIOOrderKey, FIOOrderNone, the
+	// default is FIOOrderPartition.
+	Order string
+	// MaxInFlight is the max number of the fetched messages not handled yet,
+	// the fetching waits for the workers when it's reached.
+	MaxInFlight int
+}
+
+// kafkaFIO creates the persons of the FIO messages by the pool of workers.
+// The message is routed to the worker by its partition or key, so the
+// messages of the partition or the key are handled in fetch order. The offset
+// of the message is committed after the messages of its partition up to it
+// are handled: the person is created or the message is sent to the error
+// topic. So the messages are handled at least once.
 type kafkaFIO struct {
-	reader      *kafka.Reader
-	errorWriter *kafka.Writer
+	reader      fioReader
+	errorWriter fioErrorWriter
+	// commit is false without the consumer group.
+	commit bool
+	order  string
 
-	msgs   chan fioMsg
-	errors chan fioErrorMsg
-	done   chan struct{}
+	// queues are the messages of the workers.
+	queues []chan *fioInFlight
+	// next is the worker of the next message without the order.
+	next int
+	// inFlight limits the fetched messages not handled yet.
+	inFlight chan struct{}
+	offsets  *fioOffsets
+	// fetched is closed when Fetch is stopped.
+	fetched chan struct{}
+	// stop cancels the ctx of Handle and Fetch.
+	stop context.CancelFunc
 
 	personCreator personCreator
 }
 
-func KafkaFIO(
-	ctx context.Context,
-	readTopic string,
-	errorTopic string,
-	brokers []string,
-	bufferSize int,
-	creator personCreator) (*kafkaFIO, error) {
-	if len(readTopic) == 0 {
+// fioReader reads the FIO messages, it's kafka.Reader.
+type fioReader interface {
+	FetchMessage(ctx context.Context) (kafka.Message, error)
+	CommitMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// fioErrorWriter writes the error topic messages, it's kafka.Writer.
+type fioErrorWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+func KafkaFIO(ctx context.Context, cfg KafkaFIOConfig, creator personCreator) (*kafkaFIO, error) {
+	if len(cfg.ReadTopic) == 0 {
 		return nil, fmt.Errorf("empty read topic")
 	}
-	if len(errorTopic) == 0 {
+	if len(cfg.ErrorTopic) == 0 {
 		return nil, fmt.Errorf("empty error topic")
 	}
-	if len(brokers) == 0 {
+	if len(cfg.Brokers) == 0 {
 		return nil, fmt.Errorf("empty brokers list")
 	}
-	if bufferSize <= 0 {
-		bufferSize = 1
+	switch cfg.Order {
+	case "":
+		cfg.Order = FIOOrderPartition
+	case FIOOrderPartition, FIOOrderKey, FIOOrderNone:
+	default:
+		return nil, fmt.Errorf("unknown order %q", cfg.Order)
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = 1
+	}
+	if cfg.MaxInFlight <= 0 {
+		cfg.MaxInFlight = 1
 	}
 	if creator == nil {
 		return nil, fmt.Errorf("person creator is nil")
 	}
 
 	logger := zerologx.Get().With().Logger()
-	handler := kafkaFIO{
-		personCreator: creator,
-		reader: kafka.NewReader(kafka.ReaderConfig{
-			Brokers:     brokers,
-			Topic:       readTopic,
-			MaxBytes:    1e6,
-			ErrorLogger: &logger,
+	handler := newKafkaFIO(
+		cfg,
+		kafka.NewReader(kafka.ReaderConfig{
+			Brokers: cfg.Brokers,
+			Topic:   cfg.ReadTopic,
+			GroupID: cfg.GroupID,
+			// The offsets are committed synchronously by CommitMessages.
+			CommitInterval: 0,
+			StartOffset:    kafka.FirstOffset,
+			MaxBytes:       1e6,
+			ErrorLogger:    &logger,
 		}),
-		errorWriter: &kafka.Writer{
-			Addr:                   kafka.TCP(brokers...),
-			Topic:                  errorTopic,
+		&kafka.Writer{
+			Addr:                   kafka.TCP(cfg.Brokers...),
+			Topic:                  cfg.ErrorTopic,
 			Balancer:               &kafka.LeastBytes{},
 			AllowAutoTopicCreation: false,
 		},
-		msgs:   make(chan fioMsg, bufferSize),
-		errors: make(chan fioErrorMsg, 1),
-		done:   make(chan struct{}, 1),
+		creator,
+	)
+	handler.run(ctx)
+
+	return handler, nil
+}
+
+// newKafkaFIO returns the handler of the validated cfg, it isn't started.
+func newKafkaFIO(
+	cfg KafkaFIOConfig,
+	reader fioReader,
+	errorWriter fioErrorWriter,
+	creator personCreator) *kafkaFIO {
+	handler := kafkaFIO{
+		personCreator: creator,
+		reader:        reader,
+		errorWriter:   errorWriter,
+		commit:        len(cfg.GroupID) != 0,
+		order:         cfg.Order,
+		queues:        make([]chan *fioInFlight, cfg.Workers),
+		inFlight:      make(chan struct{}, cfg.MaxInFlight),
+		offsets:       newFIOOffsets(),
+		fetched:       make(chan struct{}),
+	}
+	for i := range handler.queues {
+		// The in-flight messages fit any queue, so a busy worker doesn't
+		// block the routing to the others.
+		handler.queues[i] = make(chan *fioInFlight, cfg.MaxInFlight)
 	}
-	go handler.Handle(ctx)
-	go handler.RespondError(ctx)
-	go handler.Fetch(ctx)
+	return &handler
+}
 
-	return &handler, nil
+// run starts the handling, it's stopped when ctx is done or a message isn't
+// handled. The stop goes the way of the shutdown: the fetching is stopped,
+// the offsets of the handled messages are committed and the reader is
+// closed, so its partitions are rebalanced.
+func (h *kafkaFIO) run(ctx context.Context) {
+	ctx, h.stop = context.WithCancel(ctx)
+	go h.Handle(ctx)
+	go h.Fetch(ctx)
 }
 
+// Handle runs the workers and commits the offsets of the handled messages.
+// The reader and the error writer are closed after the workers and Fetch are
+// stopped.
 func (h *kafkaFIO) Handle(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, queue := range h.queues {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			h.work(ctx, queue)
+		}()
+	}
+	stopped := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(stopped)
+	}()
+
+	for {
+		select {
+		case <-h.offsets.notify:
+			h.commitReady(ctx)
+		case <-stopped:
+			// The messages handled before the shutdown.
+			h.commitReady(ctx)
+			<-h.fetched
+			if err := h.reader.Close(); err != nil {
+				log.Fatal("kafka Handle:", err)
+			}
+			if err := h.errorWriter.Close(); err != nil {
+				log.Fatal("kafka Handle:", err)
+			}
+			return
+		}
+	}
+}
+
+// work handles the messages of queue until ctx is done or queue is closed.
+func (h *kafkaFIO) work(ctx context.Context, queue <-chan *fioInFlight) {
+	logger := zerologx.Get().
+		With().
+		Str("port", "kafka").
+		Logger()
+
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case msg, ok := <-h.msgs:
+		case f, ok := <-queue:
 			if !ok {
 				return
 			}
-
-			fio, err := model.NewFIO(msg.Name, msg.Surname, msg.Patronymic)
+			err := h.handle(ctx, f.msg)
+			<-h.inFlight
 			if err != nil {
-				h.errors <- fioErrorMsg{Msg: msg, Err: err.Error()}
-				continue
+				// The offset isn't committed, the message is handled again
+				// after the restart or the rebalance.
+				if ctx.Err() == nil {
+					logger.Error().Err(err).Int("partition", f.msg.Partition).Int64("offset", f.msg.Offset).
+						Msg("stop handling")
+				}
+				h.stop()
+				return
 			}
+			h.offsets.done(f)
+		}
+	}
+}
 
-			_, err = h.personCreator.CreateFrom(ctx, fio)
-			if err != nil {
-				h.errors <- fioErrorMsg{Msg: msg, Err: err.Error()}
+// commitReady commits the offsets of the handled messages, even when ctx is
+// done. The message isn't committed e.g. after the rebalance, then it's
+// handled again by the new owner of the partition.
+func (h *kafkaFIO) commitReady(ctx context.Context) {
+	msgs := h.offsets.take()
+	if !h.commit || len(msgs) == 0 {
+		return
+	}
+	commitCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	if err := h.reader.CommitMessages(commitCtx, msgs...); err != nil {
+		zerologx.Get().Err(err).Str("port", "kafka").Str("op", "commit msg").Send()
+	}
+}
+
+// queue returns the queue of the worker of m by the order.
+func (h *kafkaFIO) queue(m kafka.Message) chan<- *fioInFlight {
+	var i int
+	switch {
+	case h.order == FIOOrderPartition:
+		i = m.Partition % len(h.queues)
+	case h.order == FIOOrderKey && len(m.Key) != 0:
+		hash := fnv.New32a()
+		hash.Write(m.Key)
+		i = int(hash.Sum32() % uint32(len(h.queues)))
+	default:
+		i = h.next
+		h.next = (h.next + 1) % len(h.queues)
+	}
+	return h.queues[i]
+}
+
+// handle creates the person of m or sends m to the error topic. The error is
+// returned when m is neither, then it mustn't be committed.
+func (h *kafkaFIO) handle(ctx context.Context, m kafka.Message) error {
+	var msg fioMsg
+	err := json.Unmarshal(m.Value, &msg)
+	if err == nil {
+		for _, header := range m.Headers {
+			if header.Key == _tenantHeader {
+				msg.Tenant = string(header.Value)
 			}
 		}
+		err = h.create(ctx, msg)
+	}
+	if err == nil {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return fmt.Errorf("kafka handle: %w", ctx.Err())
 	}
+	return h.respondError(ctx, fioErrorMsg{Msg: msg, Err: err.Error()})
 }
 
-// Fetch reads msg FIO from kafka topic.
+func (h *kafkaFIO) create(ctx context.Context, msg fioMsg) error {
+	tenant, err := model.ParseTenant(msg.Tenant)
+	if err != nil {
+		return err
+	}
+	fio, err := model.NewFIO(msg.Name, msg.Surname, msg.Patronymic)
+	if err != nil {
+		return err
+	}
+
+	msgCtx := reqmeta.WithCorrelationID(reqmeta.WithSource(ctx, "kafka"), xid.New().String())
+	msgCtx = reqmeta.WithTenant(msgCtx, tenant)
+	_, err = h.personCreator.CreateFrom(msgCtx, fio)
+	return err
+}
+
+// Fetch reads msg FIO from kafka topic and routes them to the workers. It
+// waits while there are too many in-flight messages.
 func (h *kafkaFIO) Fetch(ctx context.Context) {
 	defer func() {
-		close(h.msgs)
-		if err := h.reader.Close(); err != nil {
-			log.Fatal("kafka Fetch:", err)
+		for _, queue := range h.queues {
+			close(queue)
 		}
+		close(h.fetched)
 	}()
 	logger := zerologx.Get().
 		With().
@@ -142,12 +364,14 @@ func (h *kafkaFIO) Fetch(ctx context.Context) {
 		select {
 		case <-ctx.Done():
 			return
-		default:
+		case h.inFlight <- struct{}{}:
 		}
-		m, err := h.reader.ReadMessage(ctx)
+		m, err := h.reader.FetchMessage(ctx)
 		if err != nil {
-			logger.Err(err).Send()
-			break
+			if ctx.Err() == nil {
+				logger.Err(err).Send()
+			}
+			return
 		}
 		logger.Info().Str("op", "fetch msg").Dict("msg",
 			zerolog.Dict().
@@ -159,77 +383,48 @@ func (h *kafkaFIO) Fetch(ctx context.Context) {
 				Str("value", string(m.Value)),
 		).Send()
 
-		var msg fioMsg
-		if err := json.Unmarshal(m.Value, &msg); err != nil {
-			logger.Err(err).Send()
-			h.errors <- fioErrorMsg{Err: err.Error()}
-		} else {
-			h.msgs <- msg
-		}
+		// The queue isn't full, it fits the in-flight messages.
+		h.queue(m) <- h.offsets.add(m)
 	}
 }
 
-func (h *kafkaFIO) RespondError(ctx context.Context) {
-	const retries = 3
-
-	defer func() {
-		if err := h.errorWriter.Close(); err != nil {
-			log.Fatal("kafka RespondError:", err)
-		}
-	}()
+// respondError sends msg to the error topic. It retries until msg is sent or
+// ctx is done, so the failed message isn't committed without it.
+func (h *kafkaFIO) respondError(ctx context.Context, msg fioErrorMsg) error {
+	const (
+		minBackoff = 250 * time.Millisecond
+		maxBackoff = 5 * time.Second
+	)
 	logger := zerologx.Get().
 		With().
 		Str("port", "kafka").
 		Logger()
+	logger.Info().
+		Str("op", "send msg").
+		Object("msg", msg).
+		Send()
 
-	for {
+	b, err := json.Marshal(&msg)
+	if err != nil {
+		return fmt.Errorf("kafka respondError: %w", err)
+	}
+	messages := []kafka.Message{
+		{
+			Value: b,
+		},
+	}
+	for backoff := minBackoff; ; backoff = min(2*backoff, maxBackoff) {
+		writeCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		err = h.errorWriter.WriteMessages(writeCtx, messages...)
+		cancel()
+		if err == nil {
+			return nil
+		}
+		logger.Err(err).Dur("backoff", backoff).Send()
 		select {
 		case <-ctx.Done():
-			return
-		case msg, ok := <-h.errors:
-			if !ok {
-				return
-			}
-			logger.Info().
-				Str("op", "send msg").
-				Object("msg", msg).
-				Send()
-
-			var (
-				b   []byte
-				err error
-			)
-			if b, err = json.Marshal(&msg); err != nil {
-				logger.Err(err).Send()
-				continue
-			}
-
-			messages := []kafka.Message{
-				{
-					Value: b,
-				},
-			}
-			for i := 0; i < retries; i++ {
-				select {
-				case <-h.done:
-					return
-				default:
-				}
-
-				writeCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
-				defer cancel()
-				err = h.errorWriter.WriteMessages(writeCtx, messages...)
-				if errors.Is(err, kafka.LeaderNotAvailable) ||
-					errors.Is(err, context.DeadlineExceeded) {
-					logger.Info().Err(err).Send()
-					<-time.After(time.Millisecond * 250)
-					continue
-				}
-				if err != nil {
-					logger.Err(err).Send()
-				}
-				break
-			}
+			return fmt.Errorf("kafka respondError: %w", err)
+		case <-time.After(backoff):
 		}
 	}
 }
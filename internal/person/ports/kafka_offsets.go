@@ -0,0 +1,88 @@
+package ports
+
+import (
+	"sync"
+
+	kafka "github.com/segmentio/kafka-go"
+)
+
+// fioInFlight is the fetched FIO message that isn't handled yet.
+type fioInFlight struct {
+	msg  kafka.Message
+	done bool
+}
+
+// fioOffsets tracks the in-flight messages of the read topic partitions. The
+// messages are handled concurrently, so the message is ready to be committed
+// after it and all the messages fetched before it from its partition are
+// handled.
+type fioOffsets struct {
+	mu sync.Mutex
+	// pending are the in-flight messages of the partitions in fetch order.
+	pending map[int][]*fioInFlight
+	// ready are the last messages of the partitions ready to be committed.
+	ready map[int]kafka.Message
+	// notify is signaled when ready is changed.
+	notify chan struct{}
+}
+
+func newFIOOffsets() *fioOffsets {
+	return &fioOffsets{
+		pending: make(map[int][]*fioInFlight),
+		ready:   make(map[int]kafka.Message),
+		notify:  make(chan struct{}, 1),
+	}
+}
+
+// add tracks the fetched msg, the msgs of a partition must be added in fetch
+// order.
+func (o *fioOffsets) add(msg kafka.Message) *fioInFlight {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	f := &fioInFlight{msg: msg}
+	o.pending[msg.Partition] = append(o.pending[msg.Partition], f)
+	return f
+}
+
+// done marks f as handled.
+func (o *fioOffsets) done(f *fioInFlight) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	f.done = true
+	partition := f.msg.Partition
+	pending := o.pending[partition]
+	i := 0
+	for i < len(pending) && pending[i].done {
+		i++
+	}
+	if i == 0 {
+		return
+	}
+	o.ready[partition] = pending[i-1].msg
+	if i == len(pending) {
+		delete(o.pending, partition)
+	} else {
+		o.pending[partition] = pending[i:]
+	}
+
+	select {
+	case o.notify <- struct{}{}:
+	default:
+	}
+}
+
+// take returns and forgets the messages ready to be committed, one per
+// partition.
+func (o *fioOffsets) take() []kafka.Message {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	msgs := make([]kafka.Message, 0, len(o.ready))
+	for partition, msg := range o.ready {
+		msgs = append(msgs, msg)
+		delete(o.ready, partition)
+	}
+	return msgs
+}
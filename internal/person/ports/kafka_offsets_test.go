@@ -0,0 +1,90 @@
+package ports
+
+import (
+	"sort"
+	"testing"
+
+	kafka "github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFIOOffsets_Done(t *testing.T) {
+	type msg struct {
+		partition int
+		offset    int64
+	}
+	tests := []struct {
+		name    string
+		fetched []msg
+		// done are the indexes of fetched in the order of handling.
+		done []int
+		want []msg
+	}{
+		{
+			name:    "In order, last committed",
+			fetched: []msg{{0, 1}, {0, 2}, {0, 3}},
+			done:    []int{0, 1, 2},
+			want:    []msg{{0, 3}},
+		},
+		{
+			name:    "Earlier pending, not committed",
+			fetched: []msg{{0, 1}, {0, 2}, {0, 3}},
+			done:    []int{1, 2},
+			want:    []msg{},
+		},
+		{
+			name:    "Earlier done later, last committed",
+			fetched: []msg{{0, 1}, {0, 2}, {0, 3}},
+			done:    []int{2, 1, 0},
+			want:    []msg{{0, 3}},
+		},
+		{
+			name:    "Gap pending, committed up to gap",
+			fetched: []msg{{0, 1}, {0, 2}, {0, 3}, {0, 4}},
+			done:    []int{0, 3, 1},
+			want:    []msg{{0, 2}},
+		},
+		{
+			name:    "Partitions, independent",
+			fetched: []msg{{0, 1}, {1, 1}, {0, 2}, {1, 2}},
+			done:    []int{1, 2},
+			want:    []msg{{1, 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			offsets := newFIOOffsets()
+			inFlight := make([]*fioInFlight, len(tt.fetched))
+			for i, m := range tt.fetched {
+				inFlight[i] = offsets.add(kafka.Message{Partition: m.partition, Offset: m.offset})
+			}
+			for _, i := range tt.done {
+				offsets.done(inFlight[i])
+			}
+
+			got := []msg{}
+			for _, m := range offsets.take() {
+				got = append(got, msg{m.Partition, m.Offset})
+			}
+			sort.Slice(got, func(i, j int) bool { return got[i].partition < got[j].partition })
+			assert.Equal(t, tt.want, got)
+			assert.Empty(t, offsets.take())
+		})
+	}
+}
+
+func TestFIOOffsets_Take(t *testing.T) {
+	offsets := newFIOOffsets()
+	first := offsets.add(kafka.Message{Offset: 1})
+	second := offsets.add(kafka.Message{Offset: 2})
+
+	offsets.done(first)
+	assert.Equal(t, []kafka.Message{{Offset: 1}}, offsets.take())
+	offsets.done(second)
+	assert.Equal(t, []kafka.Message{{Offset: 2}}, offsets.take())
+	select {
+	case <-offsets.notify:
+	default:
+		t.Error("no notification")
+	}
+}
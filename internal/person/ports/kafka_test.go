@@ -0,0 +1,239 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alukart32/effective-mobile-test-task/internal/person/model"
+	kafka "github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type readerMock struct {
+	msgs   chan kafka.Message
+	closed chan struct{}
+
+	mu      sync.Mutex
+	commits []int64
+}
+
+func newReaderMock() *readerMock {
+	return &readerMock{msgs: make(chan kafka.Message, 10), closed: make(chan struct{})}
+}
+
+func (r *readerMock) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	select {
+	case <-ctx.Done():
+		return kafka.Message{}, ctx.Err()
+	case m := <-r.msgs:
+		return m, nil
+	}
+}
+
+func (r *readerMock) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, m := range msgs {
+		r.commits = append(r.commits, m.Offset)
+	}
+	return nil
+}
+
+func (r *readerMock) Close() error {
+	close(r.closed)
+	return nil
+}
+
+func (r *readerMock) committed() []int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]int64{}, r.commits...)
+}
+
+type errorWriterMock struct {
+	WriteMessagesFn func(context.Context, ...kafka.Message) error
+}
+
+func (m *errorWriterMock) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	if m != nil && m.WriteMessagesFn != nil {
+		return m.WriteMessagesFn(ctx, msgs...)
+	}
+	return errors.New("can't write messages")
+}
+
+func (m *errorWriterMock) Close() error {
+	return nil
+}
+
+type creatorMock struct {
+	CreateFromFn func(context.Context, model.FIO) (string, error)
+}
+
+func (m *creatorMock) CreateFrom(ctx context.Context, fio model.FIO) (string, error) {
+	if m != nil && m.CreateFromFn != nil {
+		return m.CreateFromFn(ctx, fio)
+	}
+	return "", errors.New("can't create person")
+}
+
+// startKafkaFIO runs the handler of reader, it's stopped by the returned cancel
+// or after the test.
+func startKafkaFIO(t *testing.T, cfg KafkaFIOConfig, reader *readerMock,
+	writer *errorWriterMock, creator *creatorMock) context.CancelFunc {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	newKafkaFIO(cfg, reader, writer, creator).run(ctx)
+	t.Cleanup(func() {
+		cancel()
+		<-reader.closed
+	})
+	return cancel
+}
+
+func TestKafkaFIO_Commit(t *testing.T) {
+	cfg := KafkaFIOConfig{GroupID: "person", Workers: 1, Order: FIOOrderPartition, MaxInFlight: 1}
+	fio := []byte(`{"Name": "Ivan", "Surname": "Ivanov"}`)
+
+	t.Run("Created, committed after CreateFrom", func(t *testing.T) {
+		called, release := make(chan struct{}), make(chan struct{})
+		reader := newReaderMock()
+		startKafkaFIO(t, cfg, reader, nil, &creatorMock{
+			CreateFromFn: func(ctx context.Context, f model.FIO) (string, error) {
+				close(called)
+				<-release
+				return "id", nil
+			},
+		})
+
+		reader.msgs <- kafka.Message{Offset: 1, Value: fio}
+		<-called
+		assert.Empty(t, reader.committed())
+		close(release)
+		assert.Eventually(t, func() bool {
+			return assert.ObjectsAreEqual([]int64{1}, reader.committed())
+		}, time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("Failed, committed after dead letter written", func(t *testing.T) {
+		writes, release := make(chan struct{}, 2), make(chan struct{})
+		reader := newReaderMock()
+		var attempts int
+		startKafkaFIO(t, cfg, reader, &errorWriterMock{
+			WriteMessagesFn: func(ctx context.Context, msgs ...kafka.Message) error {
+				attempts++
+				writes <- struct{}{}
+				if attempts == 1 {
+					return errors.New("leader not available")
+				}
+				<-release
+				return nil
+			},
+		}, &creatorMock{})
+
+		reader.msgs <- kafka.Message{Offset: 1, Value: fio}
+		<-writes
+		<-writes
+		assert.Empty(t, reader.committed())
+		close(release)
+		assert.Eventually(t, func() bool {
+			return assert.ObjectsAreEqual([]int64{1}, reader.committed())
+		}, time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("Invalid, committed after dead letter written", func(t *testing.T) {
+		reader := newReaderMock()
+		written := make(chan struct{}, 1)
+		startKafkaFIO(t, cfg, reader, &errorWriterMock{
+			WriteMessagesFn: func(ctx context.Context, msgs ...kafka.Message) error {
+				written <- struct{}{}
+				return nil
+			},
+		}, &creatorMock{})
+
+		reader.msgs <- kafka.Message{Offset: 1, Value: []byte("not json")}
+		<-written
+		assert.Eventually(t, func() bool {
+			return assert.ObjectsAreEqual([]int64{1}, reader.committed())
+		}, time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("Canceled, not committed", func(t *testing.T) {
+		called := make(chan struct{})
+		reader := newReaderMock()
+		var written bool
+		cancel := startKafkaFIO(t, cfg, reader, &errorWriterMock{
+			WriteMessagesFn: func(ctx context.Context, msgs ...kafka.Message) error {
+				written = true
+				return nil
+			},
+		}, &creatorMock{
+			CreateFromFn: func(ctx context.Context, f model.FIO) (string, error) {
+				close(called)
+				<-ctx.Done()
+				return "", ctx.Err()
+			},
+		})
+
+		reader.msgs <- kafka.Message{Offset: 1, Value: fio}
+		<-called
+		cancel()
+		<-reader.closed
+		assert.Empty(t, reader.committed())
+		assert.False(t, written)
+	})
+}
+
+func TestKafkaFIO_NoGroup(t *testing.T) {
+	cfg := KafkaFIOConfig{Workers: 1, Order: FIOOrderPartition, MaxInFlight: 1}
+	created := make(chan struct{}, 1)
+	reader := newReaderMock()
+	cancel := startKafkaFIO(t, cfg, reader, nil, &creatorMock{
+		CreateFromFn: func(ctx context.Context, f model.FIO) (string, error) {
+			created <- struct{}{}
+			return "id", nil
+		},
+	})
+
+	reader.msgs <- kafka.Message{Offset: 1, Value: []byte(`{"Name": "Ivan", "Surname": "Ivanov"}`)}
+	<-created
+	cancel()
+	<-reader.closed
+	require.Empty(t, reader.committed())
+}
+
+func TestKafkaFIO_Workers(t *testing.T) {
+	// The keys are routed to the different workers.
+	cfg := KafkaFIOConfig{GroupID: "person", Workers: 2, Order: FIOOrderKey, MaxInFlight: 4}
+	blocked, release := make(chan struct{}), make(chan struct{})
+	created := make(chan string, 2)
+	reader := newReaderMock()
+	startKafkaFIO(t, cfg, reader, nil, &creatorMock{
+		CreateFromFn: func(ctx context.Context, f model.FIO) (string, error) {
+			if f.Name == "Slow" {
+				close(blocked)
+				<-release
+			}
+			created <- f.Name
+			return "id", nil
+		},
+	})
+
+	reader.msgs <- kafka.Message{Offset: 1, Key: []byte("a"), Value: []byte(`{"Name": "Slow", "Surname": "Ivanov"}`)}
+	<-blocked
+	reader.msgs <- kafka.Message{Offset: 2, Key: []byte("b"), Value: []byte(`{"Name": "Fast", "Surname": "Ivanov"}`)}
+	assert.Equal(t, "Fast", <-created)
+	// The earlier message of the partition isn't handled yet.
+	assert.Empty(t, reader.committed())
+
+	close(release)
+	assert.Equal(t, "Slow", <-created)
+	assert.Eventually(t, func() bool {
+		committed := reader.committed()
+		return len(committed) > 0 && committed[len(committed)-1] == 2
+	}, time.Second, 5*time.Millisecond)
+	assert.NotContains(t, reader.committed(), int64(1))
+}
@@ -0,0 +1,91 @@
+package ports
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/alukart32/effective-mobile-test-task/internal/person/model"
+	"github.com/alukart32/effective-mobile-test-task/internal/pkg/reqmeta"
+	"github.com/gin-gonic/gin"
+)
+
+// TenantHeader is the request header with the tenant ID, it's used unless
+// the tenants are authenticated by the tokens.
+const TenantHeader = "X-Tenant-ID"
+
+var errUnauthorized = errors.New("unknown or missing bearer token")
+
+// WithTenant scopes the request context to the tenant. If the tokens are
+// set, the tenant is the one of the request bearer token and the requests
+// without the known token are unauthorized. The tenant is taken from
+// TenantHeader otherwise, the request without it is of the default tenant.
+// The actor of the request with the token is the token one, see tokenActor.
+// The tokens map the tokens to the tenants.
+func WithTenant(tokens map[string]string) (gin.HandlerFunc, error) {
+	for token, tenant := range tokens {
+		if len(token) == 0 {
+			return nil, fmt.Errorf("init tenant middleware: empty token")
+		}
+		if _, err := model.ParseTenant(tenant); err != nil || len(tenant) == 0 {
+			return nil, fmt.Errorf("init tenant middleware: invalid tenant: %q", tenant)
+		}
+	}
+
+	return func(c *gin.Context) {
+		tenant, actor, status, err := requestTenant(c, tokens)
+		if err != nil {
+			if status == http.StatusUnauthorized {
+				c.Header("WWW-Authenticate", "Bearer")
+			}
+			c.AbortWithStatusJSON(status, gin.H{"err": fmt.Errorf("tenant: %w", err).Error()})
+			return
+		}
+		ctx := reqmeta.WithTenant(c.Request.Context(), tenant)
+		if len(actor) != 0 {
+			ctx = reqmeta.WithActor(ctx, actor)
+		}
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}, nil
+}
+
+// requestTenant returns the tenant and the token actor of the request or
+// the error status. The actor is empty without the tokens.
+func requestTenant(c *gin.Context, tokens map[string]string) (string, string, int, error) {
+	if len(tokens) == 0 {
+		tenant, err := model.ParseTenant(c.GetHeader(TenantHeader))
+		if err != nil {
+			return "", "", http.StatusBadRequest, err
+		}
+		return tenant, "", 0, nil
+	}
+
+	token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok || len(token) == 0 {
+		return "", "", http.StatusUnauthorized, errUnauthorized
+	}
+	// Every token is compared in constant time, so the timing doesn't
+	// reveal the known ones.
+	var tenant string
+	for known, t := range tokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(known)) == 1 {
+			tenant = t
+		}
+	}
+	if len(tenant) == 0 {
+		return "", "", http.StatusUnauthorized, errUnauthorized
+	}
+	return tenant, tokenActor(token), 0, nil
+}
+
+// tokenActor returns the actor of the bearer token, the prefix of its
+// SHA-256, so the history tells the tokens apart without storing them.
+func tokenActor(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "token:" + hex.EncodeToString(sum[:4])
+}
@@ -0,0 +1,84 @@
+package ports
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alukart32/effective-mobile-test-task/internal/pkg/reqmeta"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithTenant(t *testing.T) {
+	tests := []struct {
+		name          string
+		tokens        map[string]string
+		authorization string
+		tenantHeader  string
+		status        int
+		tenant        string
+		actor         string
+	}{
+		{
+			name:         "No tokens, tenant and client actor of headers",
+			tenantHeader: "acme",
+			status:       http.StatusOK,
+			tenant:       "acme",
+			actor:        "client:alice",
+		},
+		{
+			name:          "Known token, tenant and actor of token",
+			tokens:        map[string]string{"secret": "acme"},
+			authorization: "Bearer secret",
+			tenantHeader:  "other",
+			status:        http.StatusOK,
+			tenant:        "acme",
+			actor:         tokenActor("secret"),
+		},
+		{
+			name:          "Unknown token, unauthorized",
+			tokens:        map[string]string{"secret": "acme"},
+			authorization: "Bearer guess",
+			status:        http.StatusUnauthorized,
+		},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withTenant, err := WithTenant(tt.tokens)
+			require.NoError(t, err)
+
+			var tenant, actor string
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				// The actor header is set like the ginx middleware does.
+				c.Request = c.Request.WithContext(
+					reqmeta.WithActor(c.Request.Context(), reqmeta.ClientActor("alice")))
+			}, withTenant)
+			router.GET("/persons", func(c *gin.Context) {
+				tenant = reqmeta.Tenant(c.Request.Context())
+				actor = reqmeta.Actor(c.Request.Context())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/persons", nil)
+			req.Header.Set("Authorization", tt.authorization)
+			req.Header.Set(TenantHeader, tt.tenantHeader)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.status, rec.Code, rec.Body.String())
+			assert.Equal(t, tt.tenant, tenant)
+			assert.Equal(t, tt.actor, actor)
+		})
+	}
+}
+
+func TestTokenActor(t *testing.T) {
+	actor := tokenActor("secret")
+	assert.Regexp(t, `^token:[0-9a-f]{8}$`, actor)
+	assert.NotContains(t, actor, "secret")
+	assert.NotEqual(t, actor, tokenActor("other"))
+}
@@ -0,0 +1,253 @@
+package ports
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/alukart32/effective-mobile-test-task/internal/person/model"
+	"github.com/alukart32/effective-mobile-test-task/internal/pkg/zerologx"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+// AdminTokenHeader is the request header with the admin token of the
+// webhook admin API.
+const AdminTokenHeader = "X-Admin-Token"
+
+// WebhookRoutes registers the admin API of the webhook subscriptions of the
+// request tenant. The requests without the adminToken are unauthorized.
+func WebhookRoutes(router gin.IRouter, manager webhookManager, adminToken string) error {
+	if manager == nil {
+		return fmt.Errorf("init webhook routes: webhookManager is nil")
+	}
+	if len(adminToken) == 0 {
+		return fmt.Errorf("init webhook routes: empty admin token")
+	}
+
+	g := router.Group("/webhooks", withAdminToken(adminToken), withSource("http"))
+	{
+		g.GET("/", listWebhooks(manager))
+		g.POST("/", registerWebhook(manager))
+		g.GET("/:id", getWebhook(manager))
+		g.DELETE("/:id", deleteWebhook(manager))
+		g.POST("/:id/enable", enableWebhook(manager))
+		g.GET("/:id/deliveries", webhookDeliveries(manager))
+	}
+	return nil
+}
+
+// withAdminToken aborts the request without the token in AdminTokenHeader.
+func withAdminToken(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if subtle.ConstantTimeCompare([]byte(c.GetHeader(AdminTokenHeader)), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"err": "unknown or missing admin token"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// pageQuery parses the limit and offset query params.
+func pageQuery(c *gin.Context) (limit, offset int, err error) {
+	if v := c.Query("limit"); len(v) != 0 {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 0 {
+			return 0, 0, fmt.Errorf("invalid value for limit: %s", v)
+		}
+	}
+	if v := c.Query("offset"); len(v) != 0 {
+		offset, err = strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return 0, 0, fmt.Errorf("invalid value for offset: %s", v)
+		}
+	}
+	return limit, offset, nil
+}
+
+type registerWebhookRequest struct {
+	URL    string
+	Events []model.EventType
+}
+
+type registerWebhookResponse struct {
+	Id string
+	// Secret is the HMAC key of the X-Webhook-Signature, it isn't shown again.
+	Secret string
+}
+
+func registerWebhook(manager webhookManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger := zerologx.Get().With().Ctx(c.Request.Context()).Logger()
+		logger.UpdateContext(func(c zerolog.Context) zerolog.Context {
+			return c.Str("port", "http").Str("op", "register webhook")
+		})
+
+		var reqData registerWebhookRequest
+		if err := c.ShouldBindJSON(&reqData); err != nil {
+			logger.Err(err).Send()
+			c.JSON(http.StatusBadRequest,
+				gin.H{"err": fmt.Errorf("register webhook: %w", err).Error()})
+			return
+		}
+		webhook, err := model.NewWebhook(reqData.URL, reqData.Events)
+		if err != nil {
+			logger.Err(err).Send()
+			c.JSON(http.StatusBadRequest,
+				gin.H{"err": fmt.Errorf("register webhook: %w", err).Error()})
+			return
+		}
+		logger.Info().Str("url", webhook.URL).Msg(">> register webhook")
+
+		if err = manager.Register(c.Request.Context(), webhook); err != nil {
+			logger.Err(err).Send()
+			c.JSON(http.StatusInternalServerError,
+				gin.H{"err": fmt.Errorf("register webhook: %w", err).Error()})
+			return
+		}
+		logger.Info().Str("webhook_id", webhook.Id).Msg("<< register webhook")
+		c.JSON(http.StatusCreated, registerWebhookResponse{
+			Id:     webhook.Id,
+			Secret: webhook.Secret,
+		})
+	}
+}
+
+func listWebhooks(manager webhookManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger := zerologx.Get().With().Ctx(c.Request.Context()).Logger()
+		logger.UpdateContext(func(c zerolog.Context) zerolog.Context {
+			return c.Str("port", "http").Str("op", "list webhooks")
+		})
+
+		limit, offset, err := pageQuery(c)
+		if err != nil {
+			logger.Err(err).Send()
+			c.JSON(http.StatusBadRequest,
+				gin.H{"err": err.Error()})
+			return
+		}
+		logger.Info().Msg(">> list webhooks")
+
+		webhooks, err := manager.List(c.Request.Context(), limit, offset)
+		if err != nil {
+			logger.Err(err).Send()
+			c.JSON(http.StatusInternalServerError,
+				gin.H{"err": fmt.Errorf("list webhooks: %w", err).Error()})
+			return
+		}
+		logger.Info().Str("status", "ok").Int("webhooks", len(webhooks)).Msg("<< list webhooks")
+
+		if len(webhooks) == 0 {
+			c.Status(http.StatusNoContent)
+			return
+		}
+		c.JSON(http.StatusOK, webhooks)
+	}
+}
+
+func getWebhook(manager webhookManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+		logger := zerologx.Get().With().Ctx(c.Request.Context()).Logger()
+		logger.UpdateContext(func(c zerolog.Context) zerolog.Context {
+			return c.Str("port", "http").
+				Str("op", "get webhook").
+				Str("param", id)
+		})
+		logger.Info().Msg(">> get webhook")
+
+		webhook, err := manager.FindById(c.Request.Context(), id)
+		if err != nil {
+			logger.Err(err).Send()
+			c.JSON(errStatus(err),
+				gin.H{"err": fmt.Errorf("get webhook: %w", err).Error()})
+			return
+		}
+		logger.Info().Str("status", "ok").Msg("<< get webhook")
+		c.JSON(http.StatusOK, webhook)
+	}
+}
+
+func deleteWebhook(manager webhookManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+		logger := zerologx.Get().With().Ctx(c.Request.Context()).Logger()
+		logger.UpdateContext(func(c zerolog.Context) zerolog.Context {
+			return c.Str("port", "http").
+				Str("op", "delete webhook").
+				Str("param", id)
+		})
+		logger.Info().Msg(">> delete webhook")
+
+		if err := manager.Delete(c.Request.Context(), id); err != nil {
+			logger.Err(err).Send()
+			c.JSON(errStatus(err),
+				gin.H{"err": fmt.Errorf("delete webhook: %w", err).Error()})
+			return
+		}
+		logger.Info().Str("status", "ok").Msg("<< delete webhook")
+		c.Status(http.StatusOK)
+	}
+}
+
+// enableWebhook enables the webhook disabled after the consecutive failures.
+func enableWebhook(manager webhookManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+		logger := zerologx.Get().With().Ctx(c.Request.Context()).Logger()
+		logger.UpdateContext(func(c zerolog.Context) zerolog.Context {
+			return c.Str("port", "http").
+				Str("op", "enable webhook").
+				Str("param", id)
+		})
+		logger.Info().Msg(">> enable webhook")
+
+		if err := manager.Enable(c.Request.Context(), id); err != nil {
+			logger.Err(err).Send()
+			c.JSON(errStatus(err),
+				gin.H{"err": fmt.Errorf("enable webhook: %w", err).Error()})
+			return
+		}
+		logger.Info().Str("status", "ok").Msg("<< enable webhook")
+		c.Status(http.StatusOK)
+	}
+}
+
+// webhookDeliveries returns the delivery log of the webhook, the latest first.
+func webhookDeliveries(manager webhookManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+		logger := zerologx.Get().With().Ctx(c.Request.Context()).Logger()
+		logger.UpdateContext(func(c zerolog.Context) zerolog.Context {
+			return c.Str("port", "http").
+				Str("op", "webhook deliveries").
+				Str("param", id)
+		})
+
+		limit, offset, err := pageQuery(c)
+		if err != nil {
+			logger.Err(err).Send()
+			c.JSON(http.StatusBadRequest,
+				gin.H{"err": err.Error()})
+			return
+		}
+		logger.Info().Msg(">> webhook deliveries")
+
+		deliveries, err := manager.Deliveries(c.Request.Context(), id, limit, offset)
+		if err != nil {
+			logger.Err(err).Send()
+			c.JSON(errStatus(err),
+				gin.H{"err": fmt.Errorf("webhook deliveries: %w", err).Error()})
+			return
+		}
+		logger.Info().Str("status", "ok").Int("deliveries", len(deliveries)).Msg("<< webhook deliveries")
+
+		if len(deliveries) == 0 {
+			c.Status(http.StatusNoContent)
+			return
+		}
+		c.JSON(http.StatusOK, deliveries)
+	}
+}
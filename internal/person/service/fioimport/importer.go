@@ -0,0 +1,145 @@
+// Package fioimport provides the streaming import of FIO files.
+//
+// The rows are validated, enriched and stored in chunks, so the file is
+// never loaded into memory. The rejected rows are written to the CSV report.
+package fioimport
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/alukart32/effective-mobile-test-task/internal/person/model"
+)
+
+type batchCreator interface {
+	CreateFromBatch(ctx context.Context, fios []model.FIO) ([]model.BatchResult, error)
+}
+
+// _defaultChunkSize is the number of FIOs created at once.
+const _defaultChunkSize = 500
+
+// Summary is the import outcome.
+type Summary struct {
+	Total    int
+	Imported int
+	Rejected int
+}
+
+type importer struct {
+	creator   batchCreator
+	chunkSize int
+}
+
+func Importer(creator batchCreator, chunkSize int) (*importer, error) {
+	if creator == nil {
+		return nil, fmt.Errorf("batch creator is nil")
+	}
+	if chunkSize <= 0 {
+		chunkSize = _defaultChunkSize
+	}
+	return &importer{
+		creator:   creator,
+		chunkSize: chunkSize,
+	}, nil
+}
+
+// Import reads all the rows and writes the rejected ones to the report.
+// The report is a CSV with the line, name, surname, patronymic, reason and
+// raw columns, the raw one is set for the malformed rows only.
+func (i *importer) Import(ctx context.Context, r Reader, report io.Writer) (_ Summary, err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("FIOImporter.Import: %w", err)
+		}
+	}()
+
+	var (
+		summary Summary
+		w       = csv.NewWriter(report)
+		chunk   = make([]model.FIO, 0, i.chunkSize)
+		rows    = make([]Row, 0, i.chunkSize)
+	)
+	reject := func(row Row, raw string, reason error) error {
+		summary.Rejected++
+		return w.Write([]string{
+			strconv.Itoa(row.Line),
+			row.Name,
+			row.Surname,
+			row.Patronymic,
+			reason.Error(),
+			raw,
+		})
+	}
+	flush := func() error {
+		if len(chunk) == 0 {
+			return nil
+		}
+		results, err := i.creator.CreateFromBatch(ctx, chunk)
+		if err != nil {
+			return err
+		}
+		for j, res := range results {
+			if res.Err != nil {
+				if err := reject(rows[j], "", res.Err); err != nil {
+					return err
+				}
+				continue
+			}
+			summary.Imported++
+		}
+		chunk = chunk[:0]
+		rows = rows[:0]
+		w.Flush()
+		return w.Error()
+	}
+
+	if err = w.Write([]string{"line", "name", "surname", "patronymic", "reason", "raw"}); err != nil {
+		return summary, err
+	}
+	for {
+		if err = ctx.Err(); err != nil {
+			return summary, err
+		}
+
+		row, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var rowErr *RowError
+			if !errors.As(err, &rowErr) {
+				return summary, err
+			}
+			summary.Total++
+			if err = reject(Row{Line: rowErr.Line}, rowErr.Raw, rowErr.Err); err != nil {
+				return summary, err
+			}
+			continue
+		}
+		summary.Total++
+
+		fio, err := model.NewFIO(row.Name, row.Surname, row.Patronymic)
+		if err != nil {
+			if err = reject(row, "", err); err != nil {
+				return summary, err
+			}
+			continue
+		}
+		chunk = append(chunk, fio)
+		rows = append(rows, row)
+		if len(chunk) == i.chunkSize {
+			if err = flush(); err != nil {
+				return summary, err
+			}
+		}
+	}
+	if err = flush(); err != nil {
+		return summary, err
+	}
+	w.Flush()
+	return summary, w.Error()
+}
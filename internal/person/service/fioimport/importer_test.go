@@ -0,0 +1,103 @@
+package fioimport
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/alukart32/effective-mobile-test-task/internal/person/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type batchCreatorMock struct {
+	CreateFromBatchFn func(ctx context.Context, fios []model.FIO) ([]model.BatchResult, error)
+}
+
+func (m *batchCreatorMock) CreateFromBatch(ctx context.Context, fios []model.FIO) ([]model.BatchResult, error) {
+	if m != nil && m.CreateFromBatchFn != nil {
+		return m.CreateFromBatchFn(ctx, fios)
+	}
+	return nil, fmt.Errorf("can't create persons")
+}
+
+func TestImporter_Import(t *testing.T) {
+	type want struct {
+		summary  Summary
+		rejected []string
+		err      bool
+	}
+	tests := []struct {
+		name    string
+		input   string
+		creator batchCreatorMock
+		want    want
+	}{
+		{
+			name:  "Valid and invalid rows, no error",
+			input: "Ivan,Ivanov\nPetr,Petrov\nAnna,Ivanova\nOleg\nI@n,Ivanov\nFail,Failed\n",
+			creator: batchCreatorMock{
+				CreateFromBatchFn: func(ctx context.Context, fios []model.FIO) ([]model.BatchResult, error) {
+					results := make([]model.BatchResult, len(fios))
+					for i, fio := range fios {
+						if fio.Name == "Fail" {
+							results[i].Err = fmt.Errorf("no age in response")
+							continue
+						}
+						results[i].Id = "id_" + fio.Name
+					}
+					return results, nil
+				},
+			},
+			want: want{
+				summary: Summary{
+					Total:    6,
+					Imported: 3,
+					Rejected: 3,
+				},
+				rejected: []string{"4", "5", "6"},
+			},
+		},
+		{
+			name:  "Creator error, error",
+			input: "Ivan,Ivanov\n",
+			creator: batchCreatorMock{
+				CreateFromBatchFn: func(ctx context.Context, fios []model.FIO) ([]model.BatchResult, error) {
+					return nil, fmt.Errorf("internal error")
+				},
+			},
+			want: want{
+				err: true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			importer, err := Importer(&tt.creator, 2)
+			require.NoError(t, err)
+
+			r, err := CSVReader(strings.NewReader(tt.input), CSVOptions{Columns: DefaultColumnMapping})
+			require.NoError(t, err)
+
+			var report bytes.Buffer
+			summary, err := importer.Import(context.Background(), r, &report)
+			if tt.want.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.EqualValues(t, tt.want.summary, summary)
+
+			records, err := csv.NewReader(&report).ReadAll()
+			require.NoError(t, err)
+			require.Len(t, records, len(tt.want.rejected)+1)
+			for i, line := range tt.want.rejected {
+				assert.Equal(t, line, records[i+1][0])
+			}
+		})
+	}
+}
@@ -0,0 +1,267 @@
+package fioimport
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Row is a FIO row read from the import file.
+type Row struct {
+	Line       int
+	Name       string
+	Surname    string
+	Patronymic string
+}
+
+// RowError is a row that can't be read. The import goes on after it.
+type RowError struct {
+	Line int
+	Raw  string
+	Err  error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// Reader reads the FIO rows one by one. It returns io.EOF at the end of the
+// input and *RowError for a malformed row.
+type Reader interface {
+	Read() (Row, error)
+}
+
+// Format is the import file format.
+type Format string
+
+const (
+	CSV    Format = "csv"
+	NDJSON Format = "ndjson"
+)
+
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case CSV, NDJSON:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unsupported import format: %s", s)
+	}
+}
+
+// ColumnMapping maps the FIO fields to the CSV columns. A column is
+// either a zero-based index or a header name.
+type ColumnMapping struct {
+	Name       string
+	Surname    string
+	Patronymic string
+}
+
+// DefaultColumnMapping expects the name, surname and patronymic columns in a row.
+var DefaultColumnMapping = ColumnMapping{
+	Name:       "0",
+	Surname:    "1",
+	Patronymic: "2",
+}
+
+// ParseColumnMapping parses the mapping like "name=first_name,surname=1".
+// The omitted fields are taken from DefaultColumnMapping.
+func ParseColumnMapping(s string) (ColumnMapping, error) {
+	mapping := DefaultColumnMapping
+	if len(s) == 0 {
+		return mapping, nil
+	}
+
+	for _, pair := range strings.Split(s, ",") {
+		field, column, ok := strings.Cut(pair, "=")
+		column = strings.TrimSpace(column)
+		if !ok || len(column) == 0 {
+			return ColumnMapping{}, fmt.Errorf("column mapping parsing error: %s", pair)
+		}
+		switch strings.TrimSpace(field) {
+		case "name":
+			mapping.Name = column
+		case "surname":
+			mapping.Surname = column
+		case "patronymic":
+			mapping.Patronymic = column
+		default:
+			return ColumnMapping{}, fmt.Errorf("unsupported %s column mapping", pair)
+		}
+	}
+	return mapping, nil
+}
+
+// CSVOptions configures the CSV reader.
+type CSVOptions struct {
+	// Comma is the field delimiter, ',' by default.
+	Comma rune
+	// Header reports whether the first record is a header.
+	Header  bool
+	Columns ColumnMapping
+}
+
+type csvReader struct {
+	r *csv.Reader
+
+	name, surname, patronymic int
+}
+
+// CSVReader returns a reader of the CSV records. The header is read at once
+// to resolve the column names of the mapping.
+func CSVReader(r io.Reader, opts CSVOptions) (*csvReader, error) {
+	cr := csv.NewReader(r)
+	if opts.Comma != 0 {
+		cr.Comma = opts.Comma
+	}
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+
+	var header []string
+	if opts.Header {
+		record, err := cr.Read()
+		if err != nil {
+			return nil, fmt.Errorf("read CSV header: %w", err)
+		}
+		header = make([]string, len(record))
+		for i, h := range record {
+			header[i] = strings.ToLower(strings.TrimSpace(h))
+		}
+	}
+
+	column := func(c string) (int, error) {
+		if i, err := strconv.Atoi(c); err == nil {
+			if i < 0 {
+				return 0, fmt.Errorf("invalid CSV column: %s", c)
+			}
+			return i, nil
+		}
+		for i, h := range header {
+			if h == strings.ToLower(c) {
+				return i, nil
+			}
+		}
+		return 0, fmt.Errorf("unknown CSV column: %s", c)
+	}
+
+	var (
+		reader = csvReader{r: cr}
+		err    error
+	)
+	if reader.name, err = column(opts.Columns.Name); err != nil {
+		return nil, err
+	}
+	if reader.surname, err = column(opts.Columns.Surname); err != nil {
+		return nil, err
+	}
+	if reader.patronymic, err = column(opts.Columns.Patronymic); err != nil {
+		return nil, err
+	}
+	return &reader, nil
+}
+
+func (r *csvReader) Read() (Row, error) {
+	record, err := r.r.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return Row{}, &RowError{Line: parseErr.StartLine, Err: parseErr.Err}
+		}
+		return Row{}, err
+	}
+	line, _ := r.r.FieldPos(0)
+
+	field := func(i int) string {
+		if i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	return Row{
+		Line:       line,
+		Name:       field(r.name),
+		Surname:    field(r.surname),
+		Patronymic: field(r.patronymic),
+	}, nil
+}
+
+const (
+	// _maxLineSize limits the NDJSON line, the longer line is the row error.
+	_maxLineSize = 64 * 1024
+	// _maxRawSize limits the raw long line kept in the row error.
+	_maxRawSize = 256
+)
+
+var errLineTooLong = fmt.Errorf("line exceeds %d bytes", _maxLineSize)
+
+type ndjsonReader struct {
+	r    *bufio.Reader
+	line int
+}
+
+// NDJSONReader returns a reader of the JSON objects, one per line.
+func NDJSONReader(r io.Reader) *ndjsonReader {
+	return &ndjsonReader{r: bufio.NewReaderSize(r, _maxLineSize)}
+}
+
+type fioObject struct {
+	Name       string
+	Surname    string
+	Patronymic string
+}
+
+func (r *ndjsonReader) Read() (Row, error) {
+	for {
+		b, tooLong, err := r.readLine()
+		if err != nil {
+			return Row{}, err
+		}
+		r.line++
+		if tooLong {
+			return Row{}, &RowError{Line: r.line, Raw: string(b), Err: errLineTooLong}
+		}
+		if len(strings.TrimSpace(string(b))) == 0 {
+			continue
+		}
+
+		var obj fioObject
+		if err := json.Unmarshal(b, &obj); err != nil {
+			return Row{}, &RowError{Line: r.line, Raw: string(b), Err: err}
+		}
+		return Row{
+			Line:       r.line,
+			Name:       strings.TrimSpace(obj.Name),
+			Surname:    strings.TrimSpace(obj.Surname),
+			Patronymic: strings.TrimSpace(obj.Patronymic),
+		}, nil
+	}
+}
+
+// readLine returns the next line without the line end, io.EOF at the end of
+// the input. The line over _maxLineSize is skipped to its end, only its
+// prefix is returned with tooLong.
+func (r *ndjsonReader) readLine() (line []byte, tooLong bool, err error) {
+	line, isPrefix, err := r.r.ReadLine()
+	if err != nil || !isPrefix {
+		return line, false, err
+	}
+
+	line = append([]byte(nil), line[:_maxRawSize]...)
+	for isPrefix {
+		if _, isPrefix, err = r.r.ReadLine(); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, false, err
+		}
+	}
+	return line, true, nil
+}
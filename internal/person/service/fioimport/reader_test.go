@@ -0,0 +1,209 @@
+package fioimport
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readAll(t *testing.T, r Reader) ([]Row, []*RowError) {
+	t.Helper()
+
+	var (
+		rows    []Row
+		rowErrs []*RowError
+	)
+	for {
+		row, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, rowErrs
+		}
+		var rowErr *RowError
+		if errors.As(err, &rowErr) {
+			rowErrs = append(rowErrs, rowErr)
+			continue
+		}
+		require.NoError(t, err)
+		rows = append(rows, row)
+	}
+}
+
+func TestParseColumnMapping(t *testing.T) {
+	type want struct {
+		mapping ColumnMapping
+		err     error
+	}
+	tests := []struct {
+		name    string
+		mapping string
+		want    want
+	}{
+		{
+			name:    "Empty mapping, default",
+			mapping: "",
+			want: want{
+				mapping: DefaultColumnMapping,
+			},
+		},
+		{
+			name:    "Partial mapping, no error",
+			mapping: "name=first_name, surname=3",
+			want: want{
+				mapping: ColumnMapping{
+					Name:       "first_name",
+					Surname:    "3",
+					Patronymic: "2",
+				},
+			},
+		},
+		{
+			name:    "Unknown field, error",
+			mapping: "age=1",
+			want: want{
+				err: fmt.Errorf("unsupported age=1 column mapping"),
+			},
+		},
+		{
+			name:    "Invalid mapping, error",
+			mapping: "name",
+			want: want{
+				err: fmt.Errorf("column mapping parsing error: name"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapping, err := ParseColumnMapping(tt.mapping)
+			if tt.want.err != nil {
+				assert.EqualError(t, err, tt.want.err.Error())
+				return
+			}
+			assert.EqualValues(t, tt.want.mapping, mapping)
+		})
+	}
+}
+
+func TestCSVReader(t *testing.T) {
+	type want struct {
+		rows    []Row
+		rowErrs int
+		err     error
+	}
+	tests := []struct {
+		name  string
+		input string
+		opts  CSVOptions
+		want  want
+	}{
+		{
+			name:  "Default mapping, no header",
+			input: "Ivan,Ivanov,Ivanovich\nPetr,Petrov\n",
+			opts: CSVOptions{
+				Columns: DefaultColumnMapping,
+			},
+			want: want{
+				rows: []Row{
+					{Line: 1, Name: "Ivan", Surname: "Ivanov", Patronymic: "Ivanovich"},
+					{Line: 2, Name: "Petr", Surname: "Petrov"},
+				},
+			},
+		},
+		{
+			name:  "Header mapping, custom comma",
+			input: "id;last;first\n1;Ivanov;Ivan\n",
+			opts: CSVOptions{
+				Comma:  ';',
+				Header: true,
+				Columns: ColumnMapping{
+					Name:       "first",
+					Surname:    "last",
+					Patronymic: "5",
+				},
+			},
+			want: want{
+				rows: []Row{
+					{Line: 2, Name: "Ivan", Surname: "Ivanov"},
+				},
+			},
+		},
+		{
+			name:  "Malformed record, row error",
+			input: "Ivan,\"Ivanov\n",
+			opts: CSVOptions{
+				Columns: DefaultColumnMapping,
+			},
+			want: want{
+				rowErrs: 1,
+			},
+		},
+		{
+			name:  "Unknown header column, error",
+			input: "name,surname\n",
+			opts: CSVOptions{
+				Header: true,
+				Columns: ColumnMapping{
+					Name:       "name",
+					Surname:    "surname",
+					Patronymic: "patronymic",
+				},
+			},
+			want: want{
+				err: fmt.Errorf("unknown CSV column: patronymic"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := CSVReader(strings.NewReader(tt.input), tt.opts)
+			if tt.want.err != nil {
+				assert.EqualError(t, err, tt.want.err.Error())
+				return
+			}
+			require.NoError(t, err)
+
+			rows, rowErrs := readAll(t, r)
+			assert.EqualValues(t, tt.want.rows, rows)
+			assert.Len(t, rowErrs, tt.want.rowErrs)
+		})
+	}
+}
+
+func TestNDJSONReader(t *testing.T) {
+	input := `{"name":"Ivan","surname":"Ivanov","patronymic":"Ivanovich"}
+
+{"Name":"Petr","Surname":"Petrov"}
+{"name":
+`
+	rows, rowErrs := readAll(t, NDJSONReader(strings.NewReader(input)))
+	assert.EqualValues(t, []Row{
+		{Line: 1, Name: "Ivan", Surname: "Ivanov", Patronymic: "Ivanovich"},
+		{Line: 3, Name: "Petr", Surname: "Petrov"},
+	}, rows)
+	require.Len(t, rowErrs, 1)
+	assert.Equal(t, 4, rowErrs[0].Line)
+	assert.Equal(t, `{"name":`, rowErrs[0].Raw)
+}
+
+func TestNDJSONReader_LongLine(t *testing.T) {
+	long := `{"name":"` + strings.Repeat("a", _maxLineSize) + `"}`
+	input := `{"name":"Ivan","surname":"Ivanov"}` + "\n" + long + "\n" + `{"name":"Petr","surname":"Petrov"}` + "\n" + long
+
+	rows, rowErrs := readAll(t, NDJSONReader(strings.NewReader(input)))
+	assert.EqualValues(t, []Row{
+		{Line: 1, Name: "Ivan", Surname: "Ivanov"},
+		{Line: 3, Name: "Petr", Surname: "Petrov"},
+	}, rows)
+	require.Len(t, rowErrs, 2)
+	for i, line := range []int{2, 4} {
+		assert.Equal(t, line, rowErrs[i].Line)
+		assert.ErrorIs(t, rowErrs[i], errLineTooLong)
+		assert.Equal(t, long[:_maxRawSize], rowErrs[i].Raw)
+	}
+}
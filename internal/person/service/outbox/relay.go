@@ -0,0 +1,145 @@
+// Package outbox relays the person domain events from the transactional
+// outbox to the message broker with at-least-once delivery.
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alukart32/effective-mobile-test-task/internal/person/model"
+	"github.com/alukart32/effective-mobile-test-task/internal/pkg/zerologx"
+)
+
+type store interface {
+	Publish(ctx context.Context, limit int, fn func([]model.OutboxMessage) error) (int, error)
+	Cleanup(ctx context.Context, publishedBefore time.Time) (int64, error)
+}
+
+type publisher interface {
+	Publish(ctx context.Context, msgs []model.OutboxMessage) error
+}
+
+// RelayConfig is the relay polling and cleanup schedule.
+type RelayConfig struct {
+	// BatchSize limits the messages published at once.
+	BatchSize    int
+	PollInterval time.Duration
+	// Retention is how long the published messages are kept.
+	Retention       time.Duration
+	CleanupInterval time.Duration
+}
+
+const (
+	_defaultBatchSize       = 100
+	_defaultPollInterval    = time.Second
+	_defaultRetention       = 24 * time.Hour
+	_defaultCleanupInterval = time.Hour
+)
+
+type relay struct {
+	store     store
+	publisher publisher
+	cfg       RelayConfig
+}
+
+func Relay(s store, p publisher, cfg RelayConfig) (*relay, error) {
+	if s == nil {
+		return nil, fmt.Errorf("outbox store is nil")
+	}
+	if p == nil {
+		return nil, fmt.Errorf("publisher is nil")
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = _defaultBatchSize
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = _defaultPollInterval
+	}
+	if cfg.Retention <= 0 {
+		cfg.Retention = _defaultRetention
+	}
+	if cfg.CleanupInterval <= 0 {
+		cfg.CleanupInterval = _defaultCleanupInterval
+	}
+
+	return &relay{
+		store:     s,
+		publisher: p,
+		cfg:       cfg,
+	}, nil
+}
+
+// Run publishes the outbox messages and cleans up the published ones until
+// ctx is done. The full batches are published one after another, otherwise
+// the outbox is polled every poll interval.
+func (r *relay) Run(ctx context.Context) {
+	logger := zerologx.Get().With().Str("op", "outbox relay").Logger()
+
+	poll := time.NewTimer(0)
+	defer poll.Stop()
+	cleanup := time.NewTicker(r.cfg.CleanupInterval)
+	defer cleanup.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-poll.C:
+			n, err := r.PublishBatch(ctx)
+			if err != nil {
+				logger.Err(err).Send()
+			}
+			next := r.cfg.PollInterval
+			if err == nil && n == r.cfg.BatchSize {
+				next = 0
+			}
+			poll.Reset(next)
+		case <-cleanup.C:
+			n, err := r.Cleanup(ctx)
+			if err != nil {
+				logger.Err(err).Send()
+			} else if n > 0 {
+				logger.Info().Int64("deleted", n).Msg("cleanup published messages")
+			}
+		}
+	}
+}
+
+// PublishBatch publishes the oldest unpublished messages in the outbox order.
+// The failed batch stays in the outbox and is published again later.
+func (r *relay) PublishBatch(ctx context.Context) (int, error) {
+	n, err := r.store.Publish(ctx, r.cfg.BatchSize, func(msgs []model.OutboxMessage) error {
+		return r.publisher.Publish(ctx, msgs)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("OutboxRelay.PublishBatch: %w", err)
+	}
+	return n, nil
+}
+
+// Cleanup deletes the messages published longer than the retention.
+func (r *relay) Cleanup(ctx context.Context) (int64, error) {
+	n, err := r.store.Cleanup(ctx, time.Now().Add(-r.cfg.Retention))
+	if err != nil {
+		return 0, fmt.Errorf("OutboxRelay.Cleanup: %w", err)
+	}
+	return n, nil
+}
+
+// fanout publishes the messages to the publishers one by one. The failed
+// batch is published to all of them again, so the idempotent publishers
+// should go first.
+type fanout []publisher
+
+func Fanout(publishers ...publisher) fanout {
+	return fanout(publishers)
+}
+
+func (f fanout) Publish(ctx context.Context, msgs []model.OutboxMessage) error {
+	for _, p := range f {
+		if err := p.Publish(ctx, msgs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alukart32/effective-mobile-test-task/internal/person/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type storeMock struct {
+	PublishFn func(ctx context.Context, limit int, fn func([]model.OutboxMessage) error) (int, error)
+	CleanupFn func(ctx context.Context, publishedBefore time.Time) (int64, error)
+}
+
+func (m *storeMock) Publish(ctx context.Context, limit int, fn func([]model.OutboxMessage) error) (int, error) {
+	if m != nil && m.PublishFn != nil {
+		return m.PublishFn(ctx, limit, fn)
+	}
+	return 0, nil
+}
+
+func (m *storeMock) Cleanup(ctx context.Context, publishedBefore time.Time) (int64, error) {
+	if m != nil && m.CleanupFn != nil {
+		return m.CleanupFn(ctx, publishedBefore)
+	}
+	return 0, nil
+}
+
+type publisherMock struct {
+	PublishFn func(ctx context.Context, msgs []model.OutboxMessage) error
+}
+
+func (m *publisherMock) Publish(ctx context.Context, msgs []model.OutboxMessage) error {
+	if m != nil && m.PublishFn != nil {
+		return m.PublishFn(ctx, msgs)
+	}
+	return nil
+}
+
+func TestRelay_PublishBatch(t *testing.T) {
+	msgs := []model.OutboxMessage{
+		{Id: 1, Key: "person_1", Type: model.EventPersonCreated},
+		{Id: 2, Key: "person_1", Type: model.EventPersonUpdated},
+	}
+	// outbox keeps the messages until they are published.
+	outbox := func(published *[]int64) *storeMock {
+		return &storeMock{
+			PublishFn: func(ctx context.Context, limit int, fn func([]model.OutboxMessage) error) (int, error) {
+				batch := msgs
+				if len(batch) > limit {
+					batch = batch[:limit]
+				}
+				if err := fn(batch); err != nil {
+					return 0, err
+				}
+				for _, m := range batch {
+					*published = append(*published, m.Id)
+				}
+				return len(batch), nil
+			},
+		}
+	}
+
+	type want struct {
+		n         int
+		published []int64
+		err       error
+	}
+	tests := []struct {
+		name      string
+		batchSize int
+		publisher publisherMock
+		want      want
+	}{
+		{
+			name:      "Published in order, no error",
+			batchSize: 10,
+			want: want{
+				n:         2,
+				published: []int64{1, 2},
+			},
+		},
+		{
+			name:      "Batch size limit, no error",
+			batchSize: 1,
+			want: want{
+				n:         1,
+				published: []int64{1},
+			},
+		},
+		{
+			name:      "Publisher error, nothing marked published",
+			batchSize: 10,
+			publisher: publisherMock{
+				PublishFn: func(ctx context.Context, msgs []model.OutboxMessage) error {
+					return fmt.Errorf("broker unavailable")
+				},
+			},
+			want: want{
+				err: fmt.Errorf("OutboxRelay.PublishBatch: broker unavailable"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var published []int64
+			r, err := Relay(outbox(&published), &tt.publisher, RelayConfig{BatchSize: tt.batchSize})
+			require.NoError(t, err)
+
+			n, err := r.PublishBatch(context.Background())
+			if tt.want.err != nil {
+				assert.EqualError(t, err, tt.want.err.Error())
+				assert.Empty(t, published)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want.n, n)
+			assert.Equal(t, tt.want.published, published)
+		})
+	}
+}
+
+func TestRelay_Cleanup(t *testing.T) {
+	var before time.Time
+	s := &storeMock{
+		CleanupFn: func(ctx context.Context, publishedBefore time.Time) (int64, error) {
+			before = publishedBefore
+			return 5, nil
+		},
+	}
+	r, err := Relay(s, &publisherMock{}, RelayConfig{Retention: time.Hour})
+	require.NoError(t, err)
+
+	n, err := r.Cleanup(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), n)
+	assert.WithinDuration(t, time.Now().Add(-time.Hour), before, time.Minute)
+}
+
+func TestFanout_Publish(t *testing.T) {
+	var calls []string
+	publisherOf := func(name string, err error) *publisherMock {
+		return &publisherMock{
+			PublishFn: func(ctx context.Context, msgs []model.OutboxMessage) error {
+				calls = append(calls, name)
+				return err
+			},
+		}
+	}
+
+	err := Fanout(publisherOf("webhooks", nil), publisherOf("kafka", nil)).
+		Publish(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"webhooks", "kafka"}, calls)
+
+	calls = nil
+	err = Fanout(publisherOf("webhooks", fmt.Errorf("enqueue failed")), publisherOf("kafka", nil)).
+		Publish(context.Background(), nil)
+	assert.EqualError(t, err, "enqueue failed")
+	assert.Equal(t, []string{"webhooks"}, calls)
+}
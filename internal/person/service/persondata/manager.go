@@ -2,9 +2,12 @@ package persondata
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/alukart32/effective-mobile-test-task/internal/person/model"
+	"github.com/alukart32/effective-mobile-test-task/internal/pkg/zerologx"
 )
 
 type metaDataProvider interface {
@@ -17,30 +20,58 @@ type saver interface {
 	Save(context.Context, model.Person) error
 }
 
+type batchSaver interface {
+	SaveBatch(context.Context, []model.Person) error
+}
+
 type finder interface {
-	FindById(ctx context.Context, id string) (model.Person, error)
+	FindById(ctx context.Context, id string, includeDeleted bool) (model.Person, error)
 }
 
 type collector interface {
 	Collect(ctx context.Context, filter model.PersonFilter, limit, offset int) ([]model.Person, error)
 }
 
+type historian interface {
+	History(ctx context.Context, id string, limit, offset int) ([]model.PersonChange, error)
+}
+
+type exporter interface {
+	Export(ctx context.Context, filter model.PersonFilter, fn func(model.Person) error) error
+}
+
+type statsCollector interface {
+	Stats(ctx context.Context, filter model.PersonFilter, buckets model.AgeBuckets) (model.PersonStats, error)
+}
+
 type updater interface {
-	Update(ctx context.Context, id string, meta model.PersonalMetaData) error
+	Update(ctx context.Context, id string, patch model.PersonPatch, expectedVersion int64) (int64, error)
 }
 
 type deleter interface {
-	Delete(ctx context.Context, id string) error
+	Delete(ctx context.Context, id string, expectedVersion int64) error
+}
+
+type restorer interface {
+	Restore(ctx context.Context, id string) error
 }
 
 type repo interface {
 	saver
+	batchSaver
 	finder
 	collector
+	historian
+	exporter
+	statsCollector
 	updater
 	deleter
+	restorer
 }
 
+// _enrichWorkers limits the concurrent enrichment of the batch FIOs.
+const _enrichWorkers = 8
+
 type manager struct {
 	repo
 	metaDataProvider
@@ -61,42 +92,122 @@ func Manager(r repo, metaData metaDataProvider) (*manager, error) {
 }
 
 func (m *manager) CreateFrom(ctx context.Context, fio model.FIO) (string, error) {
-	age, err := m.metaDataProvider.AgeByName(ctx, fio.Name)
+	person, err := m.enrich(ctx, fio)
 	if err != nil {
 		return "", fmt.Errorf("PersonManager.CreateFrom: %w", err)
 	}
+	if err = m.repo.Save(ctx, person); err != nil {
+		return "", fmt.Errorf("PersonManager.CreateFrom: %w", err)
+	}
+	return person.Id, nil
+}
+
+// CreateFromBatch enriches the FIOs concurrently and saves the persons at once.
+// If one person violates the constraint, the persons are saved one by one,
+// so every FIO gets its own result. The other save errors, e.g. the exceeded
+// quota or the lost connection, fail the whole batch. The results are in
+// the order of the FIOs.
+func (m *manager) CreateFromBatch(ctx context.Context, fios []model.FIO) ([]model.BatchResult, error) {
+	if len(fios) == 0 {
+		return nil, fmt.Errorf("PersonManager.CreateFromBatch: empty batch")
+	}
+
+	results := make([]model.BatchResult, len(fios))
+	persons := make([]model.Person, len(fios))
+
+	workers := _enrichWorkers
+	if len(fios) < workers {
+		workers = len(fios)
+	}
+	idxs := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range idxs {
+				person, err := m.enrich(ctx, fios[i])
+				if err != nil {
+					results[i].Err = err
+					continue
+				}
+				persons[i] = person
+			}
+		}()
+	}
+	for i := range fios {
+		idxs <- i
+	}
+	close(idxs)
+	wg.Wait()
+
+	enriched := make([]model.Person, 0, len(persons))
+	for i, p := range persons {
+		if results[i].Err == nil {
+			enriched = append(enriched, p)
+		}
+	}
+	if len(enriched) == 0 {
+		return results, nil
+	}
+
+	batchErr := m.repo.SaveBatch(ctx, enriched)
+	if batchErr != nil {
+		if !errors.Is(batchErr, model.ErrConstraintViolation) {
+			return nil, fmt.Errorf("PersonManager.CreateFromBatch: %w", batchErr)
+		}
+		zerologx.Get().Warn().Ctx(ctx).Err(batchErr).Int("persons", len(enriched)).
+			Msg("batch violates constraint, persons are saved one by one")
+	}
+	for i, p := range persons {
+		if results[i].Err != nil {
+			continue
+		}
+		if batchErr != nil {
+			if err := m.repo.Save(ctx, p); err != nil {
+				results[i].Err = err
+				continue
+			}
+		}
+		results[i].Id = p.Id
+	}
+	return results, nil
+}
+
+// enrich makes a new person from the FIO and its personal metadata.
+func (m *manager) enrich(ctx context.Context, fio model.FIO) (model.Person, error) {
+	age, err := m.metaDataProvider.AgeByName(ctx, fio.Name)
+	if err != nil {
+		return model.Person{}, err
+	}
 	gender, err := m.metaDataProvider.GenderByName(ctx, fio.Name)
 	if err != nil {
-		return "", fmt.Errorf("PersonManager.CreateFrom: %w", err)
+		return model.Person{}, err
 	}
 	nation, err := m.metaDataProvider.NationByName(ctx, fio.Name)
 	if err != nil {
-		return "", fmt.Errorf("PersonManager.CreateFrom: %w", err)
+		return model.Person{}, err
 	}
 
-	person := model.NewPerson(fio, model.PersonalMetaData{
+	return model.NewPerson(fio, model.PersonalMetaData{
 		Nation: nation,
 		Gender: gender,
 		Age:    age,
-	})
-
-	if err = m.repo.Save(ctx, person); err != nil {
-		return "", fmt.Errorf("PersonManager.CreateFrom: %w", err)
-	}
-	return person.Id, nil
+	}), nil
 }
 
-func (m *manager) FindById(ctx context.Context, id string) (model.Person, error) {
+// FindById returns the person, the soft deleted one is returned only if includeDeleted.
+func (m *manager) FindById(ctx context.Context, id string, includeDeleted bool) (model.Person, error) {
 	if len(id) == 0 {
 		return model.Person{}, fmt.Errorf("PersonManager.FindById: empty id")
 	}
 
-	person, err := m.repo.FindById(ctx, id)
+	person, err := m.repo.FindById(ctx, id, includeDeleted)
 	if err != nil {
 		return model.Person{}, fmt.Errorf("PersonManager.FindById: %w", err)
 	}
 	if person.IsEmpty() {
-		return model.Person{}, fmt.Errorf("PersonManager.FindById: not found")
+		return model.Person{}, fmt.Errorf("PersonManager.FindById: %w", model.ErrNotFound)
 	}
 	return person, nil
 }
@@ -109,29 +220,208 @@ func (m *manager) Collect(ctx context.Context, filter model.PersonFilter, limit,
 	return persons, nil
 }
 
-func (m *manager) Update(ctx context.Context, id string, meta model.PersonalMetaData) error {
+// History returns the audit history of the person changes.
+func (m *manager) History(ctx context.Context, id string, limit, offset int) ([]model.PersonChange, error) {
 	if len(id) == 0 {
-		return fmt.Errorf("PersonManager.Update: empty id")
-	}
-	if meta.IsEmpty() {
-		return fmt.Errorf("PersonManager.Update: no data for update")
+		return nil, fmt.Errorf("PersonManager.History: empty id")
 	}
 
-	err := m.repo.Update(ctx, id, meta)
+	changes, err := m.repo.History(ctx, id, limit, offset)
 	if err != nil {
-		return fmt.Errorf("PersonManager.Update: %w", err)
+		return nil, fmt.Errorf("PersonManager.History: %w", err)
+	}
+	return changes, nil
+}
+
+// Export passes the filtered persons to fn one by one, it stops on the first fn error.
+func (m *manager) Export(ctx context.Context, filter model.PersonFilter, fn func(model.Person) error) error {
+	if fn == nil {
+		return fmt.Errorf("PersonManager.Export: nil export func")
+	}
+
+	if err := m.repo.Export(ctx, filter, fn); err != nil {
+		return fmt.Errorf("PersonManager.Export: %w", err)
 	}
 	return nil
 }
 
-func (m *manager) Delete(ctx context.Context, id string) error {
+func (m *manager) Stats(ctx context.Context, filter model.PersonFilter, buckets model.AgeBuckets) (model.PersonStats, error) {
+	if len(buckets) == 0 {
+		buckets = model.DefaultAgeBuckets
+	}
+
+	stats, err := m.repo.Stats(ctx, filter, buckets)
+	if err != nil {
+		return model.PersonStats{}, fmt.Errorf("PersonManager.Stats: %w", err)
+	}
+	return stats, nil
+}
+
+// Update applies the patch to the person. The non zero expectedVersion must match
+// the person version, otherwise model.ErrVersionMismatch is returned.
+// If reenrich, the metadata of the renamed person is recomputed except
+// the manual overrides. The new version and the recomputed attributes are returned.
+func (m *manager) Update(
+	ctx context.Context,
+	id string,
+	patch model.PersonPatch,
+	expectedVersion int64,
+	reenrich bool,
+) (int64, []model.MetaAttr, error) {
+	if len(id) == 0 {
+		return 0, nil, fmt.Errorf("PersonManager.Update: empty id")
+	}
+	if patch.IsEmpty() {
+		return 0, nil, fmt.Errorf("PersonManager.Update: no data for update")
+	}
+
+	version, recomputed, err := m.update(ctx, id, patch, expectedVersion, reenrich)
+	if err != nil {
+		return 0, nil, fmt.Errorf("PersonManager.Update: %w", err)
+	}
+	return version, recomputed, nil
+}
+
+// Replace replaces all the person fields, the empty metadata is cleared.
+// The non zero expectedVersion must match the person version.
+// If reenrich, the empty metadata of the renamed person is recomputed
+// instead. The new version and the recomputed attributes are returned.
+func (m *manager) Replace(
+	ctx context.Context,
+	id string,
+	fio model.FIO,
+	meta model.PersonalMetaData,
+	expectedVersion int64,
+	reenrich bool,
+) (int64, []model.MetaAttr, error) {
+	if len(id) == 0 {
+		return 0, nil, fmt.Errorf("PersonManager.Replace: empty id")
+	}
+
+	patch := model.NewPersonReplacement(fio, meta)
+	if reenrich {
+		if len(meta.Nation) == 0 {
+			patch.Nation = nil
+		}
+		if len(meta.Gender) == 0 {
+			patch.Gender = nil
+		}
+		if meta.Age == 0 {
+			patch.Age = nil
+		}
+	}
+
+	version, recomputed, err := m.update(ctx, id, patch, expectedVersion, reenrich)
+	if err != nil {
+		return 0, nil, fmt.Errorf("PersonManager.Replace: %w", err)
+	}
+	return version, recomputed, nil
+}
+
+func (m *manager) update(
+	ctx context.Context,
+	id string,
+	patch model.PersonPatch,
+	expectedVersion int64,
+	reenrich bool,
+) (int64, []model.MetaAttr, error) {
+	if err := patch.Validate(); err != nil {
+		return 0, nil, err
+	}
+	if expectedVersion < 0 {
+		return 0, nil, fmt.Errorf("negative expected version")
+	}
+
+	var recomputed []model.MetaAttr
+	if reenrich && patch.Name != nil {
+		current, err := m.repo.FindById(ctx, id, false)
+		if err != nil {
+			return 0, nil, err
+		}
+		if current.IsEmpty() {
+			return 0, nil, model.ErrNotFound
+		}
+		if expectedVersion != 0 && current.Version != expectedVersion {
+			return 0, nil, model.ErrVersionMismatch
+		}
+		// The person must not change while its metadata is recomputed.
+		expectedVersion = current.Version
+
+		if *patch.Name != current.Name {
+			if recomputed, err = m.reenrich(ctx, current, &patch); err != nil {
+				return 0, nil, err
+			}
+		}
+	}
+
+	version, err := m.repo.Update(ctx, id, patch, expectedVersion)
+	if err != nil {
+		return 0, nil, err
+	}
+	return version, recomputed, nil
+}
+
+// reenrich recomputes the metadata of the person by the patched name.
+// The attributes set by the patch or overridden before are kept.
+func (m *manager) reenrich(ctx context.Context, current model.Person, patch *model.PersonPatch) ([]model.MetaAttr, error) {
+	name := *patch.Name
+	keep := func(attr model.MetaAttr, set bool) bool {
+		return set || current.HasOverride(attr)
+	}
+
+	if !keep(model.AttrAge, patch.Age != nil) {
+		age, err := m.metaDataProvider.AgeByName(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		patch.Age = &age
+		patch.Enriched = append(patch.Enriched, model.AttrAge)
+	}
+	if !keep(model.AttrGender, patch.Gender != nil) {
+		gender, err := m.metaDataProvider.GenderByName(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		patch.Gender = &gender
+		patch.Enriched = append(patch.Enriched, model.AttrGender)
+	}
+	if !keep(model.AttrNation, patch.Nation != nil) {
+		nation, err := m.metaDataProvider.NationByName(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		patch.Nation = &nation
+		patch.Enriched = append(patch.Enriched, model.AttrNation)
+	}
+	return patch.Enriched, nil
+}
+
+// Delete soft deletes the person, it can be restored until purged.
+// The non zero expectedVersion must match the person version.
+func (m *manager) Delete(ctx context.Context, id string, expectedVersion int64) error {
 	if len(id) == 0 {
 		return fmt.Errorf("PersonManager.Delete: empty id")
 	}
+	if expectedVersion < 0 {
+		return fmt.Errorf("PersonManager.Delete: negative expected version")
+	}
 
-	err := m.repo.Delete(ctx, id)
+	err := m.repo.Delete(ctx, id, expectedVersion)
 	if err != nil {
 		return fmt.Errorf("PersonManager.Delete: %w", err)
 	}
 	return nil
 }
+
+// Restore restores the soft deleted person.
+func (m *manager) Restore(ctx context.Context, id string) error {
+	if len(id) == 0 {
+		return fmt.Errorf("PersonManager.Restore: empty id")
+	}
+
+	err := m.repo.Restore(ctx, id)
+	if err != nil {
+		return fmt.Errorf("PersonManager.Restore: %w", err)
+	}
+	return nil
+}
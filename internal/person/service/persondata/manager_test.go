@@ -48,13 +48,24 @@ func (m *saverMock) Save(ctx context.Context, person model.Person) error {
 	return fmt.Errorf("can't save person")
 }
 
+type batchSaverMock struct {
+	SaveBatchFn func(context.Context, []model.Person) error
+}
+
+func (m *batchSaverMock) SaveBatch(ctx context.Context, persons []model.Person) error {
+	if m != nil && m.SaveBatchFn != nil {
+		return m.SaveBatchFn(ctx, persons)
+	}
+	return fmt.Errorf("can't save persons")
+}
+
 type finderMock struct {
-	FindByIdFn func(ctx context.Context, id string) (model.Person, error)
+	FindByIdFn func(ctx context.Context, id string, includeDeleted bool) (model.Person, error)
 }
 
-func (m *finderMock) FindById(ctx context.Context, id string) (model.Person, error) {
+func (m *finderMock) FindById(ctx context.Context, id string, includeDeleted bool) (model.Person, error) {
 	if m != nil && m.FindByIdFn != nil {
-		return m.FindByIdFn(ctx, id)
+		return m.FindByIdFn(ctx, id, includeDeleted)
 	}
 	return model.Person{}, fmt.Errorf("can't find person")
 }
@@ -70,54 +81,123 @@ func (m *collectorMock) Collect(ctx context.Context, filter model.PersonFilter,
 	return nil, fmt.Errorf("can't collect persons")
 }
 
+type historianMock struct {
+	HistoryFn func(ctx context.Context, id string, limit, offset int) ([]model.PersonChange, error)
+}
+
+func (m *historianMock) History(ctx context.Context, id string, limit, offset int) ([]model.PersonChange, error) {
+	if m != nil && m.HistoryFn != nil {
+		return m.HistoryFn(ctx, id, limit, offset)
+	}
+	return nil, fmt.Errorf("can't get history")
+}
+
+type exporterMock struct {
+	ExportFn func(ctx context.Context, filter model.PersonFilter, fn func(model.Person) error) error
+}
+
+func (m *exporterMock) Export(ctx context.Context, filter model.PersonFilter, fn func(model.Person) error) error {
+	if m != nil && m.ExportFn != nil {
+		return m.ExportFn(ctx, filter, fn)
+	}
+	return fmt.Errorf("can't export persons")
+}
+
+type statsCollectorMock struct {
+	StatsFn func(ctx context.Context, filter model.PersonFilter, buckets model.AgeBuckets) (model.PersonStats, error)
+}
+
+func (m *statsCollectorMock) Stats(ctx context.Context, filter model.PersonFilter, buckets model.AgeBuckets) (model.PersonStats, error) {
+	if m != nil && m.StatsFn != nil {
+		return m.StatsFn(ctx, filter, buckets)
+	}
+	return model.PersonStats{}, fmt.Errorf("can't collect stats")
+}
+
 type updaterMock struct {
-	UpdateFn func(ctx context.Context, id string, meta model.PersonalMetaData) error
+	UpdateFn func(ctx context.Context, id string, patch model.PersonPatch, expectedVersion int64) (int64, error)
 }
 
-func (m *updaterMock) Update(ctx context.Context, id string, meta model.PersonalMetaData) error {
+func (m *updaterMock) Update(ctx context.Context, id string, patch model.PersonPatch, expectedVersion int64) (int64, error) {
 	if m != nil && m.UpdateFn != nil {
-		return m.UpdateFn(ctx, id, meta)
+		return m.UpdateFn(ctx, id, patch, expectedVersion)
 	}
-	return fmt.Errorf("can't update person")
+	return 0, fmt.Errorf("can't update person")
 }
 
 type deleterMock struct {
-	DeleteFn func(ctx context.Context, id string) error
+	DeleteFn func(ctx context.Context, id string, expectedVersion int64) error
 }
 
-func (m *deleterMock) Delete(ctx context.Context, id string) error {
+func (m *deleterMock) Delete(ctx context.Context, id string, expectedVersion int64) error {
 	if m != nil && m.DeleteFn != nil {
-		return m.DeleteFn(ctx, id)
+		return m.DeleteFn(ctx, id, expectedVersion)
 	}
 	return fmt.Errorf("can't delete person")
 }
 
+type restorerMock struct {
+	RestoreFn func(ctx context.Context, id string) error
+}
+
+func (m *restorerMock) Restore(ctx context.Context, id string) error {
+	if m != nil && m.RestoreFn != nil {
+		return m.RestoreFn(ctx, id)
+	}
+	return nil
+}
+
 type repoMock struct {
 	saverMock
+	batchSaverMock
 	finderMock
 	collectorMock
+	historianMock
+	exporterMock
+	statsCollectorMock
 	updaterMock
 	deleterMock
+	restorerMock
 }
 
 func (m *repoMock) Save(ctx context.Context, p model.Person) error {
 	return m.saverMock.Save(ctx, p)
 }
 
-func (m *repoMock) FindById(ctx context.Context, id string) (model.Person, error) {
-	return m.finderMock.FindById(ctx, id)
+func (m *repoMock) SaveBatch(ctx context.Context, persons []model.Person) error {
+	return m.batchSaverMock.SaveBatch(ctx, persons)
+}
+
+func (m *repoMock) FindById(ctx context.Context, id string, includeDeleted bool) (model.Person, error) {
+	return m.finderMock.FindById(ctx, id, includeDeleted)
 }
 
 func (m *repoMock) Collect(ctx context.Context, filter model.PersonFilter, limit, offset int) ([]model.Person, error) {
 	return m.collectorMock.Collect(ctx, filter, limit, offset)
 }
 
-func (m *repoMock) Update(ctx context.Context, id string, meta model.PersonalMetaData) error {
-	return m.updaterMock.Update(ctx, id, meta)
+func (m *repoMock) History(ctx context.Context, id string, limit, offset int) ([]model.PersonChange, error) {
+	return m.historianMock.History(ctx, id, limit, offset)
+}
+
+func (m *repoMock) Export(ctx context.Context, filter model.PersonFilter, fn func(model.Person) error) error {
+	return m.exporterMock.Export(ctx, filter, fn)
+}
+
+func (m *repoMock) Stats(ctx context.Context, filter model.PersonFilter, buckets model.AgeBuckets) (model.PersonStats, error) {
+	return m.statsCollectorMock.Stats(ctx, filter, buckets)
+}
+
+func (m *repoMock) Update(ctx context.Context, id string, patch model.PersonPatch, expectedVersion int64) (int64, error) {
+	return m.updaterMock.Update(ctx, id, patch, expectedVersion)
+}
+
+func (m *repoMock) Delete(ctx context.Context, id string, expectedVersion int64) error {
+	return m.deleterMock.Delete(ctx, id, expectedVersion)
 }
 
-func (m *repoMock) Delete(ctx context.Context, id string) error {
-	return m.deleterMock.Delete(ctx, id)
+func (m *repoMock) Restore(ctx context.Context, id string) error {
+	return m.restorerMock.Restore(ctx, id)
 }
 
 func TestManager_CreateFrom(t *testing.T) {
@@ -270,6 +350,255 @@ func TestManager_CreateFrom(t *testing.T) {
 	}
 }
 
+func TestManager_CreateFromBatch(t *testing.T) {
+	type services struct {
+		metaProvider metaDataProviderMock
+		batchSaver   batchSaverMock
+		saver        saverMock
+	}
+	type want struct {
+		saved   int
+		created []bool
+		err     error
+	}
+	tests := []struct {
+		name string
+		fios []model.FIO
+		want want
+		serv services
+	}{
+		{
+			name: "Valid fios, no error",
+			fios: []model.FIO{
+				{Name: "first", Surname: "test"},
+				{Name: "second", Surname: "test"},
+			},
+			want: want{
+				saved:   2,
+				created: []bool{true, true},
+			},
+			serv: services{
+				batchSaver: batchSaverMock{
+					SaveBatchFn: func(ctx context.Context, p []model.Person) error {
+						return nil
+					},
+				},
+				metaProvider: metaDataProviderMock{
+					AgeByNameFn: func(ctx context.Context, s string) (int, error) {
+						return 20, nil
+					},
+					GenderByNameFn: func(ctx context.Context, s string) (string, error) {
+						return "test", nil
+					},
+					NationByNameFn: func(ctx context.Context, s string) (string, error) {
+						return "go", nil
+					},
+				},
+			},
+		},
+		{
+			name: "Can't enrich one fio, partial result",
+			fios: []model.FIO{
+				{Name: "first", Surname: "test"},
+				{Name: "unknown", Surname: "test"},
+			},
+			want: want{
+				saved:   1,
+				created: []bool{true, false},
+			},
+			serv: services{
+				batchSaver: batchSaverMock{
+					SaveBatchFn: func(ctx context.Context, p []model.Person) error {
+						return nil
+					},
+				},
+				metaProvider: metaDataProviderMock{
+					AgeByNameFn: func(ctx context.Context, s string) (int, error) {
+						if s == "unknown" {
+							return 0, fmt.Errorf("error")
+						}
+						return 20, nil
+					},
+					GenderByNameFn: func(ctx context.Context, s string) (string, error) {
+						return "test", nil
+					},
+					NationByNameFn: func(ctx context.Context, s string) (string, error) {
+						return "go", nil
+					},
+				},
+			},
+		},
+		{
+			name: "Can't save persons, all failed",
+			fios: []model.FIO{
+				{Name: "first", Surname: "test"},
+				{Name: "second", Surname: "test"},
+			},
+			want: want{
+				saved:   2,
+				created: []bool{false, false},
+			},
+			serv: services{
+				batchSaver: batchSaverMock{
+					SaveBatchFn: func(ctx context.Context, p []model.Person) error {
+						return fmt.Errorf("id unique %w", model.ErrConstraintViolation)
+					},
+				},
+				metaProvider: metaDataProviderMock{
+					AgeByNameFn: func(ctx context.Context, s string) (int, error) {
+						return 20, nil
+					},
+					GenderByNameFn: func(ctx context.Context, s string) (string, error) {
+						return "test", nil
+					},
+					NationByNameFn: func(ctx context.Context, s string) (string, error) {
+						return "go", nil
+					},
+				},
+			},
+		},
+		{
+			name: "One person violates constraint, others created",
+			fios: []model.FIO{
+				{Name: "first", Surname: "test"},
+				{Name: "second", Surname: "test"},
+				{Name: "third", Surname: "test"},
+			},
+			want: want{
+				saved:   3,
+				created: []bool{true, false, true},
+			},
+			serv: services{
+				batchSaver: batchSaverMock{
+					SaveBatchFn: func(ctx context.Context, p []model.Person) error {
+						return fmt.Errorf("age check %w", model.ErrConstraintViolation)
+					},
+				},
+				saver: saverMock{
+					SaveFn: func(ctx context.Context, p model.Person) error {
+						if p.Name == "second" {
+							return fmt.Errorf("age check %w", model.ErrConstraintViolation)
+						}
+						return nil
+					},
+				},
+				metaProvider: metaDataProviderMock{
+					AgeByNameFn: func(ctx context.Context, s string) (int, error) {
+						return 20, nil
+					},
+					GenderByNameFn: func(ctx context.Context, s string) (string, error) {
+						return "test", nil
+					},
+					NationByNameFn: func(ctx context.Context, s string) (string, error) {
+						return "go", nil
+					},
+				},
+			},
+		},
+		{
+			name: "Quota exceeded, error",
+			fios: []model.FIO{
+				{Name: "first", Surname: "test"},
+			},
+			want: want{
+				err: fmt.Errorf("PersonManager.CreateFromBatch: %w", model.ErrQuotaExceeded),
+			},
+			serv: services{
+				batchSaver: batchSaverMock{
+					SaveBatchFn: func(ctx context.Context, p []model.Person) error {
+						return model.ErrQuotaExceeded
+					},
+				},
+				saver: saverMock{
+					SaveFn: func(ctx context.Context, p model.Person) error {
+						t.Error("person saved after the batch error")
+						return nil
+					},
+				},
+				metaProvider: metaDataProviderMock{
+					AgeByNameFn: func(ctx context.Context, s string) (int, error) {
+						return 20, nil
+					},
+					GenderByNameFn: func(ctx context.Context, s string) (string, error) {
+						return "test", nil
+					},
+					NationByNameFn: func(ctx context.Context, s string) (string, error) {
+						return "go", nil
+					},
+				},
+			},
+		},
+		{
+			name: "Canceled, error",
+			fios: []model.FIO{
+				{Name: "first", Surname: "test"},
+			},
+			want: want{
+				err: fmt.Errorf("PersonManager.CreateFromBatch: %w", context.Canceled),
+			},
+			serv: services{
+				batchSaver: batchSaverMock{
+					SaveBatchFn: func(ctx context.Context, p []model.Person) error {
+						return context.Canceled
+					},
+				},
+				metaProvider: metaDataProviderMock{
+					AgeByNameFn: func(ctx context.Context, s string) (int, error) {
+						return 20, nil
+					},
+					GenderByNameFn: func(ctx context.Context, s string) (string, error) {
+						return "test", nil
+					},
+					NationByNameFn: func(ctx context.Context, s string) (string, error) {
+						return "go", nil
+					},
+				},
+			},
+		},
+		{
+			name: "Empty batch, error",
+			want: want{
+				err: fmt.Errorf("PersonManager.CreateFromBatch: empty batch"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved int
+			saveBatchFn := tt.serv.batchSaver.SaveBatchFn
+			tt.serv.batchSaver.SaveBatchFn = func(ctx context.Context, p []model.Person) error {
+				saved = len(p)
+				return saveBatchFn(ctx, p)
+			}
+
+			manager, err := Manager(&repoMock{
+				saverMock:      tt.serv.saver,
+				batchSaverMock: tt.serv.batchSaver,
+			}, &tt.serv.metaProvider)
+			require.NoError(t, err)
+
+			results, err := manager.CreateFromBatch(context.Background(), tt.fios)
+			if tt.want.err != nil {
+				assert.EqualError(t, err, tt.want.err.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, results, len(tt.want.created))
+			assert.Equal(t, tt.want.saved, saved)
+			for i, created := range tt.want.created {
+				if created {
+					assert.NoError(t, results[i].Err)
+					assert.NotEmpty(t, results[i].Id)
+				} else {
+					assert.Error(t, results[i].Err)
+					assert.Empty(t, results[i].Id)
+				}
+			}
+		})
+	}
+}
+
 func TestManager_FindById(t *testing.T) {
 	type services struct {
 		finder finderMock
@@ -295,7 +624,7 @@ func TestManager_FindById(t *testing.T) {
 			},
 			serv: services{
 				finder: finderMock{
-					FindByIdFn: func(ctx context.Context, id string) (model.Person, error) {
+					FindByIdFn: func(ctx context.Context, id string, includeDeleted bool) (model.Person, error) {
 						return model.Person{
 							Id: "person_1",
 						}, nil
@@ -321,7 +650,7 @@ func TestManager_FindById(t *testing.T) {
 			},
 			serv: services{
 				finder: finderMock{
-					FindByIdFn: func(ctx context.Context, id string) (model.Person, error) {
+					FindByIdFn: func(ctx context.Context, id string, includeDeleted bool) (model.Person, error) {
 						return model.Person{}, nil
 					},
 				},
@@ -335,7 +664,7 @@ func TestManager_FindById(t *testing.T) {
 			},
 			serv: services{
 				finder: finderMock{
-					FindByIdFn: func(ctx context.Context, id string) (model.Person, error) {
+					FindByIdFn: func(ctx context.Context, id string, includeDeleted bool) (model.Person, error) {
 						return model.Person{}, fmt.Errorf("internal error")
 					},
 				},
@@ -348,7 +677,7 @@ func TestManager_FindById(t *testing.T) {
 			manager, err := Manager(&repoMock{finderMock: tt.serv.finder}, &metaDataProviderMock{})
 			require.NoError(t, err)
 
-			_, err = manager.FindById(context.Background(), tt.id)
+			_, err = manager.FindById(context.Background(), tt.id, false)
 			if tt.want.err != nil {
 				assert.EqualError(t, err, tt.want.err.Error())
 			}
@@ -504,88 +833,57 @@ func TestManager_Collect(t *testing.T) {
 	}
 }
 
-func TestManager_Update(t *testing.T) {
+func TestManager_History(t *testing.T) {
 	type services struct {
-		updater updaterMock
-	}
-	type args struct {
-		id   string
-		meta model.PersonalMetaData
+		historian historianMock
 	}
 	type want struct {
-		err error
+		changes []model.PersonChange
+		err     error
 	}
 	tests := []struct {
 		name string
-		args args
+		id   string
 		want want
 		serv services
 	}{
 		{
-			name: "Updated, no error",
-			args: args{
-				id: "person_1",
-				meta: model.PersonalMetaData{
-					Nation: "T1",
-					Age:    25,
-				},
-			},
+			name: "History, no error",
+			id:   "person_1",
 			want: want{
-				err: nil,
+				changes: []model.PersonChange{
+					{Id: 1, PersonId: "person_1", Op: model.OpCreate},
+					{Id: 2, PersonId: "person_1", Op: model.OpUpdate},
+				},
 			},
 			serv: services{
-				updater: updaterMock{
-					UpdateFn: func(ctx context.Context, id string, meta model.PersonalMetaData) error {
-						return nil
+				historian: historianMock{
+					HistoryFn: func(ctx context.Context, id string, limit, offset int) ([]model.PersonChange, error) {
+						return []model.PersonChange{
+							{Id: 1, PersonId: id, Op: model.OpCreate},
+							{Id: 2, PersonId: id, Op: model.OpUpdate},
+						}, nil
 					},
 				},
 			},
 		},
 		{
 			name: "Empty id, error",
-			args: args{
-				id: "",
-				meta: model.PersonalMetaData{
-					Nation: "GB",
-					Age:    67,
-				},
-			},
-			want: want{
-				err: fmt.Errorf("PersonManager.Update: empty id"),
-			},
-			serv: services{
-				updater: updaterMock{},
-			},
-		},
-		{
-			name: "Empty personal meta data, error",
-			args: args{
-				id:   "person_1",
-				meta: model.PersonalMetaData{},
-			},
+			id:   "",
 			want: want{
-				err: fmt.Errorf("PersonManager.Update: no data for update"),
-			},
-			serv: services{
-				updater: updaterMock{},
+				err: fmt.Errorf("PersonManager.History: empty id"),
 			},
 		},
 		{
-			name: "Updater error",
-			args: args{
-				id: "person_1",
-				meta: model.PersonalMetaData{
-					Nation: "T1",
-					Age:    25,
-				},
-			},
+			name: "Historian error",
+			id:   "person_1",
 			want: want{
-				err: fmt.Errorf("PersonManager.Update: internal error"),
+				err: fmt.Errorf("PersonManager.History: internal error"),
 			},
 			serv: services{
-				updater: updaterMock{
-					UpdateFn: func(ctx context.Context, id string, meta model.PersonalMetaData) error {
-						return fmt.Errorf("internal error")
+				historian: historianMock{
+					HistoryFn: func(ctx context.Context, id string, limit, offset int) ([]model.PersonChange, error) {
+						return nil, fmt.Errorf("internal error")
 					},
 				},
 			},
@@ -594,13 +892,551 @@ func TestManager_Update(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			manager, err := Manager(&repoMock{updaterMock: tt.serv.updater}, &metaDataProviderMock{})
+			manager, err := Manager(&repoMock{historianMock: tt.serv.historian}, &metaDataProviderMock{})
 			require.NoError(t, err)
 
-			err = manager.Update(context.Background(), tt.args.id, tt.args.meta)
+			changes, err := manager.History(context.Background(), tt.id, 0, 0)
 			if tt.want.err != nil {
 				assert.EqualError(t, err, tt.want.err.Error())
+				return
 			}
+			require.NoError(t, err)
+			assert.EqualValues(t, tt.want.changes, changes)
+		})
+	}
+}
+
+func TestManager_Export(t *testing.T) {
+	type services struct {
+		exporter exporterMock
+	}
+	type want struct {
+		exported []string
+		err      error
+	}
+	tests := []struct {
+		name string
+		fn   func(model.Person) error
+		want want
+		serv services
+	}{
+		{
+			name: "Export, no error",
+			want: want{
+				exported: []string{"1", "2"},
+			},
+			serv: services{
+				exporter: exporterMock{
+					ExportFn: func(ctx context.Context, filter model.PersonFilter, fn func(model.Person) error) error {
+						for _, id := range []string{"1", "2"} {
+							if err := fn(model.Person{Id: id}); err != nil {
+								return err
+							}
+						}
+						return nil
+					},
+				},
+			},
+		},
+		{
+			name: "Exporter error",
+			want: want{
+				err: fmt.Errorf("PersonManager.Export: internal error"),
+			},
+			serv: services{
+				exporter: exporterMock{
+					ExportFn: func(ctx context.Context, filter model.PersonFilter, fn func(model.Person) error) error {
+						return fmt.Errorf("internal error")
+					},
+				},
+			},
+		},
+		{
+			name: "Nil export func, error",
+			fn:   nil,
+			want: want{
+				err: fmt.Errorf("PersonManager.Export: nil export func"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager, err := Manager(&repoMock{exporterMock: tt.serv.exporter}, &metaDataProviderMock{})
+			require.NoError(t, err)
+
+			var exported []string
+			fn := func(p model.Person) error {
+				exported = append(exported, p.Id)
+				return nil
+			}
+			if tt.serv.exporter.ExportFn == nil {
+				fn = tt.fn
+			}
+
+			err = manager.Export(context.Background(), model.PersonFilter{}, fn)
+			if tt.want.err != nil {
+				assert.EqualError(t, err, tt.want.err.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.EqualValues(t, tt.want.exported, exported)
+		})
+	}
+}
+
+func TestManager_Stats(t *testing.T) {
+	type services struct {
+		statsCollector statsCollectorMock
+	}
+	type args struct {
+		filter  model.PersonFilter
+		buckets model.AgeBuckets
+	}
+	type want struct {
+		buckets model.AgeBuckets
+		stats   model.PersonStats
+		err     error
+	}
+	tests := []struct {
+		name string
+		args args
+		want want
+		serv services
+	}{
+		{
+			name: "Stats with buckets, no error",
+			args: args{
+				filter: model.PersonFilter{
+					Gender: "male",
+				},
+				buckets: model.AgeBuckets{20, 40},
+			},
+			want: want{
+				buckets: model.AgeBuckets{20, 40},
+				stats: model.PersonStats{
+					Total: 2,
+					Genders: []model.GenderStats{
+						{Gender: "male", Count: 2},
+					},
+				},
+			},
+			serv: services{
+				statsCollector: statsCollectorMock{
+					StatsFn: func(ctx context.Context, filter model.PersonFilter, buckets model.AgeBuckets) (model.PersonStats, error) {
+						return model.PersonStats{
+							Total: 2,
+							Genders: []model.GenderStats{
+								{Gender: "male", Count: 2},
+							},
+						}, nil
+					},
+				},
+			},
+		},
+		{
+			name: "Stats without buckets, default buckets",
+			args: args{},
+			want: want{
+				buckets: model.DefaultAgeBuckets,
+			},
+			serv: services{
+				statsCollector: statsCollectorMock{
+					StatsFn: func(ctx context.Context, filter model.PersonFilter, buckets model.AgeBuckets) (model.PersonStats, error) {
+						return model.PersonStats{}, nil
+					},
+				},
+			},
+		},
+		{
+			name: "Stats collector error",
+			args: args{},
+			want: want{
+				err: fmt.Errorf("PersonManager.Stats: internal error"),
+			},
+			serv: services{
+				statsCollector: statsCollectorMock{
+					StatsFn: func(ctx context.Context, filter model.PersonFilter, buckets model.AgeBuckets) (model.PersonStats, error) {
+						return model.PersonStats{}, fmt.Errorf("internal error")
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotBuckets model.AgeBuckets
+			statsFn := tt.serv.statsCollector.StatsFn
+			tt.serv.statsCollector.StatsFn = func(ctx context.Context, filter model.PersonFilter, buckets model.AgeBuckets) (model.PersonStats, error) {
+				gotBuckets = buckets
+				return statsFn(ctx, filter, buckets)
+			}
+
+			manager, err := Manager(&repoMock{statsCollectorMock: tt.serv.statsCollector}, &metaDataProviderMock{})
+			require.NoError(t, err)
+
+			stats, err := manager.Stats(context.Background(), tt.args.filter, tt.args.buckets)
+			if tt.want.err != nil {
+				assert.EqualError(t, err, tt.want.err.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.EqualValues(t, tt.want.buckets, gotBuckets)
+			assert.EqualValues(t, tt.want.stats, stats)
+		})
+	}
+}
+
+func TestManager_Update(t *testing.T) {
+	str := func(s string) *string { return &s }
+	num := func(i int) *int { return &i }
+
+	type services struct {
+		updater updaterMock
+	}
+	type args struct {
+		id              string
+		patch           model.PersonPatch
+		expectedVersion int64
+	}
+	type want struct {
+		version int64
+		err     error
+	}
+	tests := []struct {
+		name string
+		args args
+		want want
+		serv services
+	}{
+		{
+			name: "Updated, no error",
+			args: args{
+				id: "person_1",
+				patch: model.PersonPatch{
+					Surname: str("Ivanov"),
+					Nation:  str("T1"),
+					Age:     num(25),
+				},
+			},
+			want: want{
+				version: 2,
+			},
+			serv: services{
+				updater: updaterMock{
+					UpdateFn: func(ctx context.Context, id string, patch model.PersonPatch, expectedVersion int64) (int64, error) {
+						return 2, nil
+					},
+				},
+			},
+		},
+		{
+			name: "Empty id, error",
+			args: args{
+				id: "",
+				patch: model.PersonPatch{
+					Nation: str("GB"),
+					Age:    num(67),
+				},
+			},
+			want: want{
+				err: fmt.Errorf("PersonManager.Update: empty id"),
+			},
+			serv: services{
+				updater: updaterMock{},
+			},
+		},
+		{
+			name: "Empty patch, error",
+			args: args{
+				id:    "person_1",
+				patch: model.PersonPatch{},
+			},
+			want: want{
+				err: fmt.Errorf("PersonManager.Update: no data for update"),
+			},
+			serv: services{
+				updater: updaterMock{},
+			},
+		},
+		{
+			name: "Cleared name, error",
+			args: args{
+				id: "person_1",
+				patch: model.PersonPatch{
+					Name: str(""),
+				},
+			},
+			want: want{
+				err: fmt.Errorf("PersonManager.Update: empty required name"),
+			},
+			serv: services{
+				updater: updaterMock{},
+			},
+		},
+		{
+			name: "Version mismatch, error",
+			args: args{
+				id: "person_1",
+				patch: model.PersonPatch{
+					Age: num(25),
+				},
+				expectedVersion: 2,
+			},
+			want: want{
+				err: fmt.Errorf("PersonManager.Update: %w", model.ErrVersionMismatch),
+			},
+			serv: services{
+				updater: updaterMock{
+					UpdateFn: func(ctx context.Context, id string, patch model.PersonPatch, expectedVersion int64) (int64, error) {
+						if expectedVersion != 3 {
+							return 0, model.ErrVersionMismatch
+						}
+						return 4, nil
+					},
+				},
+			},
+		},
+		{
+			name: "Negative expected version, error",
+			args: args{
+				id: "person_1",
+				patch: model.PersonPatch{
+					Age: num(25),
+				},
+				expectedVersion: -1,
+			},
+			want: want{
+				err: fmt.Errorf("PersonManager.Update: negative expected version"),
+			},
+			serv: services{
+				updater: updaterMock{},
+			},
+		},
+		{
+			name: "Updater error",
+			args: args{
+				id: "person_1",
+				patch: model.PersonPatch{
+					Nation: str("T1"),
+					Age:    num(25),
+				},
+			},
+			want: want{
+				err: fmt.Errorf("PersonManager.Update: internal error"),
+			},
+			serv: services{
+				updater: updaterMock{
+					UpdateFn: func(ctx context.Context, id string, patch model.PersonPatch, expectedVersion int64) (int64, error) {
+						return 0, fmt.Errorf("internal error")
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager, err := Manager(&repoMock{updaterMock: tt.serv.updater}, &metaDataProviderMock{})
+			require.NoError(t, err)
+
+			version, _, err := manager.Update(context.Background(), tt.args.id, tt.args.patch, tt.args.expectedVersion, false)
+			if tt.want.err != nil {
+				assert.EqualError(t, err, tt.want.err.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want.version, version)
+		})
+	}
+}
+
+func TestManager_Replace(t *testing.T) {
+	type args struct {
+		id   string
+		fio  model.FIO
+		meta model.PersonalMetaData
+	}
+	type want struct {
+		patch model.PersonPatch
+		err   error
+	}
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{
+			name: "Replaced, metadata cleared, no error",
+			args: args{
+				id: "person_1",
+				fio: model.FIO{
+					Name:    "Ivan",
+					Surname: "Ivanov",
+				},
+				meta: model.PersonalMetaData{
+					Age: 30,
+				},
+			},
+			want: want{
+				patch: model.NewPersonReplacement(
+					model.FIO{Name: "Ivan", Surname: "Ivanov"},
+					model.PersonalMetaData{Age: 30},
+				),
+			},
+		},
+		{
+			name: "Empty surname, error",
+			args: args{
+				id: "person_1",
+				fio: model.FIO{
+					Name: "Ivan",
+				},
+			},
+			want: want{
+				err: fmt.Errorf("PersonManager.Replace: empty required surname"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var patched model.PersonPatch
+			updater := updaterMock{
+				UpdateFn: func(ctx context.Context, id string, patch model.PersonPatch, expectedVersion int64) (int64, error) {
+					patched = patch
+					return 1, nil
+				},
+			}
+			manager, err := Manager(&repoMock{updaterMock: updater}, &metaDataProviderMock{})
+			require.NoError(t, err)
+
+			_, _, err = manager.Replace(context.Background(), tt.args.id, tt.args.fio, tt.args.meta, 0, false)
+			if tt.want.err != nil {
+				assert.EqualError(t, err, tt.want.err.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want.patch, patched)
+		})
+	}
+}
+
+func TestManager_UpdateReenrich(t *testing.T) {
+	str := func(s string) *string { return &s }
+	num := func(i int) *int { return &i }
+
+	current := model.Person{
+		Id: "person_1",
+		FIO: model.FIO{
+			Name:    "Ivan",
+			Surname: "Ivanov",
+		},
+		PersonalMetaData: model.PersonalMetaData{
+			Nation: "RU",
+			Gender: "male",
+			Age:    40,
+		},
+		Version:   3,
+		Overrides: []model.MetaAttr{model.AttrNation},
+	}
+	metaProvider := metaDataProviderMock{
+		AgeByNameFn: func(ctx context.Context, name string) (int, error) {
+			return 25, nil
+		},
+		GenderByNameFn: func(ctx context.Context, name string) (string, error) {
+			return "female", nil
+		},
+		NationByNameFn: func(ctx context.Context, name string) (string, error) {
+			return "UA", nil
+		},
+	}
+
+	type want struct {
+		recomputed []model.MetaAttr
+		patch      model.PersonPatch
+		version    int64
+		err        error
+	}
+	tests := []struct {
+		name            string
+		patch           model.PersonPatch
+		expectedVersion int64
+		want            want
+	}{
+		{
+			name: "Renamed, not overridden attributes recomputed",
+			patch: model.PersonPatch{
+				Name: str("Maria"),
+				Age:  num(30),
+			},
+			want: want{
+				recomputed: []model.MetaAttr{model.AttrGender},
+				patch: model.PersonPatch{
+					Name:     str("Maria"),
+					Age:      num(30),
+					Gender:   str("female"),
+					Enriched: []model.MetaAttr{model.AttrGender},
+				},
+				version: 3,
+			},
+		},
+		{
+			name: "Same name, nothing recomputed",
+			patch: model.PersonPatch{
+				Name: str("Ivan"),
+			},
+			want: want{
+				patch: model.PersonPatch{
+					Name: str("Ivan"),
+				},
+				version: 3,
+			},
+		},
+		{
+			name: "Version mismatch, error",
+			patch: model.PersonPatch{
+				Name: str("Maria"),
+			},
+			expectedVersion: 2,
+			want: want{
+				err: fmt.Errorf("PersonManager.Update: %w", model.ErrVersionMismatch),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				patched model.PersonPatch
+				version int64
+			)
+			repo := &repoMock{
+				finderMock: finderMock{
+					FindByIdFn: func(ctx context.Context, id string, includeDeleted bool) (model.Person, error) {
+						return current, nil
+					},
+				},
+				updaterMock: updaterMock{
+					UpdateFn: func(ctx context.Context, id string, patch model.PersonPatch, expectedVersion int64) (int64, error) {
+						patched, version = patch, expectedVersion
+						return expectedVersion + 1, nil
+					},
+				},
+			}
+			manager, err := Manager(repo, &metaProvider)
+			require.NoError(t, err)
+
+			_, recomputed, err := manager.Update(context.Background(), current.Id, tt.patch, tt.expectedVersion, true)
+			if tt.want.err != nil {
+				assert.EqualError(t, err, tt.want.err.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want.recomputed, recomputed)
+			assert.Equal(t, tt.want.patch, patched)
+			assert.Equal(t, tt.want.version, version)
 		})
 	}
 }
@@ -626,7 +1462,7 @@ func TestManager_Delete(t *testing.T) {
 			},
 			serv: services{
 				deleter: deleterMock{
-					DeleteFn: func(ctx context.Context, id string) error {
+					DeleteFn: func(ctx context.Context, id string, expectedVersion int64) error {
 						return nil
 					},
 				},
@@ -650,7 +1486,7 @@ func TestManager_Delete(t *testing.T) {
 			},
 			serv: services{
 				deleter: deleterMock{
-					DeleteFn: func(ctx context.Context, id string) error {
+					DeleteFn: func(ctx context.Context, id string, expectedVersion int64) error {
 						return fmt.Errorf("internal error")
 					},
 				},
@@ -663,10 +1499,72 @@ func TestManager_Delete(t *testing.T) {
 			manager, err := Manager(&repoMock{deleterMock: tt.serv.deleter}, &metaDataProviderMock{})
 			require.NoError(t, err)
 
-			err = manager.Delete(context.Background(), tt.id)
+			err = manager.Delete(context.Background(), tt.id, 0)
 			if tt.want.err != nil {
 				assert.EqualError(t, err, tt.want.err.Error())
 			}
 		})
 	}
 }
+
+func TestManager_Restore(t *testing.T) {
+	type services struct {
+		restorer restorerMock
+	}
+	type want struct {
+		err error
+	}
+	tests := []struct {
+		name string
+		id   string
+		want want
+		serv services
+	}{
+		{
+			name: "Restored, no error",
+			id:   "person_1",
+			serv: services{
+				restorer: restorerMock{
+					RestoreFn: func(ctx context.Context, id string) error {
+						return nil
+					},
+				},
+			},
+		},
+		{
+			name: "Empty id, error",
+			id:   "",
+			want: want{
+				err: fmt.Errorf("PersonManager.Restore: empty id"),
+			},
+		},
+		{
+			name: "Not deleted, error",
+			id:   "person_1",
+			want: want{
+				err: fmt.Errorf("PersonManager.Restore: %w", model.ErrNotFound),
+			},
+			serv: services{
+				restorer: restorerMock{
+					RestoreFn: func(ctx context.Context, id string) error {
+						return model.ErrNotFound
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager, err := Manager(&repoMock{restorerMock: tt.serv.restorer}, &metaDataProviderMock{})
+			require.NoError(t, err)
+
+			err = manager.Restore(context.Background(), tt.id)
+			if tt.want.err != nil {
+				assert.EqualError(t, err, tt.want.err.Error())
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
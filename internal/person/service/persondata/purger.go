@@ -0,0 +1,71 @@
+package persondata
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alukart32/effective-mobile-test-task/internal/pkg/reqmeta"
+	"github.com/alukart32/effective-mobile-test-task/internal/pkg/zerologx"
+)
+
+type purgeRepo interface {
+	Purge(ctx context.Context, deletedBefore time.Time) (int64, error)
+}
+
+// purger periodically hard deletes the persons soft deleted longer than the retention.
+type purger struct {
+	repo      purgeRepo
+	retention time.Duration
+	interval  time.Duration
+}
+
+func Purger(r purgeRepo, retention, interval time.Duration) (*purger, error) {
+	if r == nil {
+		return nil, fmt.Errorf("repo is nil")
+	}
+	if retention <= 0 {
+		return nil, fmt.Errorf("non positive retention")
+	}
+	if interval <= 0 {
+		return nil, fmt.Errorf("non positive interval")
+	}
+
+	return &purger{
+		repo:      r,
+		retention: retention,
+		interval:  interval,
+	}, nil
+}
+
+// Run purges the persons every interval until ctx is done.
+func (p *purger) Run(ctx context.Context) {
+	logger := zerologx.Get()
+	ctx = reqmeta.WithSource(ctx, "purge")
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		n, err := p.Purge(ctx)
+		if err != nil {
+			logger.Err(err).Msg("purge deleted persons")
+		} else if n > 0 {
+			logger.Info().Int64("purged", n).Msg("purge deleted persons")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Purge hard deletes the persons soft deleted longer than the retention.
+func (p *purger) Purge(ctx context.Context) (int64, error) {
+	n, err := p.repo.Purge(ctx, time.Now().Add(-p.retention))
+	if err != nil {
+		return 0, fmt.Errorf("PersonPurger.Purge: %w", err)
+	}
+	return n, nil
+}
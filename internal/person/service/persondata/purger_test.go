@@ -0,0 +1,78 @@
+package persondata
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type purgeRepoMock struct {
+	PurgeFn func(ctx context.Context, deletedBefore time.Time) (int64, error)
+}
+
+func (m *purgeRepoMock) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	if m != nil && m.PurgeFn != nil {
+		return m.PurgeFn(ctx, deletedBefore)
+	}
+	return 0, nil
+}
+
+func TestPurger_Purge(t *testing.T) {
+	type want struct {
+		purged int64
+		err    error
+	}
+	tests := []struct {
+		name      string
+		retention time.Duration
+		repo      purgeRepoMock
+		want      want
+	}{
+		{
+			name:      "Purged, no error",
+			retention: time.Hour,
+			repo: purgeRepoMock{
+				PurgeFn: func(ctx context.Context, deletedBefore time.Time) (int64, error) {
+					if time.Since(deletedBefore) < time.Hour {
+						return 0, fmt.Errorf("retention is not respected")
+					}
+					return 3, nil
+				},
+			},
+			want: want{
+				purged: 3,
+			},
+		},
+		{
+			name:      "Repo error",
+			retention: time.Hour,
+			repo: purgeRepoMock{
+				PurgeFn: func(ctx context.Context, deletedBefore time.Time) (int64, error) {
+					return 0, fmt.Errorf("internal error")
+				},
+			},
+			want: want{
+				err: fmt.Errorf("PersonPurger.Purge: internal error"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			purger, err := Purger(&tt.repo, tt.retention, time.Minute)
+			require.NoError(t, err)
+
+			n, err := purger.Purge(context.Background())
+			if tt.want.err != nil {
+				assert.EqualError(t, err, tt.want.err.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want.purged, n)
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/alukart32/effective-mobile-test-task/internal/person/model"
+	"github.com/alukart32/effective-mobile-test-task/internal/pkg/zerologx"
+)
+
+type dispatchRepo interface {
+	Enqueue(ctx context.Context, msgs []model.OutboxMessage) error
+	Claim(ctx context.Context, limit int, leaseUntil time.Time) ([]model.WebhookDispatch, error)
+	RecordAttempt(ctx context.Context, attempt model.DeliveryAttempt, disableAfter int) (bool, error)
+}
+
+type sender interface {
+	// Send posts the signed payload to the webhook, it returns the response
+	// status code and the error if the webhook didn't accept the payload.
+	Send(ctx context.Context, d model.WebhookDispatch) (int, error)
+}
+
+// DispatcherConfig is the delivery schedule of the webhooks.
+type DispatcherConfig struct {
+	// BatchSize limits the deliveries sent at once.
+	BatchSize    int
+	PollInterval time.Duration
+	// MaxAttempts is the number of attempts before the delivery is given up.
+	MaxAttempts int
+	// Backoff is the delay after the first failed attempt, it doubles with
+	// each next attempt up to MaxBackoff.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// DisableAfter is the number of consecutive failed attempts the webhook
+	// is disabled after, zero never disables it.
+	DisableAfter int
+	// Timeout limits the single attempt.
+	Timeout time.Duration
+}
+
+const (
+	_defaultBatchSize    = 50
+	_defaultPollInterval = time.Second
+	_defaultMaxAttempts  = 8
+	_defaultBackoff      = time.Second
+	_defaultMaxBackoff   = time.Hour
+	_defaultTimeout      = 10 * time.Second
+)
+
+type dispatcher struct {
+	repo   dispatchRepo
+	sender sender
+	cfg    DispatcherConfig
+	now    func() time.Time
+}
+
+func Dispatcher(r dispatchRepo, s sender, cfg DispatcherConfig) (*dispatcher, error) {
+	if r == nil {
+		return nil, fmt.Errorf("repo is nil")
+	}
+	if s == nil {
+		return nil, fmt.Errorf("sender is nil")
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = _defaultBatchSize
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = _defaultPollInterval
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = _defaultMaxAttempts
+	}
+	if cfg.Backoff <= 0 {
+		cfg.Backoff = _defaultBackoff
+	}
+	if cfg.MaxBackoff < cfg.Backoff {
+		cfg.MaxBackoff = _defaultMaxBackoff
+	}
+	if cfg.DisableAfter < 0 {
+		cfg.DisableAfter = 0
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = _defaultTimeout
+	}
+
+	return &dispatcher{
+		repo:   r,
+		sender: s,
+		cfg:    cfg,
+		now:    time.Now,
+	}, nil
+}
+
+// Publish enqueues the deliveries of the events to the subscribed webhooks.
+func (d *dispatcher) Publish(ctx context.Context, msgs []model.OutboxMessage) error {
+	if err := d.repo.Enqueue(ctx, msgs); err != nil {
+		return fmt.Errorf("WebhookDispatcher.Publish: %w", err)
+	}
+	return nil
+}
+
+// Run delivers the due deliveries until ctx is done. The full batches are
+// delivered one after another, otherwise the deliveries are polled every
+// poll interval.
+func (d *dispatcher) Run(ctx context.Context) {
+	logger := zerologx.Get().With().Str("op", "webhook dispatcher").Logger()
+
+	poll := time.NewTimer(0)
+	defer poll.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-poll.C:
+			n, err := d.Dispatch(ctx)
+			if err != nil {
+				logger.Err(err).Send()
+			}
+			next := d.cfg.PollInterval
+			if err == nil && n == d.cfg.BatchSize {
+				next = 0
+			}
+			poll.Reset(next)
+		}
+	}
+}
+
+// Dispatch sends the claimed due deliveries concurrently and logs the attempts.
+// It returns the number of the sent deliveries.
+func (d *dispatcher) Dispatch(ctx context.Context) (int, error) {
+	// The claimed deliveries aren't claimed again until all the attempts time out.
+	leaseUntil := d.now().Add(2 * d.cfg.Timeout)
+	dispatches, err := d.repo.Claim(ctx, d.cfg.BatchSize, leaseUntil)
+	if err != nil {
+		return 0, fmt.Errorf("WebhookDispatcher.Dispatch: %w", err)
+	}
+
+	errs := make([]error, len(dispatches))
+	var wg sync.WaitGroup
+	wg.Add(len(dispatches))
+	for i := range dispatches {
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = d.deliver(ctx, dispatches[i])
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return len(dispatches), fmt.Errorf("WebhookDispatcher.Dispatch: %w", err)
+		}
+	}
+	return len(dispatches), nil
+}
+
+// deliver sends the delivery once and logs the attempt, the failed delivery
+// is retried after the backoff until the max attempts.
+func (d *dispatcher) deliver(ctx context.Context, dispatch model.WebhookDispatch) error {
+	sendCtx, cancel := context.WithTimeout(ctx, d.cfg.Timeout)
+	code, sendErr := d.sender.Send(sendCtx, dispatch)
+	cancel()
+
+	attempt := model.DeliveryAttempt{
+		DeliveryId:   dispatch.Delivery.Id,
+		WebhookId:    dispatch.Webhook.Id,
+		ResponseCode: code,
+	}
+	if sendErr != nil {
+		attempt.Err = sendErr.Error()
+		if attempts := dispatch.Delivery.Attempts + 1; attempts < d.cfg.MaxAttempts {
+			next := d.now().Add(d.backoff(attempts))
+			attempt.NextAttemptAt = &next
+		}
+	}
+
+	disabled, err := d.repo.RecordAttempt(ctx, attempt, d.cfg.DisableAfter)
+	if err != nil {
+		return err
+	}
+	if disabled {
+		zerologx.Get().Warn().
+			Str("webhook_id", dispatch.Webhook.Id).
+			Int("failures", d.cfg.DisableAfter).
+			Msg("webhook disabled")
+	}
+	return nil
+}
+
+// backoff returns the delay after the failed attempt.
+func (d *dispatcher) backoff(attempts int) time.Duration {
+	delay := d.cfg.Backoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= d.cfg.MaxBackoff {
+			return d.cfg.MaxBackoff
+		}
+	}
+	return delay
+}
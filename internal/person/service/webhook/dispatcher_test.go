@@ -0,0 +1,175 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alukart32/effective-mobile-test-task/internal/person/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type dispatchRepoMock struct {
+	EnqueueFn       func(ctx context.Context, msgs []model.OutboxMessage) error
+	ClaimFn         func(ctx context.Context, limit int, leaseUntil time.Time) ([]model.WebhookDispatch, error)
+	RecordAttemptFn func(ctx context.Context, attempt model.DeliveryAttempt, disableAfter int) (bool, error)
+}
+
+func (m *dispatchRepoMock) Enqueue(ctx context.Context, msgs []model.OutboxMessage) error {
+	if m != nil && m.EnqueueFn != nil {
+		return m.EnqueueFn(ctx, msgs)
+	}
+	return nil
+}
+
+func (m *dispatchRepoMock) Claim(ctx context.Context, limit int, leaseUntil time.Time) ([]model.WebhookDispatch, error) {
+	if m != nil && m.ClaimFn != nil {
+		return m.ClaimFn(ctx, limit, leaseUntil)
+	}
+	return nil, nil
+}
+
+func (m *dispatchRepoMock) RecordAttempt(ctx context.Context, attempt model.DeliveryAttempt, disableAfter int) (bool, error) {
+	if m != nil && m.RecordAttemptFn != nil {
+		return m.RecordAttemptFn(ctx, attempt, disableAfter)
+	}
+	return false, nil
+}
+
+type senderMock struct {
+	SendFn func(ctx context.Context, d model.WebhookDispatch) (int, error)
+}
+
+func (m *senderMock) Send(ctx context.Context, d model.WebhookDispatch) (int, error) {
+	if m != nil && m.SendFn != nil {
+		return m.SendFn(ctx, d)
+	}
+	return 200, nil
+}
+
+func TestDispatcher_Dispatch(t *testing.T) {
+	now := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+	timeAt := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+	dispatchOf := func(attempts int) model.WebhookDispatch {
+		return model.WebhookDispatch{
+			Webhook:  model.Webhook{Id: "webhook_1"},
+			Delivery: model.WebhookDelivery{Id: 1, WebhookId: "webhook_1", Attempts: attempts},
+		}
+	}
+	cfg := DispatcherConfig{
+		MaxAttempts: 3,
+		Backoff:     time.Second,
+		MaxBackoff:  time.Minute,
+	}
+
+	tests := []struct {
+		name     string
+		dispatch model.WebhookDispatch
+		sender   senderMock
+		want     model.DeliveryAttempt
+	}{
+		{
+			name:     "Delivered, no error",
+			dispatch: dispatchOf(0),
+			want: model.DeliveryAttempt{
+				DeliveryId:   1,
+				WebhookId:    "webhook_1",
+				ResponseCode: 200,
+			},
+		},
+		{
+			name:     "First attempt failed, retried after backoff",
+			dispatch: dispatchOf(0),
+			sender: senderMock{
+				SendFn: func(ctx context.Context, d model.WebhookDispatch) (int, error) {
+					return 503, fmt.Errorf("unexpected status: 503")
+				},
+			},
+			want: model.DeliveryAttempt{
+				DeliveryId:    1,
+				WebhookId:     "webhook_1",
+				ResponseCode:  503,
+				Err:           "unexpected status: 503",
+				NextAttemptAt: timeAt(time.Second),
+			},
+		},
+		{
+			name:     "Second attempt failed, backoff doubled",
+			dispatch: dispatchOf(1),
+			sender: senderMock{
+				SendFn: func(ctx context.Context, d model.WebhookDispatch) (int, error) {
+					return 0, fmt.Errorf("connection refused")
+				},
+			},
+			want: model.DeliveryAttempt{
+				DeliveryId:    1,
+				WebhookId:     "webhook_1",
+				Err:           "connection refused",
+				NextAttemptAt: timeAt(2 * time.Second),
+			},
+		},
+		{
+			name:     "Last attempt failed, given up",
+			dispatch: dispatchOf(2),
+			sender: senderMock{
+				SendFn: func(ctx context.Context, d model.WebhookDispatch) (int, error) {
+					return 500, fmt.Errorf("unexpected status: 500")
+				},
+			},
+			want: model.DeliveryAttempt{
+				DeliveryId:   1,
+				WebhookId:    "webhook_1",
+				ResponseCode: 500,
+				Err:          "unexpected status: 500",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				mtx      sync.Mutex
+				attempts []model.DeliveryAttempt
+			)
+			repo := &dispatchRepoMock{
+				ClaimFn: func(ctx context.Context, limit int, leaseUntil time.Time) ([]model.WebhookDispatch, error) {
+					return []model.WebhookDispatch{tt.dispatch}, nil
+				},
+				RecordAttemptFn: func(ctx context.Context, attempt model.DeliveryAttempt, disableAfter int) (bool, error) {
+					mtx.Lock()
+					defer mtx.Unlock()
+					attempts = append(attempts, attempt)
+					return false, nil
+				},
+			}
+			d, err := Dispatcher(repo, &tt.sender, cfg)
+			require.NoError(t, err)
+			d.now = func() time.Time { return now }
+
+			n, err := d.Dispatch(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, 1, n)
+			assert.Equal(t, []model.DeliveryAttempt{tt.want}, attempts)
+		})
+	}
+}
+
+func TestDispatcher_backoff(t *testing.T) {
+	d, err := Dispatcher(&dispatchRepoMock{}, &senderMock{}, DispatcherConfig{
+		Backoff:    time.Second,
+		MaxBackoff: 10 * time.Second,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, time.Second, d.backoff(1))
+	assert.Equal(t, 2*time.Second, d.backoff(2))
+	assert.Equal(t, 8*time.Second, d.backoff(4))
+	assert.Equal(t, 10*time.Second, d.backoff(5))
+	assert.Equal(t, 10*time.Second, d.backoff(100))
+}
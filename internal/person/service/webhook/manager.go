@@ -0,0 +1,94 @@
+// Package webhook manages the webhook subscriptions to the person events
+// and delivers the events to them.
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alukart32/effective-mobile-test-task/internal/person/model"
+)
+
+type repo interface {
+	Save(ctx context.Context, w model.Webhook) error
+	FindById(ctx context.Context, id string) (model.Webhook, error)
+	List(ctx context.Context, limit, offset int) ([]model.Webhook, error)
+	Delete(ctx context.Context, id string) error
+	Enable(ctx context.Context, id string) error
+	Deliveries(ctx context.Context, webhookId string, limit, offset int) ([]model.WebhookDelivery, error)
+}
+
+type manager struct {
+	repo repo
+}
+
+func Manager(r repo) (*manager, error) {
+	if r == nil {
+		return nil, fmt.Errorf("repo is nil")
+	}
+	return &manager{repo: r}, nil
+}
+
+// Register saves the new webhook, the events are delivered to it since then.
+func (m *manager) Register(ctx context.Context, w model.Webhook) error {
+	if len(w.Id) == 0 || len(w.Secret) == 0 {
+		return fmt.Errorf("WebhookManager.Register: webhook isn't initialized")
+	}
+	if err := m.repo.Save(ctx, w); err != nil {
+		return fmt.Errorf("WebhookManager.Register: %w", err)
+	}
+	return nil
+}
+
+func (m *manager) FindById(ctx context.Context, id string) (model.Webhook, error) {
+	if len(id) == 0 {
+		return model.Webhook{}, fmt.Errorf("WebhookManager.FindById: empty id")
+	}
+	w, err := m.repo.FindById(ctx, id)
+	if err != nil {
+		return model.Webhook{}, fmt.Errorf("WebhookManager.FindById: %w", err)
+	}
+	return w, nil
+}
+
+func (m *manager) List(ctx context.Context, limit, offset int) ([]model.Webhook, error) {
+	webhooks, err := m.repo.List(ctx, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("WebhookManager.List: %w", err)
+	}
+	return webhooks, nil
+}
+
+// Delete deletes the webhook, its pending deliveries are dropped.
+func (m *manager) Delete(ctx context.Context, id string) error {
+	if len(id) == 0 {
+		return fmt.Errorf("WebhookManager.Delete: empty id")
+	}
+	if err := m.repo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("WebhookManager.Delete: %w", err)
+	}
+	return nil
+}
+
+// Enable enables the disabled webhook, its pending deliveries are resumed.
+func (m *manager) Enable(ctx context.Context, id string) error {
+	if len(id) == 0 {
+		return fmt.Errorf("WebhookManager.Enable: empty id")
+	}
+	if err := m.repo.Enable(ctx, id); err != nil {
+		return fmt.Errorf("WebhookManager.Enable: %w", err)
+	}
+	return nil
+}
+
+// Deliveries returns the delivery log of the webhook, the latest first.
+func (m *manager) Deliveries(ctx context.Context, webhookId string, limit, offset int) ([]model.WebhookDelivery, error) {
+	if len(webhookId) == 0 {
+		return nil, fmt.Errorf("WebhookManager.Deliveries: empty id")
+	}
+	deliveries, err := m.repo.Deliveries(ctx, webhookId, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("WebhookManager.Deliveries: %w", err)
+	}
+	return deliveries, nil
+}
@@ -0,0 +1,535 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alukart32/effective-mobile-test-task/internal/person/model"
+	"github.com/alukart32/effective-mobile-test-task/internal/person/storage/persons"
+	"github.com/alukart32/effective-mobile-test-task/internal/pkg/reqmeta"
+	"github.com/alukart32/effective-mobile-test-task/internal/pkg/sqlite"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The conformance suite runs against every backend. The memory and SQLite
+// ones always run, the others need the migrated postgres and redis, their
+// data is deleted before every test:
+//
+//	TEST_POSTGRES_URL=postgres://... TEST_REDIS_URL=redis://... \
+//		go test -run Conformance ./internal/person/storage
+
+func TestPersons_Conformance(t *testing.T) {
+	for _, backend := range []Backend{BackendMemory, BackendSQLite, BackendPostgres, BackendPostgresRedis} {
+		t.Run(string(backend), func(t *testing.T) {
+			testConformance(t, func(t *testing.T) PersonRepo {
+				return newTestRepo(t, backend)
+			})
+		})
+	}
+}
+
+// newTestRepo returns the empty persons storage of the backend.
+func newTestRepo(t *testing.T, backend Backend) PersonRepo {
+	t.Helper()
+	ctx := context.Background()
+	cfg := Config{
+		Backend: backend,
+		Cache:   persons.CacheConfig{TTL: time.Minute, NegativeTTL: time.Minute, ResultTTL: time.Minute},
+		Quotas:  model.TenantQuotas{Tenants: map[string]int{"limited": 2}},
+	}
+
+	if backend.UsesPostgres() {
+		url := os.Getenv("TEST_POSTGRES_URL")
+		if len(url) == 0 {
+			t.Skip("TEST_POSTGRES_URL isn't set")
+		}
+		pool, err := pgxpool.New(ctx, url)
+		require.NoError(t, err)
+		t.Cleanup(pool.Close)
+		_, err = pool.Exec(ctx, "TRUNCATE persons, person_history, outbox, tenant_usage")
+		require.NoError(t, err)
+		cfg.Postgres = pool
+	}
+	if backend.UsesRedis() {
+		url := os.Getenv("TEST_REDIS_URL")
+		if len(url) == 0 {
+			t.Skip("TEST_REDIS_URL isn't set")
+		}
+		opt, err := redis.ParseURL(url)
+		require.NoError(t, err)
+		client := redis.NewClient(opt)
+		t.Cleanup(func() { client.Close() })
+		require.NoError(t, client.FlushDB(ctx).Err())
+		cfg.Redis = client
+	}
+
+	if backend.UsesSQLite() {
+		db, err := sqlite.Open(filepath.Join(t.TempDir(), "persons.db"))
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		require.NoError(t, sqlite.Migrate(db, persons.SQLiteMigrations, persons.SQLiteMigrationsDir))
+		cfg.SQLite = db
+	}
+
+	repo, err := Persons(cfg)
+	require.NoError(t, err)
+	return repo
+}
+
+func testConformance(t *testing.T, newRepo func(t *testing.T) PersonRepo) {
+	ctx := context.Background()
+
+	newPerson := func(name, nation, gender string, age int) model.Person {
+		fio, err := model.NewFIO(name, "Ivanov", "")
+		require.NoError(t, err)
+		return model.NewPerson(fio, model.PersonalMetaData{Nation: nation, Gender: gender, Age: age})
+	}
+	ids := func(persons []model.Person) []string {
+		ids := make([]string, len(persons))
+		for i, p := range persons {
+			ids[i] = p.Id
+		}
+		return ids
+	}
+	sorted := func(persons ...model.Person) []string {
+		ids := ids(persons)
+		slices.Sort(ids)
+		return ids
+	}
+	str := func(v string) *string { return &v }
+	noAge := 0
+
+	t.Run("Save and find, no error", func(t *testing.T) {
+		repo := newRepo(t)
+		p := newPerson("Ivan", "RU", "male", 30)
+		require.NoError(t, repo.Save(ctx, p))
+
+		found, err := repo.FindById(ctx, p.Id, false)
+		require.NoError(t, err)
+		assert.Equal(t, p.Id, found.Id)
+		assert.Equal(t, p.FIO, found.FIO)
+		assert.Equal(t, p.PersonalMetaData, found.PersonalMetaData)
+		assert.Equal(t, model.InitialVersion, found.Version)
+		assert.False(t, found.CreatedAt.IsZero())
+		assert.NotNil(t, found.EnrichedAt)
+		assert.Nil(t, found.DeletedAt)
+
+		assert.Error(t, repo.Save(ctx, p))
+	})
+
+	t.Run("Save invalid, check violation", func(t *testing.T) {
+		repo := newRepo(t)
+		assert.ErrorIs(t, repo.Save(ctx, newPerson("Ivan", "RU", "male", 0)), model.ErrConstraintViolation)
+		assert.ErrorIs(t, repo.SaveBatch(ctx, []model.Person{newPerson("Ivan", "RU", "male", -1)}),
+			model.ErrConstraintViolation)
+
+		p := newPerson("Ivan", "RU", "male", 30)
+		require.NoError(t, repo.Save(ctx, p))
+		age := -1
+		_, err := repo.Update(ctx, p.Id, model.PersonPatch{Age: &age}, 0)
+		assert.Error(t, err)
+	})
+
+	t.Run("Find unknown, empty person", func(t *testing.T) {
+		repo := newRepo(t)
+		found, err := repo.FindById(ctx, newPerson("Ivan", "", "", 0).Id, true)
+		require.NoError(t, err)
+		assert.True(t, found.IsEmpty())
+	})
+
+	t.Run("Save batch with duplicate, none saved", func(t *testing.T) {
+		repo := newRepo(t)
+		saved, fresh := newPerson("Ivan", "RU", "male", 30), newPerson("Petr", "RU", "male", 30)
+		require.NoError(t, repo.SaveBatch(ctx, []model.Person{saved}))
+
+		assert.ErrorIs(t, repo.SaveBatch(ctx, []model.Person{fresh, saved}), model.ErrConstraintViolation)
+		found, err := repo.FindById(ctx, fresh.Id, true)
+		require.NoError(t, err)
+		assert.True(t, found.IsEmpty())
+	})
+
+	t.Run("Collect filtered, ordered by id", func(t *testing.T) {
+		repo := newRepo(t)
+		var (
+			ivan  = newPerson("Ivan", "RU", "male", 20)
+			anna  = newPerson("Anna", "RU", "female", 35)
+			hans  = newPerson("Hans", "DE", "male", 50)
+			maria = newPerson("Maria", "UA", "female", 70)
+			// clara has the cleared metadata, it matches no metadata filter.
+			clara = newPerson("Clara", "DE", "female", 40)
+		)
+		createdAfter := time.Now().Add(-time.Minute)
+		require.NoError(t, repo.SaveBatch(ctx, []model.Person{ivan, anna, hans, maria, clara}))
+		_, err := repo.Update(ctx, clara.Id, model.PersonPatch{
+			Nation: str(""), Gender: str(""), Age: &noAge,
+		}, 0)
+		require.NoError(t, err)
+
+		tests := []struct {
+			name   string
+			filter model.PersonFilter
+			want   []string
+		}{
+			{"No filter", model.PersonFilter{}, sorted(ivan, anna, hans, maria, clara)},
+			{"Older than", model.PersonFilter{OlderThan: 35}, sorted(hans, maria)},
+			{"Younger than", model.PersonFilter{YoungerThan: 50}, sorted(ivan, anna)},
+			{"Age range", model.PersonFilter{OlderThan: 20, YoungerThan: 70}, sorted(anna, hans)},
+			{"Gender", model.PersonFilter{Gender: "female"}, sorted(anna, maria)},
+			{"Nation", model.PersonFilter{Nations: []string{"DE"}}, sorted(hans)},
+			{"Nations", model.PersonFilter{Nations: []string{"RU", "UA"}}, sorted(ivan, anna, maria)},
+			{"Gender and nation", model.PersonFilter{Gender: "male", Nations: []string{"RU"}}, sorted(ivan)},
+			{"Created after", model.PersonFilter{CreatedAfter: createdAfter}, sorted(ivan, anna, hans, maria, clara)},
+			{"Created before", model.PersonFilter{CreatedBefore: createdAfter}, []string{}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				persons, err := repo.Collect(ctx, tt.filter, 0, 0)
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, ids(persons))
+			})
+		}
+	})
+
+	t.Run("Collect pages, ordered by id", func(t *testing.T) {
+		repo := newRepo(t)
+		persons := make([]model.Person, 5)
+		for i := range persons {
+			persons[i] = newPerson("Ivan", "RU", "male", 20+i)
+		}
+		require.NoError(t, repo.SaveBatch(ctx, persons))
+		all := sorted(persons...)
+
+		tests := []struct {
+			name          string
+			limit, offset int
+			want          []string
+		}{
+			{"First page", 2, 0, all[:2]},
+			{"Middle page", 2, 2, all[2:4]},
+			{"Last page", 2, 4, all[4:]},
+			{"Past the end", 2, 5, []string{}},
+			{"Offset only", 0, 3, all[3:]},
+			{"No limit", 0, 0, all},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				page, err := repo.Collect(ctx, model.PersonFilter{}, tt.limit, tt.offset)
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, ids(page))
+			})
+		}
+	})
+
+	t.Run("Export filtered, ordered by id", func(t *testing.T) {
+		repo := newRepo(t)
+		ivan, anna, hans := newPerson("Ivan", "RU", "male", 20), newPerson("Anna", "RU", "female", 35),
+			newPerson("Hans", "DE", "male", 50)
+		require.NoError(t, repo.SaveBatch(ctx, []model.Person{ivan, anna, hans}))
+
+		exported := make([]model.Person, 0)
+		err := repo.Export(ctx, model.PersonFilter{Nations: []string{"RU"}}, func(p model.Person) error {
+			exported = append(exported, p)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, sorted(ivan, anna), ids(exported))
+	})
+
+	t.Run("Update with versions", func(t *testing.T) {
+		repo := newRepo(t)
+		p := newPerson("Ivan", "RU", "male", 30)
+		require.NoError(t, repo.Save(ctx, p))
+
+		name, age := "Petr", 40
+		version, err := repo.Update(ctx, p.Id, model.PersonPatch{
+			Name: &name, Age: &age, Nation: str(""),
+		}, model.InitialVersion)
+		require.NoError(t, err)
+		assert.Equal(t, model.InitialVersion+1, version)
+		found, err := repo.FindById(ctx, p.Id, false)
+		require.NoError(t, err)
+		assert.Equal(t, "Petr", found.Name)
+		assert.Equal(t, 40, found.Age)
+		assert.Empty(t, found.Nation)
+		assert.Equal(t, "male", found.Gender)
+		assert.Equal(t, model.InitialVersion+1, found.Version)
+		assert.Equal(t, []model.MetaAttr{model.AttrAge, model.AttrNation}, found.Overrides)
+
+		gender := "female"
+		version, err = repo.Update(ctx, p.Id, model.PersonPatch{
+			Gender: &gender, Enriched: []model.MetaAttr{model.AttrGender},
+		}, 0)
+		require.NoError(t, err)
+		found, err = repo.FindById(ctx, p.Id, false)
+		require.NoError(t, err)
+		assert.Equal(t, "female", found.Gender)
+		assert.Equal(t, found.Version, version)
+		assert.Equal(t, []model.MetaAttr{model.AttrAge, model.AttrNation}, found.Overrides)
+
+		_, err = repo.Update(ctx, p.Id, model.PersonPatch{Name: &name}, model.InitialVersion)
+		assert.ErrorIs(t, err, model.ErrVersionMismatch)
+		_, err = repo.Update(ctx, newPerson("Ivan", "", "", 0).Id, model.PersonPatch{Name: &name}, 0)
+		assert.ErrorIs(t, err, model.ErrNotFound)
+	})
+
+	t.Run("Concurrent updates of version, one mismatched", func(t *testing.T) {
+		repo := newRepo(t)
+		p := newPerson("Ivan", "RU", "male", 30)
+		require.NoError(t, repo.Save(ctx, p))
+
+		names := []string{"Petr", "Anna"}
+		errs := make([]error, len(names))
+		var wg sync.WaitGroup
+		for i := range names {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, errs[i] = repo.Update(ctx, p.Id, model.PersonPatch{Name: &names[i]}, model.InitialVersion)
+			}()
+		}
+		wg.Wait()
+
+		var mismatched int
+		for _, err := range errs {
+			if err != nil {
+				assert.ErrorIs(t, err, model.ErrVersionMismatch)
+				mismatched++
+			}
+		}
+		assert.Equal(t, 1, mismatched)
+		found, err := repo.FindById(ctx, p.Id, false)
+		require.NoError(t, err)
+		assert.Equal(t, model.InitialVersion+1, found.Version)
+	})
+
+	t.Run("Delete, restore and purge", func(t *testing.T) {
+		repo := newRepo(t)
+		p := newPerson("Ivan", "RU", "male", 30)
+		require.NoError(t, repo.Save(ctx, p))
+
+		assert.ErrorIs(t, repo.Delete(ctx, p.Id, 2), model.ErrVersionMismatch)
+		require.NoError(t, repo.Delete(ctx, p.Id, model.InitialVersion))
+		assert.ErrorIs(t, repo.Delete(ctx, p.Id, 0), model.ErrNotFound)
+		assert.ErrorIs(t, repo.Delete(ctx, newPerson("Petr", "", "", 0).Id, 0), model.ErrNotFound)
+
+		found, err := repo.FindById(ctx, p.Id, false)
+		require.NoError(t, err)
+		assert.True(t, found.IsEmpty())
+		found, err = repo.FindById(ctx, p.Id, true)
+		require.NoError(t, err)
+		assert.NotNil(t, found.DeletedAt)
+		assert.Equal(t, model.InitialVersion+1, found.Version)
+
+		persons, err := repo.Collect(ctx, model.PersonFilter{}, 0, 0)
+		require.NoError(t, err)
+		assert.Empty(t, persons)
+		persons, err = repo.Collect(ctx, model.PersonFilter{IncludeDeleted: true}, 0, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{p.Id}, ids(persons))
+
+		require.NoError(t, repo.Restore(ctx, p.Id))
+		assert.ErrorIs(t, repo.Restore(ctx, p.Id), model.ErrNotFound)
+		found, err = repo.FindById(ctx, p.Id, false)
+		require.NoError(t, err)
+		assert.Nil(t, found.DeletedAt)
+
+		n, err := repo.Purge(ctx, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		assert.Zero(t, n)
+
+		require.NoError(t, repo.Delete(ctx, p.Id, 0))
+		n, err = repo.Purge(ctx, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), n)
+		found, err = repo.FindById(ctx, p.Id, true)
+		require.NoError(t, err)
+		assert.True(t, found.IsEmpty())
+	})
+
+	t.Run("History of changes, oldest first", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := reqmeta.WithActor(ctx, "admin")
+		ctx = reqmeta.WithSource(ctx, "test")
+		p := newPerson("Ivan", "RU", "male", 30)
+
+		name := "Petr"
+		require.NoError(t, repo.Save(ctx, p))
+		_, err := repo.Update(ctx, p.Id, model.PersonPatch{Name: &name}, 0)
+		require.NoError(t, err)
+		require.NoError(t, repo.Delete(ctx, p.Id, 0))
+		require.NoError(t, repo.Restore(ctx, p.Id))
+
+		changes, err := repo.History(ctx, p.Id, 0, 0)
+		require.NoError(t, err)
+		require.Len(t, changes, 4)
+		ops := make([]model.ChangeOp, len(changes))
+		for i, c := range changes {
+			ops[i] = c.Op
+			assert.Equal(t, "admin", c.Actor)
+			assert.Equal(t, "test", c.Source)
+		}
+		assert.Equal(t, []model.ChangeOp{model.OpCreate, model.OpUpdate, model.OpDelete, model.OpRestore}, ops)
+		assert.Nil(t, changes[0].Before)
+		assert.Equal(t, "Ivan", changes[1].Before.Name)
+		assert.Equal(t, "Petr", changes[1].After.Name)
+		assert.Nil(t, changes[2].After)
+
+		page, err := repo.History(ctx, p.Id, 2, 1)
+		require.NoError(t, err)
+		require.Len(t, page, 2)
+		assert.Equal(t, changes[1].Id, page[0].Id)
+		assert.Equal(t, changes[2].Id, page[1].Id)
+	})
+
+	t.Run("History of enrichment, recorded as enrich", func(t *testing.T) {
+		repo := newRepo(t)
+		p := newPerson("Ivan", "RU", "male", 30)
+		require.NoError(t, repo.Save(ctx, p))
+
+		name, age := "Petr", 40
+		_, err := repo.Update(ctx, p.Id, model.PersonPatch{
+			Name:     &name,
+			Age:      &age,
+			Enriched: []model.MetaAttr{model.AttrAge},
+		}, 0)
+		require.NoError(t, err)
+
+		changes, err := repo.History(ctx, p.Id, 0, 0)
+		require.NoError(t, err)
+		require.Len(t, changes, 2)
+		assert.Equal(t, model.OpEnrich, changes[1].Op)
+		assert.Equal(t, 30, changes[1].Before.Age)
+		assert.Equal(t, 40, changes[1].After.Age)
+		assert.NotNil(t, changes[1].After.EnrichedAt)
+	})
+
+	t.Run("Stats of filtered", func(t *testing.T) {
+		repo := newRepo(t)
+		clara := newPerson("Clara", "DE", "female", 40)
+		require.NoError(t, repo.SaveBatch(ctx, []model.Person{
+			newPerson("Ivan", "RU", "male", 20),
+			newPerson("Petr", "RU", "male", 40),
+			newPerson("Anna", "RU", "female", 35),
+			newPerson("Hans", "DE", "male", 50),
+			clara,
+		}))
+		_, err := repo.Update(ctx, clara.Id, model.PersonPatch{Gender: str(""), Age: &noAge}, 0)
+		require.NoError(t, err)
+
+		stats, err := repo.Stats(ctx, model.PersonFilter{}, model.AgeBuckets{30, 45})
+		require.NoError(t, err)
+		assert.Equal(t, 5, stats.Total)
+		assert.Equal(t, []model.GenderStats{
+			{Gender: "male", Count: 3},
+			{Gender: "female", Count: 1},
+			{Gender: "", Count: 1},
+		}, stats.Genders)
+		assert.Equal(t, []model.NationStats{
+			{Nation: "RU", Count: 3, AvgAge: 95.0 / 3},
+			{Nation: "DE", Count: 2, AvgAge: 50},
+		}, stats.Nations)
+		assert.Equal(t, []model.AgeBucket{
+			{From: 0, To: 30, Count: 1},
+			{From: 30, To: 45, Count: 2},
+			{From: 45, To: 0, Count: 1},
+		}, stats.AgeHistogram)
+
+		stats, err = repo.Stats(ctx, model.PersonFilter{Gender: "male", Nations: []string{"RU"}}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 2, stats.Total)
+		assert.Equal(t, []model.NationStats{{Nation: "RU", Count: 2, AvgAge: 30}}, stats.Nations)
+		assert.Equal(t, []model.AgeBucket{{}}, stats.AgeHistogram)
+
+		stats, err = repo.Stats(ctx, model.PersonFilter{Nations: []string{"UA"}}, nil)
+		require.NoError(t, err)
+		assert.Zero(t, stats.Total)
+		assert.Equal(t, []model.GenderStats{}, stats.Genders)
+		assert.Equal(t, []model.NationStats{}, stats.Nations)
+	})
+
+	t.Run("Tenants, isolated", func(t *testing.T) {
+		repo := newRepo(t)
+		ctxA := reqmeta.WithTenant(ctx, "team-a")
+		ctxB := reqmeta.WithTenant(ctx, "team-b")
+		a := newPerson("Ivan", "RU", "male", 30)
+		b := newPerson("Petr", "RU", "male", 30)
+		require.NoError(t, repo.Save(ctxA, a))
+		require.NoError(t, repo.SaveBatch(ctxB, []model.Person{b}))
+
+		found, err := repo.FindById(ctxB, a.Id, true)
+		require.NoError(t, err)
+		assert.True(t, found.IsEmpty())
+		found, err = repo.FindById(ctx, a.Id, false)
+		require.NoError(t, err)
+		assert.True(t, found.IsEmpty())
+
+		persons, err := repo.Collect(ctxA, model.PersonFilter{}, 0, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{a.Id}, ids(persons))
+		persons, err = repo.Collect(ctxB, model.PersonFilter{}, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{b.Id}, ids(persons))
+
+		var exported []model.Person
+		require.NoError(t, repo.Export(ctxA, model.PersonFilter{}, func(p model.Person) error {
+			exported = append(exported, p)
+			return nil
+		}))
+		assert.Equal(t, []string{a.Id}, ids(exported))
+		stats, err := repo.Stats(ctxB, model.PersonFilter{}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 1, stats.Total)
+
+		name := "Anna"
+		_, err = repo.Update(ctxB, a.Id, model.PersonPatch{Name: &name}, 0)
+		assert.ErrorIs(t, err, model.ErrNotFound)
+		assert.ErrorIs(t, repo.Delete(ctxB, a.Id, 0), model.ErrNotFound)
+		found, err = repo.FindById(ctxA, a.Id, false)
+		require.NoError(t, err)
+		assert.Equal(t, "Ivan", found.Name)
+		changes, err := repo.History(ctxB, a.Id, 0, 0)
+		require.NoError(t, err)
+		assert.Empty(t, changes)
+
+		require.NoError(t, repo.Delete(ctxA, a.Id, 0))
+		assert.ErrorIs(t, repo.Restore(ctxB, a.Id), model.ErrNotFound)
+		n, err := repo.Purge(ctx, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), n)
+		changes, err = repo.History(ctxA, a.Id, 0, 0)
+		require.NoError(t, err)
+		require.Len(t, changes, 3)
+		assert.Equal(t, model.OpPurge, changes[2].Op)
+	})
+
+	t.Run("Quota exceeded, not saved", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := reqmeta.WithTenant(ctx, "limited")
+		p := newPerson("Ivan", "RU", "male", 30)
+		require.NoError(t, repo.Save(ctx, p))
+
+		err := repo.SaveBatch(ctx, []model.Person{
+			newPerson("Petr", "RU", "male", 30),
+			newPerson("Anna", "RU", "female", 30),
+		})
+		assert.ErrorIs(t, err, model.ErrQuotaExceeded)
+		require.NoError(t, repo.Save(ctx, newPerson("Petr", "RU", "male", 30)))
+		assert.ErrorIs(t, repo.Save(ctx, newPerson("Anna", "RU", "female", 30)), model.ErrQuotaExceeded)
+		require.NoError(t, repo.Save(reqmeta.WithTenant(ctx, "other"), newPerson("Anna", "RU", "female", 30)))
+
+		// The soft deleted person counts until it's purged.
+		require.NoError(t, repo.Delete(ctx, p.Id, 0))
+		assert.ErrorIs(t, repo.Save(ctx, newPerson("Anna", "RU", "female", 30)), model.ErrQuotaExceeded)
+		_, err = repo.Purge(ctx, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		require.NoError(t, repo.Save(ctx, newPerson("Anna", "RU", "female", 30)))
+	})
+}
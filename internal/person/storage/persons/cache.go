@@ -0,0 +1,519 @@
+package persons
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"math/rand"
+	"sync/atomic"
+	"time"
+
+	"github.com/alukart32/effective-mobile-test-task/internal/person/model"
+	"github.com/alukart32/effective-mobile-test-task/internal/pkg/reqmeta"
+	"github.com/alukart32/effective-mobile-test-task/internal/pkg/zerologx"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
+)
+
+// CacheConfig is the expiration of the cached persons.
+type CacheConfig struct {
+	// TTL is the expiration of the cached person.
+	TTL time.Duration
+	// Jitter is the max random addition to the TTL, so the persons cached
+	// at once don't expire at once.
+	Jitter time.Duration
+	// NegativeTTL is the expiration of the cached not found result,
+	// zero disables the negative caching.
+	NegativeTTL time.Duration
+	// ResultTTL is the expiration of the cached Collect and Stats results,
+	// zero disables the result caching.
+	ResultTTL time.Duration
+	// LocalSize limits the persons cached in process, zero disables
+	// the local cache.
+	LocalSize int
+	// LocalTTL is the expiration of the person cached in process.
+	LocalTTL time.Duration
+	// ReplicaReads routes the misses and the cached results loads to
+	// the caught up replicas. The replica is caught up with the writes of
+	// this instance only, its stale read of the person changed by the other
+	// instance is cached until the TTL. So the primary is read by default.
+	ReplicaReads bool
+}
+
+const (
+	_defaultCacheTTL    = 10 * time.Minute
+	_defaultCacheJitter = time.Minute
+	_defaultLocalTTL    = 5 * time.Second
+	// _missLoadTimeout limits the shared load of the missed person.
+	_missLoadTimeout = 10 * time.Second
+
+	// _missingField marks the cached not found person.
+	_missingField = "missing"
+)
+
+// cacheMetrics are the person cache counters published in /debug/vars.
+var cacheMetrics = struct {
+	hits         *expvar.Int
+	localHits    *expvar.Int
+	negativeHits *expvar.Int
+	misses       *expvar.Int
+	// shared are the misses served by the concurrent db read of the same person.
+	shared       *expvar.Int
+	resultHits   *expvar.Int
+	resultMisses *expvar.Int
+	// invalidations are the messages of the persons changed by the other instances.
+	invalidations *expvar.Int
+	// invalidationErrors are the committed writes not invalidated in redis.
+	invalidationErrors *expvar.Int
+	errors             *expvar.Int
+}{
+	hits:               new(expvar.Int),
+	localHits:          new(expvar.Int),
+	negativeHits:       new(expvar.Int),
+	misses:             new(expvar.Int),
+	shared:             new(expvar.Int),
+	resultHits:         new(expvar.Int),
+	resultMisses:       new(expvar.Int),
+	invalidations:      new(expvar.Int),
+	invalidationErrors: new(expvar.Int),
+	errors:             new(expvar.Int),
+}
+
+func init() {
+	m := expvar.NewMap("person_cache")
+	m.Set("hits", cacheMetrics.hits)
+	m.Set("local_hits", cacheMetrics.localHits)
+	m.Set("negative_hits", cacheMetrics.negativeHits)
+	m.Set("misses", cacheMetrics.misses)
+	m.Set("shared", cacheMetrics.shared)
+	m.Set("result_hits", cacheMetrics.resultHits)
+	m.Set("result_misses", cacheMetrics.resultMisses)
+	m.Set("invalidations", cacheMetrics.invalidations)
+	m.Set("invalidation_errors", cacheMetrics.invalidationErrors)
+	m.Set("errors", cacheMetrics.errors)
+}
+
+// cachedStorage is the cache-aside storage: the persons are read from redis,
+// the misses are read from the db and cached until the TTL.
+//
+// The changed persons are invalidated after the db commit: the person
+// generation is incremented and the cached person is deleted. The miss
+// caches the person only if the generation read before the db read is
+// still the same, so the person read before the concurrent change is
+// never cached after it. No lock is held, it works across the replicas.
+//
+// The optional local cache is in front of redis. The changed persons are
+// published to the other instances, see RunInvalidation.
+//
+// The misses are read from the primary unless CacheConfig.ReplicaReads,
+// the replica may not have replayed the write of the other instance yet
+// and its stale read would be cached.
+//
+// The keys are prefixed by the ctx tenant, the tenant never reads
+// the persons cached by the other one.
+type cachedStorage struct {
+	db *pgxDB
+	// loads reads the misses and the cached results.
+	loads personLoader
+	cache *redis.Client
+	cfg   CacheConfig
+
+	// misses merges the concurrent db reads of the same person.
+	misses singleflight.Group
+
+	local *localCache
+	// localReady is set while the invalidations are received.
+	localReady atomic.Bool
+	instanceId string
+}
+
+func CachedStorage(db *pgxpool.Pool, pgCfg PostgresConfig, cache *redis.Client, cfg CacheConfig) (*cachedStorage, error) {
+	if cache == nil {
+		return nil, fmt.Errorf("init cached storage: redis client is nil")
+	}
+	if db == nil {
+		return nil, fmt.Errorf("init cached storage: postgres pool is nil")
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = _defaultCacheTTL
+	}
+	if cfg.Jitter < 0 {
+		cfg.Jitter = _defaultCacheJitter
+	}
+	if cfg.NegativeTTL < 0 {
+		cfg.NegativeTTL = 0
+	}
+	if cfg.ResultTTL < 0 {
+		cfg.ResultTTL = 0
+	}
+
+	if cfg.LocalSize < 0 {
+		cfg.LocalSize = 0
+	}
+	if cfg.LocalTTL <= 0 {
+		cfg.LocalTTL = _defaultLocalTTL
+	}
+
+	store, err := Storage(db, pgCfg)
+	if err != nil {
+		return nil, fmt.Errorf("init cached storage: %w", err)
+	}
+	s := &cachedStorage{
+		cache:      cache,
+		db:         store,
+		loads:      store.primary(),
+		cfg:        cfg,
+		instanceId: uuid.New().String(),
+	}
+	if cfg.ReplicaReads {
+		s.loads = store
+	}
+	if cfg.LocalSize > 0 {
+		s.local = newLocalCache(cfg.LocalSize, cfg.LocalTTL)
+	}
+	return s, nil
+}
+
+// personLoader reads the persons and the results missed in the cache.
+type personLoader interface {
+	FindById(ctx context.Context, id string, includeDeleted bool) (model.Person, error)
+	Collect(ctx context.Context, filter model.PersonFilter, limit, offset int) ([]model.Person, error)
+	Stats(ctx context.Context, filter model.PersonFilter, buckets model.AgeBuckets) (model.PersonStats, error)
+}
+
+// tenantPrefix is the prefix of the tenant keys.
+func tenantPrefix(tenant string) string {
+	return "tenant:" + tenant + ":"
+}
+
+func personKey(tenant, id string) string {
+	return tenantPrefix(tenant) + "person:" + id
+}
+
+// personGenerationKey is the key of the person changes counter.
+func personGenerationKey(tenant, id string) string {
+	return personKey(tenant, id) + ":generation"
+}
+
+// _fillScript caches the person hash ARGV[3:] for ARGV[2] ms if the person
+// generation KEYS[2] is still ARGV[1], the missing generation is "".
+var _fillScript = redis.NewScript(`
+local gen = redis.call('GET', KEYS[2]) or ''
+if gen ~= ARGV[1] then
+	return 0
+end
+redis.call('DEL', KEYS[1])
+redis.call('HSET', KEYS[1], unpack(ARGV, 3))
+redis.call('PEXPIRE', KEYS[1], ARGV[2])
+return 1
+`)
+
+// ttl returns the TTL with the random jitter.
+func (s *cachedStorage) ttl() time.Duration {
+	if s.cfg.Jitter <= 0 {
+		return s.cfg.TTL
+	}
+	return s.cfg.TTL + time.Duration(rand.Int63n(int64(s.cfg.Jitter)))
+}
+
+// invalidate increments the generations of the changed persons of the ctx
+// tenant, deletes them from the caches and publishes them to the other
+// instances. It must be called after the db commit.
+func (s *cachedStorage) invalidate(ctx context.Context, ids ...string) error {
+	tenant := reqmeta.Tenant(ctx)
+	// The local cache is invalidated after redis, so the person isn't cached
+	// locally from redis before it's deleted there.
+	if s.local != nil {
+		keys := make([]string, len(ids))
+		for i, id := range ids {
+			keys[i] = localKey(tenant, id)
+		}
+		defer s.local.invalidate(keys...)
+	}
+
+	// The generation outlives the concurrent misses, they take far less than the TTL.
+	genTTL := s.cfg.TTL + s.cfg.Jitter
+	_, err := s.cache.TxPipelined(ctx, func(rdb redis.Pipeliner) error {
+		for _, id := range ids {
+			rdb.Incr(ctx, personGenerationKey(tenant, id))
+			rdb.Expire(ctx, personGenerationKey(tenant, id), genTTL)
+			rdb.Del(ctx, personKey(tenant, id))
+		}
+		return s.publishInvalidation(ctx, rdb, tenant, ids)
+	})
+	if err != nil {
+		return fmt.Errorf("redis: %w", err)
+	}
+	return nil
+}
+
+// committed invalidates the persons changed by the committed write and the
+// cached results of the ctx tenant. The write is committed, so the cache
+// errors aren't returned to fail it: they're logged and counted, the local
+// cache is flushed and the redis caches expire by the TTL.
+func (s *cachedStorage) committed(ctx context.Context, ids ...string) {
+	// The invalidation isn't canceled with the request after the commit.
+	ctx = context.WithoutCancel(ctx)
+	err := errors.Join(s.bumpGeneration(ctx), s.invalidate(ctx, ids...))
+	if err == nil {
+		return
+	}
+	cacheMetrics.invalidationErrors.Add(1)
+	zerologx.Get().Err(err).Strs("person_ids", ids).Msg("invalidate committed persons")
+	if s.local != nil {
+		s.local.flush()
+	}
+}
+
+// Save saves the person to the db only, it's cached on the first read
+// with the timestamps set by the db. The cached not found result is evicted.
+func (s *cachedStorage) Save(ctx context.Context, person model.Person) error {
+	if err := s.db.Save(ctx, person); err != nil {
+		return err
+	}
+	s.committed(ctx, person.Id)
+	return nil
+}
+
+// SaveBatch saves the persons to the db only, they are cached on the first read.
+// The cached not found results are evicted.
+func (s *cachedStorage) SaveBatch(ctx context.Context, persons []model.Person) error {
+	if err := s.db.SaveBatch(ctx, persons); err != nil {
+		return err
+	}
+	ids := make([]string, len(persons))
+	for i, p := range persons {
+		ids[i] = p.Id
+	}
+	s.committed(ctx, ids...)
+	return nil
+}
+
+// FindById returns the cached person, the miss is read from the db and cached.
+// The not found person is the empty one, it's cached for the negative TTL.
+// The cache errors are logged and the person is read from the db.
+func (s *cachedStorage) FindById(ctx context.Context, id string, includeDeleted bool) (model.Person, error) {
+	// Only the not deleted persons are cached.
+	if includeDeleted {
+		return s.db.FindById(ctx, id, true)
+	}
+
+	tenant := reqmeta.Tenant(ctx)
+	useLocal := s.local != nil && s.localReady.Load()
+	var epoch uint64
+	if useLocal {
+		if p, ok := s.local.get(localKey(tenant, id)); ok {
+			cacheMetrics.localHits.Add(1)
+			return p, nil
+		}
+		epoch = s.local.currentEpoch()
+	}
+	cacheLocal := func(p model.Person) {
+		if useLocal && !p.IsEmpty() {
+			s.local.set(epoch, localKey(tenant, id), p)
+		}
+	}
+
+	person, found, err := s.cached(ctx, tenant, id)
+	if err != nil {
+		cacheMetrics.errors.Add(1)
+		zerologx.Get().Err(err).Str("person_id", id).Msg("read cached person")
+	}
+	if found {
+		cacheLocal(person)
+		return person, nil
+	}
+
+	cacheMetrics.misses.Add(1)
+	misses := s.misses.DoChan(personKey(tenant, id), func() (any, error) {
+		// The load is shared by the callers, so it isn't canceled with the
+		// first one. Every caller waits for it up to its own ctx.
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), _missLoadTimeout)
+		defer cancel()
+
+		gen, genErr := s.cache.Get(ctx, personGenerationKey(tenant, id)).Result()
+		if genErr != nil && !errors.Is(genErr, redis.Nil) {
+			cacheMetrics.errors.Add(1)
+			zerologx.Get().Err(genErr).Str("person_id", id).Msg("read person generation")
+		}
+
+		p, err := s.loads.FindById(ctx, id, false)
+		if err != nil {
+			return model.Person{}, err
+		}
+		if genErr != nil && !errors.Is(genErr, redis.Nil) {
+			return p, nil
+		}
+		if err := s.fill(ctx, tenant, id, gen, p); err != nil {
+			cacheMetrics.errors.Add(1)
+			zerologx.Get().Err(err).Str("person_id", id).Msg("cache person")
+		}
+		return p, nil
+	})
+	var miss singleflight.Result
+	select {
+	case <-ctx.Done():
+		return model.Person{}, ctx.Err()
+	case miss = <-misses:
+	}
+	if miss.Shared {
+		cacheMetrics.shared.Add(1)
+	}
+	if miss.Err != nil {
+		return model.Person{}, miss.Err
+	}
+	person = miss.Val.(model.Person)
+	cacheLocal(person)
+	return person, nil
+}
+
+// cached returns the cached person of the tenant. It isn't found on the miss,
+// the cached not found person is found and empty.
+func (s *cachedStorage) cached(ctx context.Context, tenant, id string) (model.Person, bool, error) {
+	cmd := s.cache.HGetAll(ctx, personKey(tenant, id))
+	vals, err := cmd.Result()
+	if err != nil {
+		return model.Person{}, false, fmt.Errorf("redis: %w", err)
+	}
+	// The missing key is the empty map, not redis.Nil.
+	if len(vals) == 0 {
+		return model.Person{}, false, nil
+	}
+	if _, ok := vals[_missingField]; ok {
+		cacheMetrics.negativeHits.Add(1)
+		return model.Person{}, true, nil
+	}
+
+	var r record
+	if err = cmd.Scan(&r); err != nil {
+		return model.Person{}, false, fmt.Errorf("redis: %w", err)
+	}
+	cacheMetrics.hits.Add(1)
+	return r.ToModel(), true, nil
+}
+
+// fill caches the tenant person read from the db in the generation gen,
+// the empty person is cached as not found if the negative caching is
+// enabled. The person isn't cached if it's changed since the generation.
+func (s *cachedStorage) fill(ctx context.Context, tenant, id, gen string, p model.Person) error {
+	keys := []string{personKey(tenant, id), personGenerationKey(tenant, id)}
+	if p.IsEmpty() {
+		if s.cfg.NegativeTTL <= 0 {
+			return nil
+		}
+		err := _fillScript.Run(ctx, s.cache, keys,
+			gen, s.cfg.NegativeTTL.Milliseconds(), _missingField, 1).Err()
+		if err != nil {
+			return fmt.Errorf("redis: %w", err)
+		}
+		return nil
+	}
+
+	args := []any{
+		gen, s.ttl().Milliseconds(),
+		"id", p.Id,
+		"name", p.Name,
+		"surname", p.Surname,
+		"patronymic", p.Patronymic,
+		"nation", p.Nation,
+		"gender", p.Gender,
+		"age", p.Age,
+		"version", p.Version,
+		"created_at", p.CreatedAt,
+		"updated_at", p.UpdatedAt,
+		"overrides", newAttrList(p.Overrides).String(),
+	}
+	if p.EnrichedAt != nil {
+		args = append(args, "enriched_at", *p.EnrichedAt)
+	}
+	if err := _fillScript.Run(ctx, s.cache, keys, args...).Err(); err != nil {
+		return fmt.Errorf("redis: %w", err)
+	}
+	return nil
+}
+
+// Update evicts the updated person from the cache, the new version is returned
+// and cached on the next read.
+// The writes bump the generation of the cached Collect and Stats results.
+func (s *cachedStorage) Update(ctx context.Context, id string, patch model.PersonPatch, expectedVersion int64) (int64, error) {
+	version, err := s.db.Update(ctx, id, patch, expectedVersion)
+	if err != nil {
+		return 0, err
+	}
+	s.committed(ctx, id)
+	return version, nil
+}
+
+func (s *cachedStorage) Collect(
+	ctx context.Context,
+	filter model.PersonFilter,
+	limit, offset int,
+) ([]model.Person, error) {
+	if s.cfg.ResultTTL <= 0 {
+		return s.db.Collect(ctx, filter, limit, offset)
+	}
+	return cachedResult(ctx, s, "collect", collectQuery(filter, limit, offset),
+		func() ([]model.Person, error) {
+			return s.loads.Collect(ctx, filter, limit, offset)
+		})
+}
+
+func (s *cachedStorage) Export(
+	ctx context.Context,
+	filter model.PersonFilter,
+	fn func(model.Person) error,
+) error {
+	return s.db.Export(ctx, filter, fn)
+}
+
+func (s *cachedStorage) History(ctx context.Context, id string, limit, offset int) ([]model.PersonChange, error) {
+	return s.db.History(ctx, id, limit, offset)
+}
+
+func (s *cachedStorage) Stats(
+	ctx context.Context,
+	filter model.PersonFilter,
+	buckets model.AgeBuckets,
+) (model.PersonStats, error) {
+	if s.cfg.ResultTTL <= 0 {
+		return s.db.Stats(ctx, filter, buckets)
+	}
+	return cachedResult(ctx, s, "stats", statsQuery(filter, buckets),
+		func() (model.PersonStats, error) {
+			return s.loads.Stats(ctx, filter, buckets)
+		})
+}
+
+func (s *cachedStorage) Delete(ctx context.Context, id string, expectedVersion int64) error {
+	if err := s.db.Delete(ctx, id, expectedVersion); err != nil {
+		return err
+	}
+	s.committed(ctx, id)
+	return nil
+}
+
+// Restore restores the soft deleted person, its cached not found result is evicted.
+func (s *cachedStorage) Restore(ctx context.Context, id string) error {
+	if err := s.db.Restore(ctx, id); err != nil {
+		return err
+	}
+	s.committed(ctx, id)
+	return nil
+}
+
+// Purge hard deletes the soft deleted persons of all the tenants, they are
+// evicted from the cache on delete.
+func (s *cachedStorage) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	n, err := s.db.Purge(ctx, deletedBefore)
+	if err != nil || n == 0 {
+		return n, err
+	}
+	if err = s.bumpGlobalGeneration(context.WithoutCancel(ctx)); err != nil {
+		cacheMetrics.invalidationErrors.Add(1)
+		zerologx.Get().Err(err).Msg("invalidate purged persons")
+	}
+	return n, nil
+}
@@ -0,0 +1,112 @@
+package persons
+
+import (
+	"context"
+	"math/rand"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alukart32/effective-mobile-test-task/internal/person/model"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+// The benchmarks need the migrated postgres and redis:
+//
+//	BENCH_POSTGRES_URL=postgres://... BENCH_REDIS_URL=redis://... \
+//		go test -run - -bench CachedStorage -cpu 1,4,16 ./internal/person/storage/persons
+//
+// The locked variant serializes the calls with the process-wide mutex
+// the storage used before, for comparison.
+
+const _benchPersons = 1000
+
+func benchStorage(b *testing.B) (*cachedStorage, []string) {
+	b.Helper()
+	pgURL, redisURL := os.Getenv("BENCH_POSTGRES_URL"), os.Getenv("BENCH_REDIS_URL")
+	if len(pgURL) == 0 || len(redisURL) == 0 {
+		b.Skip("BENCH_POSTGRES_URL and BENCH_REDIS_URL aren't set")
+	}
+	ctx := context.Background()
+
+	pool, err := pgxpool.New(ctx, pgURL)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(pool.Close)
+	redisOpt, err := redis.ParseURL(redisURL)
+	if err != nil {
+		b.Fatal(err)
+	}
+	cache := redis.NewClient(redisOpt)
+	b.Cleanup(func() { cache.Close() })
+
+	s, err := CachedStorage(pool, PostgresConfig{}, cache, CacheConfig{TTL: time.Minute, NegativeTTL: time.Minute})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	persons := make([]model.Person, _benchPersons)
+	ids := make([]string, _benchPersons)
+	for i := range persons {
+		fio, _ := model.NewFIO("Ivan", "Ivanov", "")
+		persons[i] = model.NewPerson(fio, model.PersonalMetaData{Nation: "RU", Gender: "male", Age: 30})
+		ids[i] = persons[i].Id
+	}
+	if err = s.SaveBatch(ctx, persons); err != nil {
+		b.Fatal(err)
+	}
+	return s, ids
+}
+
+// benchmarkMixed runs the parallel reads with the writes every writeEvery call.
+func benchmarkMixed(b *testing.B, writeEvery int, locked bool) {
+	s, ids := benchStorage(b)
+	ctx := context.Background()
+
+	var mtx sync.RWMutex
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+		for i := 1; pb.Next(); i++ {
+			id := ids[rnd.Intn(len(ids))]
+			if writeEvery > 0 && i%writeEvery == 0 {
+				age := rnd.Intn(100)
+				if locked {
+					mtx.Lock()
+				}
+				_, err := s.Update(ctx, id, model.PersonPatch{Age: &age}, 0)
+				if locked {
+					mtx.Unlock()
+				}
+				if err != nil {
+					b.Error(err)
+				}
+				continue
+			}
+
+			if locked {
+				mtx.RLock()
+			}
+			_, err := s.FindById(ctx, id, false)
+			if locked {
+				mtx.RUnlock()
+			}
+			if err != nil {
+				b.Error(err)
+			}
+		}
+	})
+}
+
+func BenchmarkCachedStorage_Reads(b *testing.B) {
+	b.Run("locked", func(b *testing.B) { benchmarkMixed(b, 0, true) })
+	b.Run("lock-free", func(b *testing.B) { benchmarkMixed(b, 0, false) })
+}
+
+func BenchmarkCachedStorage_ReadsWrites(b *testing.B) {
+	b.Run("locked", func(b *testing.B) { benchmarkMixed(b, 10, true) })
+	b.Run("lock-free", func(b *testing.B) { benchmarkMixed(b, 10, false) })
+}
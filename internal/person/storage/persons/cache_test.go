@@ -0,0 +1,173 @@
+package persons
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/alukart32/effective-mobile-test-task/internal/person/model"
+	"github.com/alukart32/effective-mobile-test-task/internal/pkg/reqmeta"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type loaderMock struct {
+	FindByIdFn func(context.Context, string, bool) (model.Person, error)
+}
+
+func (m *loaderMock) FindById(ctx context.Context, id string, includeDeleted bool) (model.Person, error) {
+	if m != nil && m.FindByIdFn != nil {
+		return m.FindByIdFn(ctx, id, includeDeleted)
+	}
+	return model.Person{}, errors.New("can't find person")
+}
+
+func (m *loaderMock) Collect(ctx context.Context, filter model.PersonFilter, limit, offset int) ([]model.Person, error) {
+	return nil, errors.New("can't collect persons")
+}
+
+func (m *loaderMock) Stats(ctx context.Context, filter model.PersonFilter, buckets model.AgeBuckets) (model.PersonStats, error) {
+	return model.PersonStats{}, errors.New("can't collect stats")
+}
+
+// newTestCachedStorage returns the cached storage of the in-memory redis,
+// the misses are read by loads.
+func newTestCachedStorage(t *testing.T, loads personLoader) (*cachedStorage, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return &cachedStorage{
+		loads:      loads,
+		cache:      client,
+		cfg:        CacheConfig{TTL: time.Minute, NegativeTTL: time.Minute},
+		instanceId: "test",
+	}, mr
+}
+
+func TestCachedStorage_FindById(t *testing.T) {
+	ctx := context.Background()
+	person := func(name string) model.Person {
+		return model.Person{Id: "1", FIO: model.FIO{Name: name, Surname: "Ivanov"}, Version: 1}
+	}
+
+	t.Run("Changed during miss, stale read not cached", func(t *testing.T) {
+		started, release := make(chan struct{}), make(chan struct{})
+		var loads atomic.Int32
+		s, mr := newTestCachedStorage(t, &loaderMock{
+			FindByIdFn: func(ctx context.Context, id string, includeDeleted bool) (model.Person, error) {
+				if loads.Add(1) == 1 {
+					close(started)
+					<-release
+					return person("Ivan"), nil
+				}
+				return person("Petr"), nil
+			},
+		})
+
+		stale := make(chan model.Person, 1)
+		go func() {
+			p, _ := s.FindById(ctx, "1", false)
+			stale <- p
+		}()
+		<-started
+		require.NoError(t, s.invalidate(ctx, "1"))
+		close(release)
+		assert.Equal(t, "Ivan", (<-stale).Name)
+		assert.False(t, mr.Exists(personKey(reqmeta.DefaultTenant, "1")))
+
+		p, err := s.FindById(ctx, "1", false)
+		require.NoError(t, err)
+		assert.Equal(t, "Petr", p.Name)
+		assert.True(t, mr.Exists(personKey(reqmeta.DefaultTenant, "1")))
+		assert.EqualValues(t, 2, loads.Load())
+	})
+
+	t.Run("Missing, cached as not found", func(t *testing.T) {
+		var loads atomic.Int32
+		s, mr := newTestCachedStorage(t, &loaderMock{
+			FindByIdFn: func(ctx context.Context, id string, includeDeleted bool) (model.Person, error) {
+				loads.Add(1)
+				return model.Person{}, nil
+			},
+		})
+
+		for range 2 {
+			p, err := s.FindById(ctx, "1", false)
+			require.NoError(t, err)
+			assert.True(t, p.IsEmpty())
+		}
+		assert.EqualValues(t, 1, loads.Load())
+		assert.Equal(t, "1", mr.HGet(personKey(reqmeta.DefaultTenant, "1"), _missingField))
+
+		// The saved person evicts the not found result.
+		require.NoError(t, s.invalidate(ctx, "1"))
+		_, err := s.FindById(ctx, "1", false)
+		require.NoError(t, err)
+		assert.EqualValues(t, 2, loads.Load())
+	})
+
+	t.Run("Missing, not cached without negative TTL", func(t *testing.T) {
+		var loads atomic.Int32
+		s, mr := newTestCachedStorage(t, &loaderMock{
+			FindByIdFn: func(ctx context.Context, id string, includeDeleted bool) (model.Person, error) {
+				loads.Add(1)
+				return model.Person{}, nil
+			},
+		})
+		s.cfg.NegativeTTL = 0
+
+		for range 2 {
+			_, err := s.FindById(ctx, "1", false)
+			require.NoError(t, err)
+		}
+		assert.EqualValues(t, 2, loads.Load())
+		assert.False(t, mr.Exists(personKey(reqmeta.DefaultTenant, "1")))
+	})
+
+	t.Run("Concurrent misses, one db read", func(t *testing.T) {
+		const callers = 8
+		release := make(chan struct{})
+		var loads atomic.Int32
+		s, mr := newTestCachedStorage(t, &loaderMock{
+			FindByIdFn: func(ctx context.Context, id string, includeDeleted bool) (model.Person, error) {
+				loads.Add(1)
+				<-release
+				return person("Ivan"), nil
+			},
+		})
+
+		shared := cacheMetrics.shared.Value()
+		var wg sync.WaitGroup
+		names := make(chan string, callers)
+		for range callers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				p, err := s.FindById(ctx, "1", false)
+				assert.NoError(t, err)
+				names <- p.Name
+			}()
+		}
+		// Every caller has read the cache before it joins the miss: the HGETALL
+		// of each one and the GET of the generation.
+		assert.Eventually(t, func() bool {
+			return mr.CommandCount() >= callers+1
+		}, time.Second, time.Millisecond)
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+		wg.Wait()
+		close(names)
+
+		for name := range names {
+			assert.Equal(t, "Ivan", name)
+		}
+		assert.EqualValues(t, 1, loads.Load())
+		assert.EqualValues(t, callers, cacheMetrics.shared.Value()-shared)
+	})
+}
@@ -0,0 +1,39 @@
+package persons
+
+import (
+	"context"
+	"strings"
+
+	"github.com/alukart32/effective-mobile-test-task/internal/pkg/zerologx"
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+)
+
+// explainQuery logs the plan of the query at the debug level if the plans
+// are enabled. The plan is read in the savepoint, so its error doesn't
+// abort tx, it's logged only.
+func (p *pgxDB) explainQuery(ctx context.Context, tx pgx.Tx, query string, args ...any) {
+	logger := zerologx.Get()
+	if !p.explain || logger.GetLevel() > zerolog.DebugLevel {
+		return
+	}
+
+	plan, err := func() (_ []string, err error) {
+		sp, err := tx.Begin(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer sp.Rollback(ctx)
+
+		rows, err := sp.Query(ctx, "EXPLAIN "+query, args...)
+		if err != nil {
+			return nil, err
+		}
+		return pgx.CollectRows(rows, pgx.RowTo[string])
+	}()
+	if err != nil {
+		logger.Debug().Err(err).Str("sql", query).Msg("explain query")
+		return
+	}
+	logger.Debug().Str("sql", query).Str("plan", strings.Join(plan, "\n")).Msg("query plan")
+}
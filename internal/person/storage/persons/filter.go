@@ -0,0 +1,87 @@
+package persons
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alukart32/effective-mobile-test-task/internal/person/model"
+)
+
+// sqlDialect is how the SQL storages bind the filter values.
+type sqlDialect struct {
+	// param returns the placeholder of the nth arg.
+	param func(n int) string
+	// time returns the stored value of the time.
+	time func(time.Time) any
+}
+
+var _postgresDialect = sqlDialect{
+	param: func(n int) string { return "$" + strconv.Itoa(n) },
+	time:  func(t time.Time) any { return t },
+}
+
+// writeFilter writes the WHERE clause of the filter of the tenant persons.
+// Placeholders are numbered after the given args, the filter values are
+// appended to them. The soft deleted persons are filtered out unless
+// the filter includes them.
+func writeFilter(sb *strings.Builder, d sqlDialect, tenant string, filter model.PersonFilter, args []any) []any {
+	var conditions int
+	and := func() {
+		if conditions > 0 {
+			sb.WriteString(" AND ")
+		} else {
+			sb.WriteString(" WHERE ")
+		}
+		conditions++
+	}
+	bind := func(v any) string {
+		args = append(args, v)
+		return d.param(len(args))
+	}
+
+	and()
+	sb.WriteString("tenant_id = " + bind(tenant))
+
+	if !filter.IncludeDeleted {
+		and()
+		sb.WriteString("deleted_at IS NULL")
+	}
+
+	if filter.OlderThan != 0 {
+		and()
+		sb.WriteString("age > " + bind(filter.OlderThan))
+	}
+	if filter.YoungerThan != 0 {
+		and()
+		sb.WriteString("age < " + bind(filter.YoungerThan))
+	}
+	if len(filter.Gender) != 0 {
+		and()
+		sb.WriteString("gender = " + bind(filter.Gender))
+	}
+	if len(filter.Nations) > 0 {
+		and()
+		if len(filter.Nations) == 1 {
+			sb.WriteString("nation = " + bind(filter.Nations[0]))
+		} else {
+			sb.WriteString("nation IN ( ")
+			for i, n := range filter.Nations {
+				if i > 0 {
+					sb.WriteString(",")
+				}
+				sb.WriteString(bind(n))
+			}
+			sb.WriteString(" )")
+		}
+	}
+	if !filter.CreatedAfter.IsZero() {
+		and()
+		sb.WriteString("created_at >= " + bind(d.time(filter.CreatedAfter)))
+	}
+	if !filter.CreatedBefore.IsZero() {
+		and()
+		sb.WriteString("created_at < " + bind(d.time(filter.CreatedBefore)))
+	}
+	return args
+}
@@ -0,0 +1,203 @@
+package persons
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/alukart32/effective-mobile-test-task/internal/person/model"
+	"github.com/alukart32/effective-mobile-test-task/internal/pkg/reqmeta"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// recordChange writes the person change to the history and its event
+// to the outbox within the tx. The tenant, actor, source and correlation ID
+// are taken from ctx.
+func (p *pgxDB) recordChange(
+	ctx context.Context,
+	tx pgx.Tx,
+	op model.ChangeOp,
+	personId string,
+	before, after *record,
+) error {
+	const query = `INSERT INTO
+	person_history(person_id, op, before, after, actor, source, correlation_id, tenant_id)
+	VALUES($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	beforeJSON, err := marshalSnapshot(before)
+	if err != nil {
+		return err
+	}
+	afterJSON, err := marshalSnapshot(after)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, query,
+		personId,
+		string(op),
+		beforeJSON,
+		afterJSON,
+		reqmeta.Actor(ctx),
+		reqmeta.Source(ctx),
+		reqmeta.CorrelationID(ctx),
+		reqmeta.Tenant(ctx),
+	)
+	if err != nil {
+		return fmt.Errorf("record %s change: %w", op, err)
+	}
+	return p.enqueueEvent(ctx, tx, op, personId, before, after)
+}
+
+// recordCreates writes the creation of the persons to the history and
+// the outbox within the tx.
+func (p *pgxDB) recordCreates(ctx context.Context, tx pgx.Tx, persons []model.Person) error {
+	records := make([]record, len(persons))
+	for i := range persons {
+		records[i] = toRecord(persons[i])
+	}
+	return p.recordChanges(ctx, tx, model.OpCreate, records)
+}
+
+// recordChanges writes the same op changes of the persons to the history
+// and the outbox within the tx. The records are the state after the change,
+// or before it for the purged persons.
+func (p *pgxDB) recordChanges(ctx context.Context, tx pgx.Tx, op model.ChangeOp, records []record) error {
+	var (
+		actor         = reqmeta.Actor(ctx)
+		source        = reqmeta.Source(ctx)
+		correlationId = reqmeta.CorrelationID(ctx)
+		tenant        = reqmeta.Tenant(ctx)
+	)
+	snapshotColumn := "after"
+	if op == model.OpPurge {
+		snapshotColumn = "before"
+	}
+	_, err := tx.CopyFrom(ctx,
+		pgx.Identifier{"person_history"},
+		[]string{"person_id", "op", snapshotColumn, "actor", "source", "correlation_id", "tenant_id"},
+		pgx.CopyFromSlice(len(records), func(i int) ([]any, error) {
+			snapshot, err := marshalSnapshot(&records[i])
+			if err != nil {
+				return nil, err
+			}
+			return []any{
+				records[i].Id,
+				string(op),
+				snapshot,
+				actor,
+				source,
+				correlationId,
+				tenant,
+			}, nil
+		}),
+	)
+	if err != nil {
+		return fmt.Errorf("record %s changes: %w", op, err)
+	}
+	return p.enqueueEvents(ctx, tx, op, records)
+}
+
+// marshalSnapshot returns the JSON of the record or nil for the nil one.
+func marshalSnapshot(r *record) ([]byte, error) {
+	if r == nil {
+		return nil, nil
+	}
+	return json.Marshal(r)
+}
+
+// unmarshalSnapshot returns the person of the JSON record or nil for the NULL one.
+func unmarshalSnapshot(b []byte) (*model.Person, error) {
+	if len(b) == 0 {
+		return nil, nil
+	}
+	var r record
+	if err := json.Unmarshal(b, &r); err != nil {
+		return nil, err
+	}
+	person := r.ToModel()
+	return &person, nil
+}
+
+// History returns the person changes from the oldest to the newest one.
+// The changes of the other tenants persons aren't returned.
+func (p *pgxDB) History(ctx context.Context, id string, limit, offset int) (_ []model.PersonChange, err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("pgxDB.History: %w", err)
+		}
+	}()
+
+	var sb strings.Builder
+	sb.WriteString(`SELECT id, person_id, op, before, after, actor, source, correlation_id, changed_at
+	FROM person_history WHERE person_id = $1 AND tenant_id = $2 ORDER BY id`)
+	args := []any{id, reqmeta.Tenant(ctx)}
+	if limit > 0 {
+		args = append(args, limit)
+		sb.WriteString(fmt.Sprintf(" LIMIT $%d", len(args)))
+	}
+	if offset > 0 {
+		args = append(args, offset)
+		sb.WriteString(fmt.Sprintf(" OFFSET $%d", len(args)))
+	}
+
+	pool, r := p.readPool()
+	rows, err := queryHistory(ctx, pool, sb.String(), args...)
+	if r != nil && err != nil && ctx.Err() == nil && isConnError(err) {
+		p.replicas.down(r, err)
+		rows, err = queryHistory(ctx, p.pool, sb.String(), args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]model.PersonChange, len(rows))
+	for i, row := range rows {
+		changes[i] = row.change
+		if changes[i].Before, err = unmarshalSnapshot(row.before); err != nil {
+			return nil, err
+		}
+		if changes[i].After, err = unmarshalSnapshot(row.after); err != nil {
+			return nil, err
+		}
+	}
+	return changes, nil
+}
+
+// historyRow is the change read from the history, the snapshots are decoded
+// after the read, so their errors aren't taken for the replica failure.
+type historyRow struct {
+	change        model.PersonChange
+	before, after []byte
+}
+
+func queryHistory(ctx context.Context, pool *pgxpool.Pool, query string, args ...any) ([]historyRow, error) {
+	rows, err := pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (historyRow, error) {
+		var (
+			r  historyRow
+			op string
+		)
+		err := row.Scan(
+			&r.change.Id,
+			&r.change.PersonId,
+			&op,
+			&r.before,
+			&r.after,
+			&r.change.Actor,
+			&r.change.Source,
+			&r.change.CorrelationId,
+			&r.change.ChangedAt,
+		)
+		if err != nil {
+			return historyRow{}, err
+		}
+		r.change.Op = model.ChangeOp(op)
+		return r, nil
+	})
+}
@@ -0,0 +1,126 @@
+package persons
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/alukart32/effective-mobile-test-task/internal/pkg/zerologx"
+	"github.com/redis/go-redis/v9"
+)
+
+// _invalidationChannel is the redis channel of the changed persons,
+// the instances evict them from the local caches.
+const _invalidationChannel = "persons:invalidation"
+
+const (
+	// _invalidationPing is the idle time the subscription is checked after.
+	_invalidationPing       = 30 * time.Second
+	_invalidationMinBackoff = 100 * time.Millisecond
+	_invalidationMaxBackoff = 30 * time.Second
+)
+
+type invalidationMsg struct {
+	// Origin is the instance ID of the publisher, it skips its own messages.
+	Origin string   `json:"origin"`
+	Tenant string   `json:"tenant"`
+	Ids    []string `json:"ids"`
+}
+
+// RunInvalidation evicts the persons changed by the other instances from
+// the local cache until ctx is done. The local cache is used only while
+// subscribed: it's flushed and bypassed from the subscription error until
+// the resubscription, the messages may be lost meanwhile.
+func (s *cachedStorage) RunInvalidation(ctx context.Context) {
+	if s.local == nil {
+		return
+	}
+	logger := zerologx.Get().With().Str("op", "persons invalidation").Logger()
+
+	backoff := _invalidationMinBackoff
+	for {
+		subscribed, err := s.subscribe(ctx)
+		s.localReady.Store(false)
+		s.local.flush()
+		if ctx.Err() != nil {
+			return
+		}
+		logger.Err(err).Msg("invalidation subscription lost, local cache flushed")
+
+		if subscribed {
+			backoff = _invalidationMinBackoff
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, _invalidationMaxBackoff)
+	}
+}
+
+// subscribe applies the invalidation messages until the subscription error.
+// It reports whether the subscription was confirmed.
+func (s *cachedStorage) subscribe(ctx context.Context) (subscribed bool, err error) {
+	ps := s.cache.Subscribe(ctx, _invalidationChannel)
+	defer ps.Close()
+
+	for {
+		msg, err := ps.ReceiveTimeout(ctx, _invalidationPing)
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				if err = ps.Ping(ctx); err == nil {
+					continue
+				}
+			}
+			return subscribed, err
+		}
+
+		switch m := msg.(type) {
+		case *redis.Subscription:
+			// The first subscription or the resubscription after the reconnect.
+			subscribed = true
+			s.local.flush()
+			s.localReady.Store(true)
+		case *redis.Message:
+			s.applyInvalidation(m.Payload)
+		}
+	}
+}
+
+func (s *cachedStorage) applyInvalidation(payload string) {
+	var msg invalidationMsg
+	if err := json.Unmarshal([]byte(payload), &msg); err != nil {
+		zerologx.Get().Err(err).Msg("invalid persons invalidation, local cache flushed")
+		s.local.flush()
+		return
+	}
+	if msg.Origin == s.instanceId {
+		return
+	}
+	cacheMetrics.invalidations.Add(1)
+	keys := make([]string, len(msg.Ids))
+	for i, id := range msg.Ids {
+		keys[i] = localKey(msg.Tenant, id)
+	}
+	s.local.invalidate(keys...)
+}
+
+// publishInvalidation queues the message of the changed persons of the tenant to the pipeline.
+func (s *cachedStorage) publishInvalidation(
+	ctx context.Context,
+	rdb redis.Pipeliner,
+	tenant string,
+	ids []string,
+) error {
+	payload, err := json.Marshal(invalidationMsg{Origin: s.instanceId, Tenant: tenant, Ids: ids})
+	if err != nil {
+		return fmt.Errorf("persons invalidation: %w", err)
+	}
+	rdb.Publish(ctx, _invalidationChannel, payload)
+	return nil
+}
@@ -0,0 +1,94 @@
+package persons
+
+import (
+	"sync"
+	"time"
+
+	"github.com/alukart32/effective-mobile-test-task/internal/person/model"
+)
+
+// localCache is the in-process cache of the persons in front of redis.
+// It holds at most size persons for the TTL, the arbitrary person is
+// evicted when it's full. The persons are keyed by the tenant and id,
+// see localKey.
+type localCache struct {
+	size int
+	ttl  time.Duration
+
+	mtx     sync.RWMutex
+	persons map[string]localEntry
+	// epoch is incremented on every invalidation, the person read before
+	// it isn't cached after it.
+	epoch uint64
+}
+
+type localEntry struct {
+	person  model.Person
+	expires time.Time
+}
+
+func newLocalCache(size int, ttl time.Duration) *localCache {
+	return &localCache{
+		size:    size,
+		ttl:     ttl,
+		persons: make(map[string]localEntry, size),
+	}
+}
+
+// localKey returns the local cache key of the tenant person.
+func localKey(tenant, id string) string {
+	return tenant + "/" + id
+}
+
+func (c *localCache) get(key string) (model.Person, bool) {
+	c.mtx.RLock()
+	e, ok := c.persons[key]
+	c.mtx.RUnlock()
+	if !ok || time.Now().After(e.expires) {
+		return model.Person{}, false
+	}
+	return e.person, true
+}
+
+// currentEpoch returns the epoch to pass to set the person read after it.
+func (c *localCache) currentEpoch() uint64 {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+	return c.epoch
+}
+
+// set caches the person of the key read in the epoch, it's skipped if any
+// person is invalidated since then.
+func (c *localCache) set(epoch uint64, key string, p model.Person) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if epoch != c.epoch {
+		return
+	}
+	if _, ok := c.persons[key]; !ok && len(c.persons) >= c.size {
+		for k := range c.persons {
+			delete(c.persons, k)
+			break
+		}
+	}
+	c.persons[key] = localEntry{person: p, expires: time.Now().Add(c.ttl)}
+}
+
+func (c *localCache) invalidate(keys ...string) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.epoch++
+	for _, key := range keys {
+		delete(c.persons, key)
+	}
+}
+
+func (c *localCache) flush() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.epoch++
+	c.persons = make(map[string]localEntry, c.size)
+}
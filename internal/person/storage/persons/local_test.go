@@ -0,0 +1,69 @@
+package persons
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alukart32/effective-mobile-test-task/internal/person/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalCache(t *testing.T) {
+	person := func(id string) model.Person {
+		return model.Person{Id: id, FIO: model.FIO{Name: "Ivan", Surname: "Ivanov"}}
+	}
+
+	t.Run("Set and get, no error", func(t *testing.T) {
+		c := newLocalCache(2, time.Minute)
+		c.set(c.currentEpoch(), "1", person("1"))
+
+		p, ok := c.get("1")
+		assert.True(t, ok)
+		assert.Equal(t, person("1"), p)
+		_, ok = c.get("2")
+		assert.False(t, ok)
+	})
+
+	t.Run("Expired, not found", func(t *testing.T) {
+		c := newLocalCache(2, -time.Second)
+		c.set(c.currentEpoch(), "1", person("1"))
+
+		_, ok := c.get("1")
+		assert.False(t, ok)
+	})
+
+	t.Run("Full, evicted one", func(t *testing.T) {
+		c := newLocalCache(2, time.Minute)
+		for _, id := range []string{"1", "2", "3"} {
+			c.set(c.currentEpoch(), id, person(id))
+		}
+		assert.Len(t, c.persons, 2)
+		_, ok := c.get("3")
+		assert.True(t, ok)
+	})
+
+	t.Run("Read before invalidation, not cached", func(t *testing.T) {
+		c := newLocalCache(2, time.Minute)
+		epoch := c.currentEpoch()
+		c.invalidate("1")
+		c.set(epoch, "1", person("1"))
+
+		_, ok := c.get("1")
+		assert.False(t, ok)
+	})
+
+	t.Run("Invalidate and flush, evicted", func(t *testing.T) {
+		c := newLocalCache(3, time.Minute)
+		for _, id := range []string{"1", "2", "3"} {
+			c.set(c.currentEpoch(), id, person(id))
+		}
+		c.invalidate("1")
+		_, ok := c.get("1")
+		assert.False(t, ok)
+		_, ok = c.get("2")
+		assert.True(t, ok)
+
+		c.flush()
+		assert.Empty(t, c.persons)
+	})
+}
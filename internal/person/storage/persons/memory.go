@@ -0,0 +1,308 @@
+package persons
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/alukart32/effective-mobile-test-task/internal/person/model"
+	"github.com/alukart32/effective-mobile-test-task/internal/pkg/reqmeta"
+)
+
+// memoryDB is the in-memory persons storage with the semantics of pgxDB.
+// It keeps the history, but has no outbox, so no person events are published.
+type memoryDB struct {
+	mu      sync.RWMutex
+	persons map[string]tenantRecord
+	history []tenantChange
+	// usage is the number of the persons of the tenants.
+	usage  map[string]int64
+	quotas model.TenantQuotas
+}
+
+// tenantRecord is the person of the tenant.
+type tenantRecord struct {
+	nullableRecord
+	tenant string
+}
+
+// tenantChange is the person change of the tenant.
+type tenantChange struct {
+	model.PersonChange
+	tenant string
+}
+
+func MemoryStorage(quotas model.TenantQuotas) (*memoryDB, error) {
+	return &memoryDB{
+		persons: make(map[string]tenantRecord),
+		usage:   make(map[string]int64),
+		quotas:  quotas,
+	}, nil
+}
+
+// find returns the person of the tenant, the mutex must be held.
+func (m *memoryDB) find(tenant, id string) (tenantRecord, bool) {
+	r, ok := m.persons[id]
+	if !ok || r.tenant != tenant {
+		return tenantRecord{}, false
+	}
+	return r, true
+}
+
+func (m *memoryDB) Save(ctx context.Context, person model.Person) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.persons[person.Id]; ok {
+		return fmt.Errorf("memoryDB.Save: id unique %w", model.ErrConstraintViolation)
+	}
+	tenant := reqmeta.Tenant(ctx)
+	if m.quotas.Exceeded(tenant, m.usage[tenant]+1) {
+		return fmt.Errorf("memoryDB.Save: %w", model.ErrQuotaExceeded)
+	}
+	r := newNullableRecord(stampCreated(person, time.Now().UTC()))
+	if err := r.check(); err != nil {
+		return fmt.Errorf("memoryDB.Save: %w", err)
+	}
+	m.persons[r.Id] = tenantRecord{nullableRecord: r, tenant: tenant}
+	m.usage[tenant]++
+	m.recordChange(ctx, model.OpCreate, r.Id, nil, &r.record)
+	return nil
+}
+
+// SaveBatch inserts all the persons or none of them.
+func (m *memoryDB) SaveBatch(ctx context.Context, persons []model.Person) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tenant := reqmeta.Tenant(ctx)
+	if m.quotas.Exceeded(tenant, m.usage[tenant]+int64(len(persons))) {
+		return fmt.Errorf("memoryDB.SaveBatch: %w", model.ErrQuotaExceeded)
+	}
+	ids := make(map[string]struct{}, len(persons))
+	for _, p := range persons {
+		if _, ok := m.persons[p.Id]; ok {
+			return fmt.Errorf("memoryDB.SaveBatch: id unique %w", model.ErrConstraintViolation)
+		}
+		if _, ok := ids[p.Id]; ok {
+			return fmt.Errorf("memoryDB.SaveBatch: id unique %w", model.ErrConstraintViolation)
+		}
+		if err := newNullableRecord(p).check(); err != nil {
+			return fmt.Errorf("memoryDB.SaveBatch: %w", err)
+		}
+		ids[p.Id] = struct{}{}
+	}
+
+	now := time.Now().UTC()
+	for _, p := range persons {
+		r := newNullableRecord(stampCreated(p, now))
+		m.persons[r.Id] = tenantRecord{nullableRecord: r, tenant: tenant}
+		m.recordChange(ctx, model.OpCreate, r.Id, nil, &r.record)
+	}
+	m.usage[tenant] += int64(len(persons))
+	return nil
+}
+
+func (m *memoryDB) FindById(ctx context.Context, id string, includeDeleted bool) (model.Person, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	r, ok := m.find(reqmeta.Tenant(ctx), id)
+	if !ok || (!includeDeleted && r.DeletedAt != nil) {
+		return model.Person{}, nil
+	}
+	return r.ToModel(), nil
+}
+
+// Collect returns the filtered persons ordered by id.
+func (m *memoryDB) Collect(ctx context.Context, filter model.PersonFilter, limit, offset int) ([]model.Person, error) {
+	records := m.filter(reqmeta.Tenant(ctx), filter)
+	if offset > 0 {
+		records = records[min(offset, len(records)):]
+	}
+	if limit > 0 {
+		records = records[:min(limit, len(records))]
+	}
+
+	persons := make([]model.Person, len(records))
+	for i, r := range records {
+		persons[i] = r.ToModel()
+	}
+	return persons, nil
+}
+
+// Export passes the filtered persons ordered by id to fn. The persons
+// are the snapshot taken before the first call.
+func (m *memoryDB) Export(ctx context.Context, filter model.PersonFilter, fn func(model.Person) error) error {
+	for _, r := range m.filter(reqmeta.Tenant(ctx), filter) {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("memoryDB.Export: %w", err)
+		}
+		if err := fn(r.ToModel()); err != nil {
+			return fmt.Errorf("memoryDB.Export: %w", err)
+		}
+	}
+	return nil
+}
+
+// filter returns the tenant persons matching the filter ordered by id.
+func (m *memoryDB) filter(tenant string, filter model.PersonFilter) []nullableRecord {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	records := make([]nullableRecord, 0, len(m.persons))
+	for _, r := range m.persons {
+		if r.tenant == tenant && r.matches(filter) {
+			records = append(records, r.nullableRecord)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Id < records[j].Id
+	})
+	return records
+}
+
+func (m *memoryDB) Stats(ctx context.Context, filter model.PersonFilter, buckets model.AgeBuckets) (model.PersonStats, error) {
+	b := newStatsBuilder(buckets)
+	for _, r := range m.filter(reqmeta.Tenant(ctx), filter) {
+		b.add(r, 1)
+	}
+	return b.stats(), nil
+}
+
+func (m *memoryDB) History(ctx context.Context, id string, limit, offset int) ([]model.PersonChange, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	tenant := reqmeta.Tenant(ctx)
+	changes := make([]model.PersonChange, 0)
+	for _, c := range m.history {
+		if c.PersonId == id && c.tenant == tenant {
+			changes = append(changes, c.PersonChange)
+		}
+	}
+	if offset > 0 {
+		changes = changes[min(offset, len(changes)):]
+	}
+	if limit > 0 {
+		changes = changes[:min(limit, len(changes))]
+	}
+	return changes, nil
+}
+
+// Update applies the patch to the person and returns its incremented version.
+// The non zero expectedVersion must match the current person version.
+func (m *memoryDB) Update(ctx context.Context, id string, patch model.PersonPatch, expectedVersion int64) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	before, ok := m.find(reqmeta.Tenant(ctx), id)
+	if !ok || before.DeletedAt != nil {
+		return 0, fmt.Errorf("memoryDB.Update: %w", model.ErrNotFound)
+	}
+	if expectedVersion != 0 && before.Version != expectedVersion {
+		return 0, fmt.Errorf("memoryDB.Update: %w", model.ErrVersionMismatch)
+	}
+
+	after := before
+	after.nullableRecord = before.patched(patch, time.Now().UTC())
+	if err := after.check(); err != nil {
+		return 0, fmt.Errorf("memoryDB.Update: %w", err)
+	}
+
+	m.persons[id] = after
+	m.recordChange(ctx, patch.ChangeOp(), id, &before.record, &after.record)
+	return after.Version, nil
+}
+
+// Delete marks the person as deleted, it's hidden until restored or purged.
+// The non zero expectedVersion must match the current person version.
+func (m *memoryDB) Delete(ctx context.Context, id string, expectedVersion int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	before, ok := m.find(reqmeta.Tenant(ctx), id)
+	if !ok || before.DeletedAt != nil {
+		return fmt.Errorf("memoryDB.Delete: %w", model.ErrNotFound)
+	}
+	if expectedVersion != 0 && before.Version != expectedVersion {
+		return fmt.Errorf("memoryDB.Delete: %w", model.ErrVersionMismatch)
+	}
+
+	now := time.Now().UTC()
+	after := before
+	after.DeletedAt = &now
+	after.Version++
+	after.UpdatedAt = now
+
+	m.persons[id] = after
+	m.recordChange(ctx, model.OpDelete, id, &before.record, nil)
+	return nil
+}
+
+// Restore unmarks the soft deleted person.
+func (m *memoryDB) Restore(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, ok := m.find(reqmeta.Tenant(ctx), id)
+	if !ok || r.DeletedAt == nil {
+		return fmt.Errorf("memoryDB.Restore: %w", model.ErrNotFound)
+	}
+	r.DeletedAt = nil
+	r.Version++
+	r.UpdatedAt = time.Now().UTC()
+
+	m.persons[id] = r
+	m.recordChange(ctx, model.OpRestore, id, nil, &r.record)
+	return nil
+}
+
+// Purge hard deletes the persons of all the tenants soft deleted before
+// the time. It returns the number of the purged persons.
+func (m *memoryDB) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var purged int64
+	for id, r := range m.persons {
+		if r.DeletedAt == nil || !r.DeletedAt.Before(deletedBefore) {
+			continue
+		}
+		delete(m.persons, id)
+		m.usage[r.tenant]--
+		m.recordChange(reqmeta.WithTenant(ctx, r.tenant), model.OpPurge, id, &r.record, nil)
+		purged++
+	}
+	return purged, nil
+}
+
+// recordChange appends the person change to the history, the mutex must be held.
+// The tenant, actor, source and correlation ID are taken from ctx.
+func (m *memoryDB) recordChange(ctx context.Context, op model.ChangeOp, personId string, before, after *record) {
+	m.history = append(m.history, tenantChange{
+		PersonChange: model.PersonChange{
+			Id:            int64(len(m.history) + 1),
+			PersonId:      personId,
+			Op:            op,
+			Before:        snapshot(before),
+			After:         snapshot(after),
+			Actor:         reqmeta.Actor(ctx),
+			Source:        reqmeta.Source(ctx),
+			CorrelationId: reqmeta.CorrelationID(ctx),
+			ChangedAt:     time.Now().UTC(),
+		},
+		tenant: reqmeta.Tenant(ctx),
+	})
+}
+
+// snapshot returns the person of the record or nil for the nil one.
+func snapshot(r *record) *model.Person {
+	if r == nil {
+		return nil
+	}
+	person := r.ToModel()
+	return &person
+}
@@ -2,10 +2,19 @@ package persons
 
 import (
 	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/alukart32/effective-mobile-test-task/internal/person/model"
+	"github.com/jackc/pgx/v5"
 )
 
+// _personColumns are the persons table columns in the record scan order.
+const _personColumns = "id, name, surname, patronymic, nation, gender, age, version, " +
+	"created_at, updated_at, enriched_at, overrides, deleted_at"
+
 type record struct {
 	Id         string `redis:"id" json:"id"`
 	Name       string `redis:"name" json:"name"`
@@ -14,12 +23,99 @@ type record struct {
 	Nation     string `redis:"nation" json:"nation"`
 	Gender     string `redis:"gender" json:"gender"`
 	Age        int    `redis:"age" json:"age"`
+	Version    int64  `redis:"version" json:"version"`
+
+	CreatedAt  time.Time  `redis:"created_at" json:"created_at"`
+	UpdatedAt  time.Time  `redis:"updated_at" json:"updated_at"`
+	EnrichedAt *time.Time `redis:"enriched_at" json:"enriched_at,omitempty"`
+	Overrides  attrList   `redis:"overrides" json:"overrides,omitempty"`
+
+	// DeletedAt isn't cached, the soft deleted persons are evicted from the cache.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// attrList is the list of the metadata attributes. It's cached as
+// the comma separated string.
+type attrList []string
+
+func newAttrList(attrs []model.MetaAttr) attrList {
+	if len(attrs) == 0 {
+		return nil
+	}
+	l := make(attrList, len(attrs))
+	for i, a := range attrs {
+		l[i] = string(a)
+	}
+	return l
+}
+
+func (l attrList) ToModel() []model.MetaAttr {
+	if len(l) == 0 {
+		return nil
+	}
+	attrs := make([]model.MetaAttr, len(l))
+	for i, a := range l {
+		attrs[i] = model.MetaAttr(a)
+	}
+	return attrs
+}
+
+func (l attrList) String() string {
+	return strings.Join(l, ",")
+}
+
+// ScanRedis implements the redis hash field scanner.
+func (l *attrList) ScanRedis(s string) error {
+	if len(s) == 0 {
+		*l = nil
+		return nil
+	}
+	*l = strings.Split(s, ",")
+	return nil
 }
 
 func (r record) MarshalBinary() ([]byte, error) {
 	return json.Marshal(r)
 }
 
+// scanRecord scans the row of _personColumns followed by the extra columns.
+// The cleared metadata is NULL, it's scanned as the zero value.
+func scanRecord(row pgx.Row, extra ...any) (record, error) {
+	var (
+		r              record
+		nation, gender *string
+		age            *int
+		overrides      []string
+	)
+	dest := []any{
+		&r.Id,
+		&r.Name,
+		&r.Surname,
+		&r.Patronymic,
+		&nation,
+		&gender,
+		&age,
+		&r.Version,
+		&r.CreatedAt,
+		&r.UpdatedAt,
+		&r.EnrichedAt,
+		&overrides,
+		&r.DeletedAt,
+	}
+	err := row.Scan(append(dest, extra...)...)
+	r.Overrides = overrides
+	if nation != nil {
+		r.Nation = *nation
+	}
+	if gender != nil {
+		r.Gender = *gender
+	}
+	if age != nil {
+		r.Age = *age
+	}
+	return r, err
+}
+
 func toRecord(p model.Person) record {
 	return record{
 		Id:         p.Id,
@@ -29,6 +125,12 @@ func toRecord(p model.Person) record {
 		Nation:     p.Nation,
 		Gender:     p.Gender,
 		Age:        p.Age,
+		Version:    p.Version,
+		CreatedAt:  p.CreatedAt,
+		UpdatedAt:  p.UpdatedAt,
+		EnrichedAt: p.EnrichedAt,
+		Overrides:  newAttrList(p.Overrides),
+		DeletedAt:  p.DeletedAt,
 	}
 }
 
@@ -45,5 +147,117 @@ func (r record) ToModel() model.Person {
 			Gender: r.Gender,
 			Age:    r.Age,
 		},
+		Version:    r.Version,
+		CreatedAt:  r.CreatedAt,
+		UpdatedAt:  r.UpdatedAt,
+		EnrichedAt: r.EnrichedAt,
+		Overrides:  r.Overrides.ToModel(),
+		DeletedAt:  r.DeletedAt,
+	}
+}
+
+// stampCreated sets the creation time of the new person.
+// The enrichment time is set only if the person has the metadata.
+func stampCreated(p model.Person, now time.Time) model.Person {
+	p.CreatedAt = now
+	p.UpdatedAt = now
+	if !p.PersonalMetaData.IsEmpty() {
+		p.EnrichedAt = &now
+	}
+	return p
+}
+
+// nullableRecord is the record with the cleared metadata marked, it's
+// NULL in the db. The storages without the NULL columns keep it to match
+// the same filters and stats.
+type nullableRecord struct {
+	record
+	nullNation, nullGender, nullAge bool
+}
+
+// newNullableRecord returns the record of the new person like pgxDB.Save
+// stores it: the version is the initial one, there are no overrides and
+// the empty metadata isn't NULL.
+func newNullableRecord(p model.Person) nullableRecord {
+	r := nullableRecord{record: toRecord(p)}
+	r.Version = model.InitialVersion
+	r.Overrides = nil
+	r.DeletedAt = nil
+	return r
+}
+
+// check validates the person like the persons table constraints.
+func (r nullableRecord) check() error {
+	if len(r.Name) == 0 {
+		return fmt.Errorf("name check %w", model.ErrConstraintViolation)
+	}
+	if len(r.Surname) == 0 {
+		return fmt.Errorf("surname check %w", model.ErrConstraintViolation)
+	}
+	if !r.nullAge && r.Age <= 0 {
+		return fmt.Errorf("age check %w", model.ErrConstraintViolation)
+	}
+	return nil
+}
+
+// matches reports whether the person matches the filter the way
+// writeFilter does, the NULL metadata matches no condition.
+func (r nullableRecord) matches(filter model.PersonFilter) bool {
+	if !filter.IncludeDeleted && r.DeletedAt != nil {
+		return false
+	}
+	if filter.OlderThan != 0 && (r.nullAge || r.Age <= filter.OlderThan) {
+		return false
+	}
+	if filter.YoungerThan != 0 && (r.nullAge || r.Age >= filter.YoungerThan) {
+		return false
+	}
+	if len(filter.Gender) != 0 && (r.nullGender || r.Gender != filter.Gender) {
+		return false
+	}
+	if len(filter.Nations) > 0 && (r.nullNation || !slices.Contains(filter.Nations, r.Nation)) {
+		return false
+	}
+	if !filter.CreatedAfter.IsZero() && r.CreatedAt.Before(filter.CreatedAfter) {
+		return false
+	}
+	if !filter.CreatedBefore.IsZero() && !r.CreatedAt.Before(filter.CreatedBefore) {
+		return false
+	}
+	return true
+}
+
+// patched returns the person with the patch applied like pgxDB.Update
+// does: the cleared metadata is NULL, the overrides are merged and
+// the version is incremented.
+func (r nullableRecord) patched(patch model.PersonPatch, now time.Time) nullableRecord {
+	if patch.Name != nil {
+		r.Name = *patch.Name
+	}
+	if patch.Surname != nil {
+		r.Surname = *patch.Surname
+	}
+	if patch.Patronymic != nil {
+		r.Patronymic = *patch.Patronymic
+	}
+	if patch.Nation != nil {
+		r.Nation, r.nullNation = *patch.Nation, len(*patch.Nation) == 0
+	}
+	if patch.Gender != nil {
+		r.Gender, r.nullGender = *patch.Gender, len(*patch.Gender) == 0
+	}
+	if patch.Age != nil {
+		r.Age, r.nullAge = *patch.Age, *patch.Age == 0
+	}
+	if overrides := patch.Overrides(); len(overrides) > 0 {
+		merged := append(slices.Clone(r.Overrides), newAttrList(overrides)...)
+		slices.Sort(merged)
+		r.Overrides = slices.Compact(merged)
+	}
+	if len(patch.Enriched) > 0 {
+		r.EnrichedAt = &now
 	}
+	r.Version++
+	r.UpdatedAt = now
+	return r
 }
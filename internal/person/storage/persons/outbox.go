@@ -0,0 +1,176 @@
+package persons
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/alukart32/effective-mobile-test-task/internal/person/model"
+	"github.com/alukart32/effective-mobile-test-task/internal/pkg/reqmeta"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// personEvent is the outbox payload of the person change.
+type personEvent struct {
+	Type     model.EventType `json:"type"`
+	PersonId string          `json:"person_id"`
+	Tenant   string          `json:"tenant"`
+	// Person is the state after the change or before it for the deleted person.
+	Person        *record   `json:"person,omitempty"`
+	Actor         string    `json:"actor,omitempty"`
+	Source        string    `json:"source,omitempty"`
+	CorrelationId string    `json:"correlation_id,omitempty"`
+	OccurredAt    time.Time `json:"occurred_at"`
+}
+
+func newPersonEvent(ctx context.Context, op model.ChangeOp, personId string, before, after *record) personEvent {
+	person := after
+	if person == nil {
+		person = before
+	}
+	return personEvent{
+		Type:          model.EventTypeOf(op),
+		PersonId:      personId,
+		Tenant:        reqmeta.Tenant(ctx),
+		Person:        person,
+		Actor:         reqmeta.Actor(ctx),
+		Source:        reqmeta.Source(ctx),
+		CorrelationId: reqmeta.CorrelationID(ctx),
+		OccurredAt:    time.Now().UTC(),
+	}
+}
+
+// enqueueEvent writes the person change event to the outbox within the tx.
+func (p *pgxDB) enqueueEvent(
+	ctx context.Context,
+	tx pgx.Tx,
+	op model.ChangeOp,
+	personId string,
+	before, after *record,
+) error {
+	const query = `INSERT INTO outbox(aggregate_id, event_type, payload, tenant_id) VALUES($1, $2, $3, $4)`
+
+	event := newPersonEvent(ctx, op, personId, before, after)
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if _, err = tx.Exec(ctx, query, personId, string(event.Type), payload, event.Tenant); err != nil {
+		return fmt.Errorf("enqueue %s event: %w", event.Type, err)
+	}
+	return nil
+}
+
+// enqueueEvents writes the same op events of the persons to the outbox within the tx.
+// The persons are the state after the change or before it for the deleted ones.
+func (p *pgxDB) enqueueEvents(ctx context.Context, tx pgx.Tx, op model.ChangeOp, persons []record) error {
+	_, err := tx.CopyFrom(ctx,
+		pgx.Identifier{"outbox"},
+		[]string{"aggregate_id", "event_type", "payload", "tenant_id"},
+		pgx.CopyFromSlice(len(persons), func(i int) ([]any, error) {
+			event := newPersonEvent(ctx, op, persons[i].Id, nil, &persons[i])
+			payload, err := json.Marshal(event)
+			if err != nil {
+				return nil, err
+			}
+			return []any{persons[i].Id, string(event.Type), payload, event.Tenant}, nil
+		}),
+	)
+	if err != nil {
+		return fmt.Errorf("enqueue %s events: %w", model.EventTypeOf(op), err)
+	}
+	return nil
+}
+
+// _outboxLockKey is the advisory lock key held by the publishing relay.
+const _outboxLockKey int64 = 0x6f7574626f78
+
+type outboxStore struct {
+	db *pgxDB
+}
+
+func Outbox(db *pgxpool.Pool) (*outboxStore, error) {
+	if db == nil {
+		return nil, fmt.Errorf("init outbox: postgres pool is nil")
+	}
+	return &outboxStore{db: &pgxDB{pool: db}}, nil
+}
+
+// Publish passes the oldest unpublished messages to fn and marks them published
+// if fn succeeds. Only one caller publishes at a time, the others get zero
+// messages, so the messages are published in the outbox order.
+func (s *outboxStore) Publish(
+	ctx context.Context,
+	limit int,
+	fn func([]model.OutboxMessage) error,
+) (_ int, err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("outbox.Publish: %w", err)
+		}
+	}()
+
+	tx, err := s.db.pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.ReadCommitted,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		err = s.db.finishTx(ctx, tx, err)
+	}()
+
+	var locked bool
+	if err = tx.QueryRow(ctx, `SELECT pg_try_advisory_xact_lock($1)`, _outboxLockKey).Scan(&locked); err != nil {
+		return 0, err
+	}
+	if !locked {
+		return 0, nil
+	}
+
+	const query = `SELECT id, aggregate_id, event_type, payload, tenant_id, created_at
+	FROM outbox WHERE published_at IS NULL ORDER BY id LIMIT $1`
+	rows, err := tx.Query(ctx, query, limit)
+	if err != nil {
+		return 0, err
+	}
+	msgs, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.OutboxMessage, error) {
+		var (
+			msg       model.OutboxMessage
+			eventType string
+		)
+		err := row.Scan(&msg.Id, &msg.Key, &eventType, &msg.Payload, &msg.Tenant, &msg.CreatedAt)
+		msg.Type = model.EventType(eventType)
+		return msg, err
+	})
+	if err != nil || len(msgs) == 0 {
+		return 0, err
+	}
+
+	if err = fn(msgs); err != nil {
+		return 0, err
+	}
+
+	ids := make([]int64, len(msgs))
+	for i, m := range msgs {
+		ids[i] = m.Id
+	}
+	_, err = tx.Exec(ctx, `UPDATE outbox SET published_at = now() WHERE id = ANY($1)`, ids)
+	if err != nil {
+		return 0, err
+	}
+	return len(msgs), nil
+}
+
+// Cleanup deletes the messages published before the time.
+func (s *outboxStore) Cleanup(ctx context.Context, publishedBefore time.Time) (int64, error) {
+	tag, err := s.db.pool.Exec(ctx, `DELETE FROM outbox WHERE published_at < $1`, publishedBefore)
+	if err != nil {
+		return 0, fmt.Errorf("outbox.Cleanup: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -5,18 +5,78 @@ import (
 	"errors"
 	"fmt"
 	"strings"
-	"sync"
+	"time"
 
 	"github.com/alukart32/effective-mobile-test-task/internal/person/model"
+	"github.com/alukart32/effective-mobile-test-task/internal/pkg/reqmeta"
 	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
-	"github.com/redis/go-redis/v9"
 )
 
+// PostgresConfig is the optional configuration of the postgres storage.
+type PostgresConfig struct {
+	Replicas Replicas
+	// Explain logs the plans of the filtered queries at the debug level,
+	// every such query is planned twice.
+	Explain bool
+	Quotas  model.TenantQuotas
+}
+
+// pgxDB writes to the primary pool. The reads of the persons, history,
+// stats and exports are routed to the replicas if there are any.
+//
+// Every query is scoped to the ctx tenant, except the purge of all
+// the tenants persons.
 type pgxDB struct {
-	pool *pgxpool.Pool
+	pool     *pgxpool.Pool
+	replicas *replicaSet
+	explain  bool
+	quotas   model.TenantQuotas
+}
+
+func Storage(db *pgxpool.Pool, cfg PostgresConfig) (*pgxDB, error) {
+	if db == nil {
+		return nil, fmt.Errorf("init persons storage: postgres pool is nil")
+	}
+	set, err := newReplicaSet(db, cfg.Replicas)
+	if err != nil {
+		return nil, fmt.Errorf("init persons storage: %w", err)
+	}
+	return &pgxDB{pool: db, replicas: set, explain: cfg.Explain, quotas: cfg.Quotas}, nil
+}
+
+// readPool returns the pool of the next caught up replica, the primary one
+// if there is none. The replica is nil for the primary.
+func (p *pgxDB) readPool() (*pgxpool.Pool, *replica) {
+	if r := p.replicas.pick(); r != nil {
+		return r.pool, r
+	}
+	return p.pool, nil
+}
+
+// primary returns the storage reading the primary only.
+func (p *pgxDB) primary() *pgxDB {
+	return &pgxDB{pool: p.pool, explain: p.explain, quotas: p.quotas}
+}
+
+// beginRead begins the read only tx on the replica, on the primary if no
+// replica is caught up or the replica fails to begin it.
+func (p *pgxDB) beginRead(ctx context.Context) (pgx.Tx, error) {
+	opts := pgx.TxOptions{
+		IsoLevel:       pgx.RepeatableRead,
+		AccessMode:     pgx.ReadOnly,
+		DeferrableMode: pgx.NotDeferrable,
+	}
+	if r := p.replicas.pick(); r != nil {
+		tx, err := r.pool.BeginTx(ctx, opts)
+		if err == nil || ctx.Err() != nil {
+			return tx, err
+		}
+		p.replicas.down(r, err)
+	}
+	return p.pool.BeginTx(ctx, opts)
 }
 
 func (p *pgxDB) Save(ctx context.Context, person model.Person) (err error) {
@@ -25,8 +85,9 @@ func (p *pgxDB) Save(ctx context.Context, person model.Person) (err error) {
 			err = fmt.Errorf("pgxDB.Save: %w", err)
 		}
 	}()
+	// The tenant usage is updated by the concurrent writes, it's read committed.
 	tx, err := p.pool.BeginTx(ctx, pgx.TxOptions{
-		IsoLevel:       pgx.RepeatableRead,
+		IsoLevel:       pgx.ReadCommitted,
 		AccessMode:     pgx.ReadWrite,
 		DeferrableMode: pgx.NotDeferrable,
 	})
@@ -34,13 +95,19 @@ func (p *pgxDB) Save(ctx context.Context, person model.Person) (err error) {
 		return err
 	}
 	defer func() {
-		err = p.finishTx(ctx, tx, err)
+		err = p.finishWriteTx(ctx, tx, err)
 	}()
 
+	tenant := reqmeta.Tenant(ctx)
+	if err = p.reserveQuota(ctx, tx, tenant, 1); err != nil {
+		return err
+	}
+
 	const query = `INSERT INTO
-	persons(id, name, surname, patronymic, nation, gender, age)
-	VALUES($1, $2, $3, $4, $5, $6, $7)`
+	persons(id, name, surname, patronymic, nation, gender, age, created_at, updated_at, enriched_at, tenant_id)
+	VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
 
+	person = stampCreated(person, time.Now().UTC())
 	_, err = tx.Exec(ctx, query,
 		person.Id,
 		person.Name,
@@ -49,38 +116,116 @@ func (p *pgxDB) Save(ctx context.Context, person model.Person) (err error) {
 		person.Nation,
 		person.Gender,
 		person.Age,
+		person.CreatedAt,
+		person.UpdatedAt,
+		person.EnrichedAt,
+		tenant,
 	)
+	if err == nil {
+		after := toRecord(person)
+		err = p.recordChange(ctx, tx, model.OpCreate, person.Id, nil, &after)
+	}
 
 	var pgErr *pgconn.PgError
 	if err != nil && errors.As(err, &pgErr) {
 		if pgerrcode.IsIntegrityConstraintViolation(pgErr.SQLState()) &&
 			pgErr.SQLState() == pgerrcode.UniqueViolation {
-			err = fmt.Errorf("pgxDB.Save: %s unique violation", pgErr.ColumnName)
+			err = fmt.Errorf("pgxDB.Save: %s unique %w", pgErr.ColumnName, model.ErrConstraintViolation)
 		}
 		if pgerrcode.IsIntegrityConstraintViolation(pgErr.SQLState()) &&
 			pgErr.SQLState() == pgerrcode.CheckViolation {
-			err = fmt.Errorf("pgxDB.Save: %s check violation", pgErr.ColumnName)
+			err = fmt.Errorf("pgxDB.Save: %s check %w", pgErr.ColumnName, model.ErrConstraintViolation)
 		}
 	}
 	return err
 }
 
-func (p *pgxDB) FindById(ctx context.Context, id string) (_ model.Person, err error) {
-	const query = `SELECT * FROM persons WHERE id = $1`
-	var record record
-	row := p.pool.QueryRow(ctx, query, id)
-	err = row.Scan(
-		&record.Id,
-		&record.Name,
-		&record.Surname,
-		&record.Patronymic,
-		&record.Nation,
-		&record.Gender,
-		&record.Age,
+// SaveBatch inserts the persons at once with the COPY protocol.
+func (p *pgxDB) SaveBatch(ctx context.Context, persons []model.Person) (err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("pgxDB.SaveBatch: %w", err)
+		}
+	}()
+	tx, err := p.pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.ReadCommitted,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = p.finishWriteTx(ctx, tx, err)
+	}()
+
+	tenant := reqmeta.Tenant(ctx)
+	if err = p.reserveQuota(ctx, tx, tenant, len(persons)); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	stamped := make([]model.Person, len(persons))
+	for i := range persons {
+		stamped[i] = stampCreated(persons[i], now)
+	}
+	persons = stamped
+
+	_, err = tx.CopyFrom(ctx,
+		pgx.Identifier{"persons"},
+		[]string{
+			"id", "name", "surname", "patronymic", "nation", "gender", "age",
+			"created_at", "updated_at", "enriched_at", "tenant_id",
+		},
+		pgx.CopyFromSlice(len(persons), func(i int) ([]any, error) {
+			return []any{
+				persons[i].Id,
+				persons[i].Name,
+				persons[i].Surname,
+				persons[i].Patronymic,
+				persons[i].Nation,
+				persons[i].Gender,
+				persons[i].Age,
+				persons[i].CreatedAt,
+				persons[i].UpdatedAt,
+				persons[i].EnrichedAt,
+				tenant,
+			}, nil
+		}),
 	)
+	if err == nil {
+		err = p.recordCreates(ctx, tx, persons)
+	}
+
+	var pgErr *pgconn.PgError
+	if err != nil && errors.As(err, &pgErr) {
+		if pgerrcode.IsIntegrityConstraintViolation(pgErr.SQLState()) &&
+			pgErr.SQLState() == pgerrcode.UniqueViolation {
+			err = fmt.Errorf("%s unique %w", pgErr.ColumnName, model.ErrConstraintViolation)
+		}
+		if pgerrcode.IsIntegrityConstraintViolation(pgErr.SQLState()) &&
+			pgErr.SQLState() == pgerrcode.CheckViolation {
+			err = fmt.Errorf("%s check %w", pgErr.ColumnName, model.ErrConstraintViolation)
+		}
+	}
+	return err
+}
+
+func (p *pgxDB) FindById(ctx context.Context, id string, includeDeleted bool) (_ model.Person, err error) {
+	query := `SELECT ` + _personColumns + ` FROM persons WHERE id = $1 AND tenant_id = $2`
+	if !includeDeleted {
+		query += ` AND deleted_at IS NULL`
+	}
+	tenant := reqmeta.Tenant(ctx)
+	pool, r := p.readPool()
+	record, err := scanRecord(pool.QueryRow(ctx, query, id, tenant))
+	if r != nil && err != nil && !errors.Is(err, pgx.ErrNoRows) && ctx.Err() == nil && isConnError(err) {
+		p.replicas.down(r, err)
+		record, err = scanRecord(p.pool.QueryRow(ctx, query, id, tenant))
+	}
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			err = nil
+			return model.Person{}, nil
 		}
 		return model.Person{}, fmt.Errorf("pgxDB.FindById: %w", err)
 	}
@@ -94,19 +239,16 @@ func (p *pgxDB) Collect(ctx context.Context, filter model.PersonFilter, limit, o
 		}
 	}()
 
-	tx, err := p.pool.BeginTx(ctx, pgx.TxOptions{
-		IsoLevel:       pgx.RepeatableRead,
-		AccessMode:     pgx.ReadWrite,
-		DeferrableMode: pgx.NotDeferrable,
-	})
+	tx, err := p.beginRead(ctx)
 	if err != nil {
 		return nil, err
 	}
-	defer func() error {
-		return p.finishTx(ctx, tx, err)
+	defer func() {
+		err = p.finishTx(ctx, tx, err)
 	}()
 
-	query, args := p.getCollectQuery(limit, offset, filter)
+	query, args := p.getCollectQuery(reqmeta.Tenant(ctx), limit, offset, filter)
+	p.explainQuery(ctx, tx, query, args...)
 	var rows pgx.Rows
 	if len(args) > 0 {
 		rows, err = tx.Query(ctx, query, args...)
@@ -120,21 +262,13 @@ func (p *pgxDB) Collect(ctx context.Context, filter model.PersonFilter, limit, o
 
 	records := make([]record, 0)
 	for rows.Next() {
-		var r record
-		err = rows.Scan(
-			&r.Id,
-			&r.Name,
-			&r.Surname,
-			&r.Patronymic,
-			&r.Nation,
-			&r.Gender,
-			&r.Age)
+		r, err := scanRecord(rows)
 		if err != nil {
 			return nil, err
 		}
 		records = append(records, r)
 	}
-	if rows.Err() != nil {
+	if err = rows.Err(); err != nil {
 		return nil, err
 	}
 
@@ -145,301 +279,461 @@ func (p *pgxDB) Collect(ctx context.Context, filter model.PersonFilter, limit, o
 	return persons, err
 }
 
-func (p *pgxDB) getCollectQuery(limit, offset int, filter model.PersonFilter) (string, []any) {
-	var (
-		sb         strings.Builder
-		fieldOrder int
-		args       []any
-	)
-	addFilters := func() {
-		if filter.IsEmpty() {
-			return
+// _exportFetchSize is the number of rows fetched from the export cursor at once.
+const _exportFetchSize = 1000
+
+// Export streams the filtered persons to fn from the server-side cursor,
+// so only one fetch of rows is held in memory.
+func (p *pgxDB) Export(ctx context.Context, filter model.PersonFilter, fn func(model.Person) error) (err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("pgxDB.Export: %w", err)
 		}
-		sb.WriteString(" WHERE ")
+	}()
 
-		if filter.OlderThan != 0 {
-			fieldOrder++
-			sb.WriteString(fmt.Sprintf("age > $%d", fieldOrder))
-			args = append(args, filter.OlderThan)
+	tx, err := p.beginRead(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = p.finishTx(ctx, tx, err)
+	}()
+
+	var sb strings.Builder
+	sb.WriteString("SELECT " + _personColumns + " FROM persons")
+	args := writeFilter(&sb, _postgresDialect, reqmeta.Tenant(ctx), filter, nil)
+	sb.WriteString(" ORDER BY id")
+	p.explainQuery(ctx, tx, sb.String(), args...)
+	if _, err = tx.Exec(ctx, "DECLARE persons_export NO SCROLL CURSOR FOR "+sb.String(), args...); err != nil {
+		return err
+	}
+
+	fetch := fmt.Sprintf("FETCH FORWARD %d FROM persons_export", _exportFetchSize)
+	for {
+		rows, err := tx.Query(ctx, fetch)
+		if err != nil {
+			return err
 		}
-		if filter.YoungerThan != 0 {
-			if fieldOrder > 0 {
-				sb.WriteString(" AND ")
+
+		var fetched int
+		for rows.Next() {
+			fetched++
+			r, err := scanRecord(rows)
+			if err == nil {
+				err = fn(r.ToModel())
 			}
-			fieldOrder++
-			sb.WriteString(fmt.Sprintf("age < $%d", fieldOrder))
-			args = append(args, filter.YoungerThan)
-		}
-		if len(filter.Gender) != 0 {
-			if fieldOrder > 0 {
-				sb.WriteString(" AND ")
+			if err != nil {
+				rows.Close()
+				return err
 			}
-			fieldOrder++
-			sb.WriteString(fmt.Sprintf("gender = $%d", fieldOrder))
-			args = append(args, filter.Gender)
 		}
-		if len(filter.Nations) > 0 {
-			if fieldOrder > 0 {
-				sb.WriteString(" AND ")
-			}
-			if len(filter.Nations) == 1 {
-				fieldOrder++
-				sb.WriteString(fmt.Sprintf("nation = $%d", fieldOrder))
-				args = append(args, filter.Nations[0])
-			} else {
-				sb.WriteString("nation IN ( ")
-				for i, n := range filter.Nations {
-					fieldOrder++
-					if i < len(filter.Nations)-1 {
-						sb.WriteString(fmt.Sprintf("$%d,", fieldOrder))
-					} else {
-						sb.WriteString(fmt.Sprintf("$%d", fieldOrder))
-					}
-					args = append(args, n)
-				}
-				sb.WriteString(" )")
-			}
+		rows.Close()
+		if err = rows.Err(); err != nil {
+			return err
+		}
+		if fetched < _exportFetchSize {
+			return nil
 		}
 	}
-	sb.WriteString("SELECT p.id, p.name, p.surname, p.patronymic, p.nation, p.gender, p.age")
+}
+
+// getCollectQuery returns the query of the filtered tenant persons ordered
+// by id. The page ids are selected first, so only the page rows are read.
+func (p *pgxDB) getCollectQuery(tenant string, limit, offset int, filter model.PersonFilter) (string, []any) {
+	var (
+		sb   strings.Builder
+		args []any
+	)
+	sb.WriteString("SELECT p.id, p.name, p.surname, p.patronymic, p.nation, p.gender, p.age, p.version,")
+	sb.WriteString(" p.created_at, p.updated_at, p.enriched_at, p.overrides, p.deleted_at")
 	sb.WriteString(" FROM persons AS p")
 
-	if limit > 0 {
-		if offset > 0 {
-			sb.WriteString(" JOIN (SELECT id FROM persons ")
-			if !filter.IsEmpty() {
-				addFilters()
-			}
-			sb.WriteString(" ORDER BY id")
-			sb.WriteString(fmt.Sprintf(" LIMIT $%d OFFSET $%d", fieldOrder+1, fieldOrder+2))
-			args = append(args, limit, offset)
-			fieldOrder += 2
-
-			sb.WriteString(" ) as tmp ON tmp.id = p.id")
-		} else {
-			if !filter.IsEmpty() {
-				addFilters()
-			}
-			fieldOrder++
-			sb.WriteString(fmt.Sprintf(" LIMIT $%d", fieldOrder))
+	if limit > 0 || offset > 0 {
+		sb.WriteString(" JOIN (SELECT id FROM persons ")
+		args = writeFilter(&sb, _postgresDialect, tenant, filter, args)
+		sb.WriteString(" ORDER BY id")
+		if limit > 0 {
 			args = append(args, limit)
+			sb.WriteString(fmt.Sprintf(" LIMIT $%d", len(args)))
 		}
-	} else if !filter.IsEmpty() {
-		addFilters()
+		if offset > 0 {
+			args = append(args, offset)
+			sb.WriteString(fmt.Sprintf(" OFFSET $%d", len(args)))
+		}
+		sb.WriteString(" ) as tmp ON tmp.id = p.id")
+	} else {
+		args = writeFilter(&sb, _postgresDialect, tenant, filter, args)
 	}
+	sb.WriteString(" ORDER BY p.id")
 	return sb.String(), args
 }
 
-func (p *pgxDB) Update(ctx context.Context, id string, meta model.PersonalMetaData) (err error) {
+func (p *pgxDB) Stats(ctx context.Context, filter model.PersonFilter, buckets model.AgeBuckets) (_ model.PersonStats, err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("pgxDB.Stats: %w", err)
+		}
+	}()
+
+	tx, err := p.beginRead(ctx)
+	if err != nil {
+		return model.PersonStats{}, err
+	}
+	defer func() {
+		err = p.finishTx(ctx, tx, err)
+	}()
+
+	var sb strings.Builder
+	args := writeFilter(&sb, _postgresDialect, reqmeta.Tenant(ctx), filter, nil)
+	where := sb.String()
+
+	var stats model.PersonStats
+	query := "SELECT gender, COUNT(*) FROM persons" + where + " GROUP BY gender ORDER BY 2 DESC, 1"
+	p.explainQuery(ctx, tx, query, args...)
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return model.PersonStats{}, err
+	}
+	stats.Genders, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.GenderStats, error) {
+		var (
+			s      model.GenderStats
+			gender *string
+		)
+		err := row.Scan(&gender, &s.Count)
+		if gender != nil {
+			s.Gender = *gender
+		}
+		return s, err
+	})
+	if err != nil {
+		return model.PersonStats{}, err
+	}
+	for _, g := range stats.Genders {
+		stats.Total += g.Count
+	}
+
+	query = "SELECT nation, COUNT(*), COALESCE(AVG(age), 0)::float8 FROM persons" + where +
+		" GROUP BY nation ORDER BY 2 DESC, 1"
+	p.explainQuery(ctx, tx, query, args...)
+	rows, err = tx.Query(ctx, query, args...)
+	if err != nil {
+		return model.PersonStats{}, err
+	}
+	stats.Nations, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.NationStats, error) {
+		var (
+			s      model.NationStats
+			nation *string
+		)
+		err := row.Scan(&nation, &s.Count, &s.AvgAge)
+		if nation != nil {
+			s.Nation = *nation
+		}
+		return s, err
+	})
+	if err != nil {
+		return model.PersonStats{}, err
+	}
+
+	stats.AgeHistogram = model.NewAgeHistogram(buckets)
+	if len(buckets) == 0 {
+		return stats, nil
+	}
+	where += " AND age IS NOT NULL"
+	histogramArgs := append(args, []int(buckets))
+	query = fmt.Sprintf("SELECT width_bucket(age, $%d::int4[]), COUNT(*) FROM persons", len(histogramArgs)) +
+		where + " GROUP BY 1"
+	p.explainQuery(ctx, tx, query, histogramArgs...)
+	rows, err = tx.Query(ctx, query, histogramArgs...)
+	if err != nil {
+		return model.PersonStats{}, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var bucket, count int
+		if err = rows.Scan(&bucket, &count); err != nil {
+			return model.PersonStats{}, err
+		}
+		if bucket >= 0 && bucket < len(stats.AgeHistogram) {
+			stats.AgeHistogram[bucket].Count = count
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return model.PersonStats{}, err
+	}
+	return stats, nil
+}
+
+// Update applies the patch to the person and returns its incremented version.
+// The non zero expectedVersion must match the current person version.
+func (p *pgxDB) Update(ctx context.Context, id string, patch model.PersonPatch, expectedVersion int64) (version int64, err error) {
 	defer func() {
 		if err != nil {
 			err = fmt.Errorf("pgxDB.Update: %w", err)
 		}
 	}()
 
+	// The row lock and the version check order the concurrent updates, it's
+	// read committed to not fail them with the serialization error.
 	tx, err := p.pool.BeginTx(ctx, pgx.TxOptions{
-		IsoLevel:       pgx.RepeatableRead,
+		IsoLevel:       pgx.ReadCommitted,
 		AccessMode:     pgx.ReadWrite,
 		DeferrableMode: pgx.NotDeferrable,
 	})
 	if err != nil {
-		return err
+		return 0, err
 	}
 	defer func() {
-		err = p.finishTx(ctx, tx, err)
+		err = p.finishWriteTx(ctx, tx, err)
 	}()
-	query, args := p.getUpdateQuery(id, meta)
-	_, err = tx.Exec(ctx, query, args...)
-	return err
+
+	tenant := reqmeta.Tenant(ctx)
+	const selectQuery = `SELECT ` + _personColumns + ` FROM persons
+	WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL FOR UPDATE`
+	before, err := scanRecord(tx.QueryRow(ctx, selectQuery, id, tenant))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = model.ErrNotFound
+		}
+		return 0, err
+	}
+	if expectedVersion != 0 && before.Version != expectedVersion {
+		return 0, model.ErrVersionMismatch
+	}
+
+	query, args := p.getUpdateQuery(tenant, id, patch)
+	after, err := scanRecord(tx.QueryRow(ctx, query, args...))
+	if err != nil {
+		return 0, err
+	}
+	return after.Version, p.recordChange(ctx, tx, patch.ChangeOp(), id, &before, &after)
 }
 
-func (p *pgxDB) getUpdateQuery(id string, meta model.PersonalMetaData) (string, []any) {
+// getUpdateQuery returns the query setting the patched fields. The cleared
+// metadata is set to NULL, the cleared patronymic to the empty string.
+func (p *pgxDB) getUpdateQuery(tenant, id string, patch model.PersonPatch) (string, []any) {
 	var (
-		sb         strings.Builder
-		fieldOrder int
-		args       []any
+		sb   strings.Builder
+		args []any
 	)
-	sb.WriteString("UPDATE persons SET ")
-	if len(meta.Nation) != 0 {
-		fieldOrder++
-
-		sb.WriteString(fmt.Sprintf("nation = $%v", fieldOrder))
-		args = append(args, meta.Nation)
+	set := func(column string, v any) {
+		args = append(args, v)
+		sb.WriteString(fmt.Sprintf("%s = $%d, ", column, len(args)))
 	}
-	if len(meta.Gender) != 0 {
-		if fieldOrder > 0 {
-			sb.WriteString(", ")
+	setNullable := func(column string, v any, cleared bool) {
+		if cleared {
+			sb.WriteString(column + " = NULL, ")
+			return
 		}
-		fieldOrder++
-		sb.WriteString(fmt.Sprintf("gender = $%v", fieldOrder))
-		args = append(args, meta.Gender)
+		set(column, v)
 	}
-	if meta.Age > 0 {
-		if fieldOrder > 0 {
-			sb.WriteString(", ")
-		}
-		fieldOrder++
 
-		sb.WriteString(fmt.Sprintf("age = $%v", fieldOrder))
-		args = append(args, meta.Age)
+	sb.WriteString("UPDATE persons SET ")
+	if patch.Name != nil {
+		set("name", *patch.Name)
+	}
+	if patch.Surname != nil {
+		set("surname", *patch.Surname)
+	}
+	if patch.Patronymic != nil {
+		set("patronymic", *patch.Patronymic)
+	}
+	if patch.Nation != nil {
+		setNullable("nation", *patch.Nation, len(*patch.Nation) == 0)
+	}
+	if patch.Gender != nil {
+		setNullable("gender", *patch.Gender, len(*patch.Gender) == 0)
+	}
+	if patch.Age != nil {
+		setNullable("age", *patch.Age, *patch.Age == 0)
+	}
+	if overrides := patch.Overrides(); len(overrides) > 0 {
+		args = append(args, []string(newAttrList(overrides)))
+		sb.WriteString(fmt.Sprintf(
+			"overrides = ARRAY(SELECT DISTINCT unnest(overrides || $%d::text[]) ORDER BY 1), ", len(args)))
+	}
+	if len(patch.Enriched) > 0 {
+		sb.WriteString("enriched_at = now(), ")
 	}
 
-	sb.WriteString(fmt.Sprintf(" WHERE id = $%v", fieldOrder+1))
-	args = append(args, id)
+	sb.WriteString("version = version + 1, updated_at = now()")
+	args = append(args, id, tenant)
+	sb.WriteString(fmt.Sprintf(" WHERE id = $%d AND tenant_id = $%d", len(args)-1, len(args)))
+	sb.WriteString(" RETURNING " + _personColumns)
 
 	return sb.String(), args
 }
 
-func (p *pgxDB) Delete(ctx context.Context, id string) (err error) {
-	const query = `DELETE FROM persons WHERE id = $1`
-	_, err = p.pool.Exec(ctx, query, id)
+// Delete marks the person as deleted, it's hidden until restored or purged.
+// The non zero expectedVersion must match the current person version.
+func (p *pgxDB) Delete(ctx context.Context, id string, expectedVersion int64) (err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("pgxDB.Delete: %w", err)
+		}
+	}()
+
+	// The row lock and the version check order the concurrent deletes, it's
+	// read committed to not fail them with the serialization error.
+	tx, err := p.pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.ReadCommitted,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
 	if err != nil {
-		err = fmt.Errorf("pgxDB.Delete: %w", err)
+		return err
 	}
-	return err
-}
+	defer func() {
+		err = p.finishWriteTx(ctx, tx, err)
+	}()
 
-// finishTx rollbacks transaction if error is provided.
-// If err is nil transaction is committed.
-func (p *pgxDB) finishTx(ctx context.Context, tx pgx.Tx, err error) error {
+	tenant := reqmeta.Tenant(ctx)
+	const selectQuery = `SELECT ` + _personColumns + ` FROM persons
+	WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL FOR UPDATE`
+	before, err := scanRecord(tx.QueryRow(ctx, selectQuery, id, tenant))
 	if err != nil {
-		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
-			return errors.Join(err, rollbackErr)
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = model.ErrNotFound
 		}
 		return err
 	}
-	if commitErr := tx.Commit(ctx); commitErr != nil {
-		return fmt.Errorf("commit tx: %w", err)
+	if expectedVersion != 0 && before.Version != expectedVersion {
+		return model.ErrVersionMismatch
 	}
-	return nil
-}
-
-type cachedStorage struct {
-	db    *pgxDB
-	cache *redis.Client
 
-	mtx *sync.RWMutex
-}
-
-func CachedStorage(db *pgxpool.Pool, cache *redis.Client) (*cachedStorage, error) {
-	if cache == nil {
-		return nil, fmt.Errorf("init cached storage: redis client is nil")
-	}
-	if db == nil {
-		return nil, fmt.Errorf("init cached storage: postgres pool is nil")
+	const query = `UPDATE persons SET deleted_at = now(), version = version + 1, updated_at = now()
+	WHERE id = $1 AND tenant_id = $2`
+	if _, err = tx.Exec(ctx, query, id, tenant); err != nil {
+		return err
 	}
-
-	return &cachedStorage{
-		cache: cache,
-		db:    &pgxDB{db},
-		mtx:   &sync.RWMutex{},
-	}, nil
+	return p.recordChange(ctx, tx, model.OpDelete, id, &before, nil)
 }
 
-func (s *cachedStorage) Save(ctx context.Context, person model.Person) error {
-	s.mtx.Lock()
-	defer s.mtx.Unlock()
-
-	if _, err := s.cache.Pipelined(ctx, func(rdb redis.Pipeliner) error {
-		key := "person:" + person.Id
-		rdb.HSet(ctx, key, "id", person.Id)
-		rdb.HSet(ctx, key, "name", person.Name)
-		rdb.HSet(ctx, key, "surname", person.Surname)
-		rdb.HSet(ctx, key, "patronymic", person.Patronymic)
-		rdb.HSet(ctx, key, "nation", person.Nation)
-		rdb.HSet(ctx, key, "gender", person.Gender)
-		rdb.HSet(ctx, key, "age", person.Age)
-		return nil
-	}); err != nil {
+// Restore unmarks the soft deleted person.
+func (p *pgxDB) Restore(ctx context.Context, id string) (err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("pgxDB.Restore: %w", err)
+		}
+	}()
+
+	// The concurrent restore waits for the row lock and finds it restored.
+	tx, err := p.pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.ReadCommitted,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
 		return err
 	}
+	defer func() {
+		err = p.finishWriteTx(ctx, tx, err)
+	}()
 
-	var err error
-	if err = s.db.Save(ctx, person); err != nil {
-		if cacheErr := s.cache.Del(ctx, "person:"+person.Id).Err(); cacheErr != nil {
-			return fmt.Errorf("redis: %w", cacheErr)
+	const query = `UPDATE persons SET deleted_at = NULL, version = version + 1, updated_at = now()
+	WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NOT NULL RETURNING ` + _personColumns
+	after, err := scanRecord(tx.QueryRow(ctx, query, id, reqmeta.Tenant(ctx)))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = model.ErrNotFound
 		}
+		return err
 	}
-	return err
+	return p.recordChange(ctx, tx, model.OpRestore, id, nil, &after)
 }
 
-func (s *cachedStorage) FindById(ctx context.Context, id string) (model.Person, error) {
-	s.mtx.RLock()
-	defer s.mtx.RUnlock()
+// Purge hard deletes the persons of all the tenants soft deleted before
+// the time. It returns the number of the purged persons.
+func (p *pgxDB) Purge(ctx context.Context, deletedBefore time.Time) (_ int64, err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("pgxDB.Purge: %w", err)
+		}
+	}()
 
-	var record record
-	err := s.cache.HGetAll(ctx, "person:"+id).Scan(&record)
+	tx, err := p.pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.ReadCommitted,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
 	if err != nil {
-		if !errors.Is(err, redis.Nil) {
-			return model.Person{}, err
-		}
+		return 0, err
+	}
+	defer func() {
+		err = p.finishWriteTx(ctx, tx, err)
+	}()
 
-		p, err := s.db.FindById(ctx, id)
+	const query = `DELETE FROM persons WHERE deleted_at < $1 RETURNING ` + _personColumns + `, tenant_id`
+	rows, err := tx.Query(ctx, query, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	purged := make(map[string][]record)
+	for rows.Next() {
+		var tenant string
+		r, err := scanRecord(rows, &tenant)
 		if err != nil {
-			return model.Person{}, err
-		}
-		if _, err = s.cache.Pipelined(ctx, func(rdb redis.Pipeliner) error {
-			key := "person:" + p.Id
-			rdb.HSet(ctx, key, "id", p.Id)
-			rdb.HSet(ctx, key, "name", p.Name)
-			rdb.HSet(ctx, key, "surname", p.Surname)
-			rdb.HSet(ctx, key, "patronymic", p.Patronymic)
-			rdb.HSet(ctx, key, "nation", p.Nation)
-			rdb.HSet(ctx, key, "gender", p.Gender)
-			rdb.HSet(ctx, key, "age", p.Age)
-			return nil
-		}); err != nil {
-			return model.Person{}, err
+			rows.Close()
+			return 0, err
 		}
-		return p, nil
+		purged[tenant] = append(purged[tenant], r)
+	}
+	if err = rows.Err(); err != nil {
+		return 0, err
 	}
 
-	return record.ToModel(), nil
+	// The changes are recorded in the tenants of the purged persons.
+	var total int64
+	for tenant, records := range purged {
+		if err = p.recordChanges(reqmeta.WithTenant(ctx, tenant), tx, model.OpPurge, records); err != nil {
+			return 0, err
+		}
+		if err = p.reserveQuota(ctx, tx, tenant, -len(records)); err != nil {
+			return 0, err
+		}
+		total += int64(len(records))
+	}
+	return total, nil
 }
 
-func (s *cachedStorage) Update(ctx context.Context, id string, meta model.PersonalMetaData) error {
-	s.mtx.Lock()
-	defer s.mtx.Unlock()
-
-	if err := s.db.Update(ctx, id, meta); err != nil {
-		return err
+// reserveQuota adds n persons to the tenant usage within the tx, the negative
+// n releases them. It fails with model.ErrQuotaExceeded if the added persons
+// exceed the tenant quota. The usage row is locked until the tx is finished,
+// so the concurrent writes of the tenant can't exceed the quota together.
+func (p *pgxDB) reserveQuota(ctx context.Context, tx pgx.Tx, tenant string, n int) error {
+	const query = `INSERT INTO tenant_usage(tenant_id, persons) VALUES($1, $2)
+	ON CONFLICT (tenant_id) DO UPDATE SET persons = tenant_usage.persons + EXCLUDED.persons
+	RETURNING persons`
+
+	var persons int64
+	if err := tx.QueryRow(ctx, query, tenant, n).Scan(&persons); err != nil {
+		return fmt.Errorf("tenant usage: %w", err)
 	}
-	if err := s.cache.HSet(ctx, "person:"+id, s.getUpdateArgs(meta)).Err(); err != nil {
-		return fmt.Errorf("redis: %w", err)
+	if n > 0 && p.quotas.Exceeded(tenant, persons) {
+		return model.ErrQuotaExceeded
 	}
 	return nil
 }
 
-func (s *cachedStorage) getUpdateArgs(meta model.PersonalMetaData) map[string]any {
-	values := make(map[string]any)
-	if len(meta.Nation) != 0 {
-		values["nation"] = meta.Nation
-	}
-	if len(meta.Gender) != 0 {
-		values["gender"] = meta.Gender
-	}
-	if meta.Age > 0 {
-		values["age"] = meta.Age
+// finishWriteTx finishes the write transaction like finishTx, the replicas
+// aren't read until they replay the committed one.
+func (p *pgxDB) finishWriteTx(ctx context.Context, tx pgx.Tx, err error) error {
+	if err = p.finishTx(ctx, tx, err); err == nil {
+		p.replicas.wrote(ctx)
 	}
-	return values
-}
-
-func (s *cachedStorage) Collect(
-	ctx context.Context,
-	filter model.PersonFilter,
-	limit, offset int,
-) ([]model.Person, error) {
-	s.mtx.RLock()
-	defer s.mtx.RUnlock()
-	return s.db.Collect(ctx, filter, limit, offset)
+	return err
 }
 
-func (s *cachedStorage) Delete(ctx context.Context, id string) error {
-	s.mtx.Lock()
-	defer s.mtx.Unlock()
-
-	if err := s.cache.Del(ctx, "person:"+id).Err(); err != nil {
-		return fmt.Errorf("redis: %w", err)
+// finishTx rollbacks transaction if error is provided.
+// If err is nil transaction is committed.
+func (p *pgxDB) finishTx(ctx context.Context, tx pgx.Tx, err error) error {
+	if err != nil {
+		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+			return errors.Join(err, rollbackErr)
+		}
+		return err
 	}
-	return s.db.Delete(ctx, id)
+	if commitErr := tx.Commit(ctx); commitErr != nil {
+		return fmt.Errorf("commit tx: %w", commitErr)
+	}
+	return nil
 }
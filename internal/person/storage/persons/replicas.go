@@ -0,0 +1,221 @@
+package persons
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/alukart32/effective-mobile-test-task/internal/pkg/zerologx"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Replicas are the postgres read replicas of the primary.
+type Replicas struct {
+	Pools []*pgxpool.Pool
+	// CheckInterval is how often the replicas health and replay position
+	// are checked, see RunReplicaChecks.
+	CheckInterval time.Duration
+}
+
+const (
+	_defaultReplicaCheckInterval = time.Second
+	// _unknownLSN is the last write position if it failed to be read,
+	// the replicas aren't read until the next check reads the primary one.
+	_unknownLSN = math.MaxUint64
+)
+
+// replicaSet routes the reads to the replicas round robin. The replica is
+// read only if it's healthy and it has replayed the last write of the
+// instance, so the instance reads its own writes. The primary is read
+// otherwise.
+type replicaSet struct {
+	primary  *pgxpool.Pool
+	nodes    []*replica
+	interval time.Duration
+
+	next atomic.Uint64
+	// lastWrite is the primary WAL position after the last write.
+	lastWrite atomic.Uint64
+}
+
+type replica struct {
+	pool *pgxpool.Pool
+	// healthy is false until the first successful check.
+	healthy atomic.Bool
+	// replayed is the WAL position replayed at the last check.
+	replayed atomic.Uint64
+}
+
+// newReplicaSet returns the replica set, nil if there are no replicas.
+func newReplicaSet(primary *pgxpool.Pool, cfg Replicas) (*replicaSet, error) {
+	if len(cfg.Pools) == 0 {
+		return nil, nil
+	}
+	if cfg.CheckInterval <= 0 {
+		cfg.CheckInterval = _defaultReplicaCheckInterval
+	}
+	s := &replicaSet{
+		primary:  primary,
+		nodes:    make([]*replica, len(cfg.Pools)),
+		interval: cfg.CheckInterval,
+	}
+	for i, pool := range cfg.Pools {
+		if pool == nil {
+			return nil, fmt.Errorf("replica %d postgres pool is nil", i)
+		}
+		s.nodes[i] = &replica{pool: pool}
+	}
+	return s, nil
+}
+
+// pick returns the next healthy caught up replica, nil if there is none.
+func (s *replicaSet) pick() *replica {
+	if s == nil {
+		return nil
+	}
+	lastWrite := s.lastWrite.Load()
+	n := uint64(len(s.nodes))
+	start := s.next.Add(1)
+	for i := uint64(0); i < n; i++ {
+		r := s.nodes[(start+i)%n]
+		if r.healthy.Load() && r.replayed.Load() >= lastWrite {
+			return r
+		}
+	}
+	return nil
+}
+
+// wrote moves the last write position to the current primary one.
+// It must be called after the write commit.
+func (s *replicaSet) wrote(ctx context.Context) {
+	if s == nil {
+		return
+	}
+	lsn, err := currentLSN(ctx, s.primary)
+	if err != nil {
+		zerologx.Get().Err(err).Msg("read primary WAL position, replicas aren't read until the next check")
+		lsn = _unknownLSN
+	}
+	for {
+		last := s.lastWrite.Load()
+		if last >= lsn || s.lastWrite.CompareAndSwap(last, lsn) {
+			return
+		}
+	}
+}
+
+// down excludes the failed replica until the next successful check.
+func (s *replicaSet) down(r *replica, err error) {
+	if r.healthy.Swap(false) {
+		zerologx.Get().Warn().Err(err).Str("replica", replicaHost(r.pool)).Msg("postgres replica is down")
+	}
+}
+
+// check reads the replicas replay positions, the failed ones are down.
+func (s *replicaSet) check(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, s.interval)
+	defer cancel()
+
+	if s.lastWrite.Load() == _unknownLSN {
+		if lsn, err := currentLSN(ctx, s.primary); err == nil {
+			s.lastWrite.CompareAndSwap(_unknownLSN, lsn)
+		}
+	}
+	for _, r := range s.nodes {
+		lsn, err := replayLSN(ctx, r.pool)
+		if err != nil {
+			s.down(r, err)
+			continue
+		}
+		r.replayed.Store(lsn)
+		if !r.healthy.Swap(true) {
+			zerologx.Get().Info().Str("replica", replicaHost(r.pool)).Msg("postgres replica is up")
+		}
+	}
+}
+
+// RunReplicaChecks checks the replicas health and replay position until
+// ctx is done. The replicas aren't read before the first check.
+func (p *pgxDB) RunReplicaChecks(ctx context.Context) {
+	s := p.replicas
+	if s == nil {
+		return
+	}
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		s.check(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunReplicaChecks checks the replicas of the db, see pgxDB.RunReplicaChecks.
+func (s *cachedStorage) RunReplicaChecks(ctx context.Context) {
+	s.db.RunReplicaChecks(ctx)
+}
+
+// isConnError reports whether the read failed before it reached the
+// server or was aborted by the server, it's retried on the primary.
+func isConnError(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		// Class 57 is the operator intervention, e.g. the replica shutdown
+		// or the query canceled by the recovery conflict.
+		return strings.HasPrefix(pgErr.Code, "57")
+	}
+	return true
+}
+
+func currentLSN(ctx context.Context, pool *pgxpool.Pool) (uint64, error) {
+	var s string
+	if err := pool.QueryRow(ctx, `SELECT pg_current_wal_lsn()::text`).Scan(&s); err != nil {
+		return 0, err
+	}
+	return parseLSN(s)
+}
+
+// replayLSN returns the replayed WAL position, the current one if the
+// replica is promoted.
+func replayLSN(ctx context.Context, pool *pgxpool.Pool) (uint64, error) {
+	const query = `SELECT COALESCE(
+		CASE WHEN pg_is_in_recovery() THEN pg_last_wal_replay_lsn() ELSE pg_current_wal_lsn() END,
+		'0/0')::text`
+
+	var s string
+	if err := pool.QueryRow(ctx, query).Scan(&s); err != nil {
+		return 0, err
+	}
+	return parseLSN(s)
+}
+
+// parseLSN parses the pg_lsn text, e.g. "16/B374D848".
+func parseLSN(s string) (uint64, error) {
+	hi, lo, ok := strings.Cut(s, "/")
+	if !ok {
+		return 0, fmt.Errorf("invalid LSN: %q", s)
+	}
+	h, err := strconv.ParseUint(hi, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid LSN: %q", s)
+	}
+	l, err := strconv.ParseUint(lo, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid LSN: %q", s)
+	}
+	return h<<32 | l, nil
+}
+
+func replicaHost(pool *pgxpool.Pool) string {
+	cfg := pool.Config().ConnConfig
+	return fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)
+}
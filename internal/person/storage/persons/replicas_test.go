@@ -0,0 +1,166 @@
+package persons
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLSN(t *testing.T) {
+	tests := []struct {
+		name    string
+		lsn     string
+		want    uint64
+		wantErr bool
+	}{
+		{name: "Zero, no error", lsn: "0/0", want: 0},
+		{name: "Valid, no error", lsn: "16/B374D848", want: 0x16<<32 | 0xB374D848},
+		{name: "Lower case, no error", lsn: "1/a", want: 1<<32 | 0xa},
+		{name: "No slash, error", lsn: "16B374D848", wantErr: true},
+		{name: "Not hex, error", lsn: "16/X", wantErr: true},
+		{name: "Overflow, error", lsn: "100000000/0", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseLSN(tt.lsn)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReplicaSet_Pick(t *testing.T) {
+	newSet := func(replayed ...uint64) *replicaSet {
+		s := &replicaSet{nodes: make([]*replica, len(replayed))}
+		for i, lsn := range replayed {
+			s.nodes[i] = &replica{}
+			s.nodes[i].healthy.Store(true)
+			s.nodes[i].replayed.Store(lsn)
+		}
+		return s
+	}
+
+	t.Run("No replicas, primary", func(t *testing.T) {
+		var s *replicaSet
+		assert.Nil(t, s.pick())
+	})
+
+	t.Run("Not checked, primary", func(t *testing.T) {
+		s := &replicaSet{nodes: []*replica{{}}}
+		assert.Nil(t, s.pick())
+	})
+
+	t.Run("Healthy, round robin", func(t *testing.T) {
+		s := newSet(10, 10)
+		first, second := s.pick(), s.pick()
+		assert.NotNil(t, first)
+		assert.NotNil(t, second)
+		assert.NotSame(t, first, second)
+		assert.Same(t, first, s.pick())
+	})
+
+	t.Run("Lagging after write, caught up one", func(t *testing.T) {
+		s := newSet(5, 10)
+		s.lastWrite.Store(10)
+		for i := 0; i < 3; i++ {
+			assert.Same(t, s.nodes[1], s.pick())
+		}
+	})
+
+	t.Run("All lagging after write, primary", func(t *testing.T) {
+		s := newSet(5, 9)
+		s.lastWrite.Store(10)
+		assert.Nil(t, s.pick())
+	})
+
+	t.Run("Unknown write position, primary", func(t *testing.T) {
+		s := newSet(5, 10)
+		s.lastWrite.Store(_unknownLSN)
+		assert.Nil(t, s.pick())
+	})
+
+	t.Run("Down, skipped", func(t *testing.T) {
+		s := newSet(10, 10)
+		// The pool isn't connected, it's logged only.
+		pool, err := pgxpool.New(context.Background(), "postgres://replica:5432/persons")
+		require.NoError(t, err)
+		defer pool.Close()
+		s.nodes[0].pool = pool
+
+		s.down(s.nodes[0], errors.New("connection refused"))
+		for i := 0; i < 3; i++ {
+			assert.Same(t, s.nodes[1], s.pick())
+		}
+	})
+}
+
+// newTestPool returns the pool not connected until it's queried.
+func newTestPool(t *testing.T, host string) *pgxpool.Pool {
+	t.Helper()
+	pool, err := pgxpool.New(context.Background(), "postgres://"+host+":5432/persons")
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+func TestPgxDB_ReadPool(t *testing.T) {
+	primary, replicaPool := newTestPool(t, "primary"), newTestPool(t, "replica")
+	db, err := Storage(primary, PostgresConfig{Replicas: Replicas{Pools: []*pgxpool.Pool{replicaPool}}})
+	require.NoError(t, err)
+	r := db.replicas.nodes[0]
+	r.healthy.Store(true)
+	r.replayed.Store(5)
+	db.replicas.lastWrite.Store(10)
+
+	pool, picked := db.readPool()
+	assert.Same(t, primary, pool)
+	assert.Nil(t, picked)
+
+	// The next check sees the replica replayed the write.
+	r.replayed.Store(10)
+	pool, picked = db.readPool()
+	assert.Same(t, replicaPool, pool)
+	assert.Same(t, r, picked)
+}
+
+func TestCachedStorage_ReplicaReads(t *testing.T) {
+	tests := []struct {
+		name         string
+		replicaReads bool
+		replica      bool
+	}{
+		{name: "Default, primary", replicaReads: false, replica: false},
+		{name: "Replica reads, caught up replica", replicaReads: true, replica: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary, replicaPool := newTestPool(t, "primary"), newTestPool(t, "replica")
+			cache := redis.NewClient(&redis.Options{Addr: "redis:6379"})
+			t.Cleanup(func() { cache.Close() })
+			s, err := CachedStorage(primary,
+				PostgresConfig{Replicas: Replicas{Pools: []*pgxpool.Pool{replicaPool}}},
+				cache, CacheConfig{ReplicaReads: tt.replicaReads})
+			require.NoError(t, err)
+			r := s.db.replicas.nodes[0]
+			r.healthy.Store(true)
+			r.replayed.Store(10)
+			s.db.replicas.lastWrite.Store(10)
+
+			pool, _ := s.loads.(*pgxDB).readPool()
+			if tt.replica {
+				assert.Same(t, replicaPool, pool)
+			} else {
+				assert.Same(t, primary, pool)
+			}
+		})
+	}
+}
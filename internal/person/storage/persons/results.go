@@ -0,0 +1,137 @@
+package persons
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/alukart32/effective-mobile-test-task/internal/person/model"
+	"github.com/alukart32/effective-mobile-test-task/internal/pkg/reqmeta"
+	"github.com/alukart32/effective-mobile-test-task/internal/pkg/zerologx"
+	"github.com/redis/go-redis/v9"
+)
+
+// _generationKey is the counter of the purges of all the tenants persons.
+// The cached results are keyed by it and the tenant generation, so the write
+// makes all of the tenant results unreachable and they expire by the TTL.
+const _generationKey = "persons:generation"
+
+// tenantGenerationKey is the counter of the tenant persons writes.
+func tenantGenerationKey(tenant string) string {
+	return tenantPrefix(tenant) + "persons:generation"
+}
+
+// resultKey returns the key of the tenant query result in the generation.
+// The query is the canonical filter, sort and page.
+func resultKey(tenant, kind, generation, query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return fmt.Sprintf("%spersons:%s:%s:%s", tenantPrefix(tenant), kind, generation, hex.EncodeToString(sum[:16]))
+}
+
+func collectQuery(filter model.PersonFilter, limit, offset int) string {
+	return fmt.Sprintf("%s|sort=id|limit=%d|offset=%d", filter.Key(), limit, offset)
+}
+
+func statsQuery(filter model.PersonFilter, buckets model.AgeBuckets) string {
+	bounds := make([]byte, 0, 4*len(buckets))
+	for i, b := range buckets {
+		if i > 0 {
+			bounds = append(bounds, ',')
+		}
+		bounds = strconv.AppendInt(bounds, int64(b), 10)
+	}
+	return fmt.Sprintf("%s|buckets=%s", filter.Key(), bounds)
+}
+
+// generation returns the current persons generation of the tenant, it's
+// the purges and the tenant writes counters joined, "0.0" before the first write.
+func (s *cachedStorage) generation(ctx context.Context, tenant string) (string, error) {
+	vals, err := s.cache.MGet(ctx, _generationKey, tenantGenerationKey(tenant)).Result()
+	if err != nil {
+		return "", fmt.Errorf("redis: %w", err)
+	}
+	gens := make([]string, len(vals))
+	for i, v := range vals {
+		// The missing counter is nil.
+		gen, _ := v.(string)
+		if len(gen) == 0 {
+			gen = "0"
+		}
+		gens[i] = gen
+	}
+	return strings.Join(gens, "."), nil
+}
+
+// bumpGeneration invalidates the cached results of the ctx tenant after the write.
+func (s *cachedStorage) bumpGeneration(ctx context.Context) error {
+	return s.incrGeneration(ctx, tenantGenerationKey(reqmeta.Tenant(ctx)))
+}
+
+// bumpGlobalGeneration invalidates the cached results of all the tenants
+// after the purge.
+func (s *cachedStorage) bumpGlobalGeneration(ctx context.Context) error {
+	return s.incrGeneration(ctx, _generationKey)
+}
+
+func (s *cachedStorage) incrGeneration(ctx context.Context, key string) error {
+	if s.cfg.ResultTTL <= 0 {
+		return nil
+	}
+	if err := s.cache.Incr(ctx, key).Err(); err != nil {
+		return fmt.Errorf("redis: %w", err)
+	}
+	return nil
+}
+
+// cachedResult returns the ctx tenant query result of the current
+// generation, or reads it with fn and caches it. The generation is read before fn, so
+// the result read before the concurrent write is cached in the old
+// generation. The cache errors are logged and the result is read with fn.
+func cachedResult[T any](
+	ctx context.Context,
+	s *cachedStorage,
+	kind, query string,
+	fn func() (T, error),
+) (T, error) {
+	logger := zerologx.Get().With().Str("kind", kind).Logger()
+
+	tenant := reqmeta.Tenant(ctx)
+	gen, err := s.generation(ctx, tenant)
+	if err != nil {
+		cacheMetrics.errors.Add(1)
+		logger.Err(err).Msg("read persons generation")
+		return fn()
+	}
+	key := resultKey(tenant, kind, gen, query)
+
+	var res T
+	data, err := s.cache.Get(ctx, key).Bytes()
+	if err == nil {
+		if err = json.Unmarshal(data, &res); err == nil {
+			cacheMetrics.resultHits.Add(1)
+			return res, nil
+		}
+	}
+	if !errors.Is(err, redis.Nil) {
+		cacheMetrics.errors.Add(1)
+		logger.Err(err).Msg("read cached result")
+	}
+	cacheMetrics.resultMisses.Add(1)
+
+	if res, err = fn(); err != nil {
+		return res, err
+	}
+	if data, err = json.Marshal(res); err != nil {
+		return res, err
+	}
+	if err = s.cache.Set(ctx, key, data, s.cfg.ResultTTL).Err(); err != nil {
+		cacheMetrics.errors.Add(1)
+		logger.Err(err).Msg("cache result")
+	}
+	return res, nil
+}
@@ -0,0 +1,626 @@
+package persons
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alukart32/effective-mobile-test-task/internal/person/model"
+	"github.com/alukart32/effective-mobile-test-task/internal/pkg/reqmeta"
+	"github.com/mattn/go-sqlite3"
+)
+
+// SQLiteMigrations are the migrations of the SQLite storage in the SQLiteMigrationsDir.
+//
+//go:embed sqlite/*.sql
+var SQLiteMigrations embed.FS
+
+const SQLiteMigrationsDir = "sqlite"
+
+// _sqliteDialect binds the numbered params, the times are stored
+// as the unix nanoseconds.
+var _sqliteDialect = sqlDialect{
+	param: func(n int) string { return "?" + strconv.Itoa(n) },
+	time:  func(t time.Time) any { return t.UnixNano() },
+}
+
+// sqliteDB is the SQLite persons storage with the semantics of pgxDB.
+// It keeps the history, but has no outbox, so no person events are published.
+type sqliteDB struct {
+	db     *sql.DB
+	quotas model.TenantQuotas
+}
+
+func SQLiteStorage(db *sql.DB, quotas model.TenantQuotas) (*sqliteDB, error) {
+	if db == nil {
+		return nil, fmt.Errorf("init persons storage: sqlite db is nil")
+	}
+	return &sqliteDB{db: db, quotas: quotas}, nil
+}
+
+func (s *sqliteDB) Save(ctx context.Context, person model.Person) (err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("sqliteDB.Save: %w", err)
+		}
+	}()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = s.finishTx(tx, err)
+	}()
+
+	if err = s.reserveQuota(ctx, tx, 1); err != nil {
+		return err
+	}
+	person = stampCreated(person, time.Now().UTC())
+	if err = s.insert(ctx, tx, person); err != nil {
+		return err
+	}
+	after := toRecord(person)
+	return s.recordChange(ctx, tx, model.OpCreate, person.Id, nil, &after)
+}
+
+// SaveBatch inserts all the persons or none of them.
+func (s *sqliteDB) SaveBatch(ctx context.Context, persons []model.Person) (err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("sqliteDB.SaveBatch: %w", err)
+		}
+	}()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = s.finishTx(tx, err)
+	}()
+
+	if err = s.reserveQuota(ctx, tx, len(persons)); err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	for _, p := range persons {
+		p = stampCreated(p, now)
+		if err = s.insert(ctx, tx, p); err != nil {
+			return err
+		}
+		after := toRecord(p)
+		if err = s.recordChange(ctx, tx, model.OpCreate, p.Id, nil, &after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// insert inserts the new person, the constraint violations are reported
+// like the postgres storage does.
+func (s *sqliteDB) insert(ctx context.Context, tx *sql.Tx, p model.Person) error {
+	const query = `INSERT INTO
+	persons(id, name, surname, patronymic, nation, gender, age, created_at, updated_at, enriched_at, tenant_id)
+	VALUES(?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9, ?10, ?11)`
+
+	_, err := tx.ExecContext(ctx, query,
+		p.Id,
+		p.Name,
+		p.Surname,
+		p.Patronymic,
+		p.Nation,
+		p.Gender,
+		p.Age,
+		p.CreatedAt.UnixNano(),
+		p.UpdatedAt.UnixNano(),
+		sqliteTime(p.EnrichedAt),
+		reqmeta.Tenant(ctx),
+	)
+
+	var sqliteErr sqlite3.Error
+	if err != nil && errors.As(err, &sqliteErr) {
+		switch sqliteErr.ExtendedCode {
+		case sqlite3.ErrConstraintPrimaryKey, sqlite3.ErrConstraintUnique:
+			err = fmt.Errorf("id unique %w", model.ErrConstraintViolation)
+		case sqlite3.ErrConstraintCheck:
+			err = fmt.Errorf("check %w: %w", model.ErrConstraintViolation, err)
+		}
+	}
+	return err
+}
+
+func (s *sqliteDB) FindById(ctx context.Context, id string, includeDeleted bool) (model.Person, error) {
+	query := `SELECT ` + _personColumns + ` FROM persons WHERE id = ?1 AND tenant_id = ?2`
+	if !includeDeleted {
+		query += ` AND deleted_at IS NULL`
+	}
+	r, err := scanSQLiteRecord(s.db.QueryRowContext(ctx, query, id, reqmeta.Tenant(ctx)))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Person{}, nil
+		}
+		return model.Person{}, fmt.Errorf("sqliteDB.FindById: %w", err)
+	}
+	return r.ToModel(), nil
+}
+
+// Collect returns the filtered persons ordered by id.
+func (s *sqliteDB) Collect(ctx context.Context, filter model.PersonFilter, limit, offset int) (_ []model.Person, err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("sqliteDB.Collect: %w", err)
+		}
+	}()
+
+	var sb strings.Builder
+	sb.WriteString("SELECT " + _personColumns + " FROM persons")
+	args := writeFilter(&sb, _sqliteDialect, reqmeta.Tenant(ctx), filter, nil)
+	sb.WriteString(" ORDER BY id")
+	if limit > 0 || offset > 0 {
+		// SQLite requires LIMIT for OFFSET, the negative one is no limit.
+		if limit <= 0 {
+			limit = -1
+		}
+		args = append(args, limit, max(offset, 0))
+		sb.WriteString(fmt.Sprintf(" LIMIT ?%d OFFSET ?%d", len(args)-1, len(args)))
+	}
+
+	persons := make([]model.Person, 0)
+	err = s.query(ctx, sb.String(), args, func(r nullableRecord) error {
+		persons = append(persons, r.ToModel())
+		return nil
+	})
+	return persons, err
+}
+
+// Export streams the filtered persons ordered by id to fn. The SELECT
+// reads the snapshot, the concurrent writes aren't blocked.
+func (s *sqliteDB) Export(ctx context.Context, filter model.PersonFilter, fn func(model.Person) error) error {
+	var sb strings.Builder
+	sb.WriteString("SELECT " + _personColumns + " FROM persons")
+	args := writeFilter(&sb, _sqliteDialect, reqmeta.Tenant(ctx), filter, nil)
+	sb.WriteString(" ORDER BY id")
+
+	err := s.query(ctx, sb.String(), args, func(r nullableRecord) error {
+		return fn(r.ToModel())
+	})
+	if err != nil {
+		return fmt.Errorf("sqliteDB.Export: %w", err)
+	}
+	return nil
+}
+
+// query passes the person rows of the query to fn.
+func (s *sqliteDB) query(ctx context.Context, query string, args []any, fn func(nullableRecord) error) error {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		r, err := scanSQLiteRecord(rows)
+		if err == nil {
+			err = fn(r)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Stats aggregates the persons grouped by the gender, nation and age
+// in one query, so the stats are of the same snapshot.
+func (s *sqliteDB) Stats(ctx context.Context, filter model.PersonFilter, buckets model.AgeBuckets) (_ model.PersonStats, err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("sqliteDB.Stats: %w", err)
+		}
+	}()
+
+	var sb strings.Builder
+	sb.WriteString("SELECT gender, nation, age, COUNT(*) FROM persons")
+	args := writeFilter(&sb, _sqliteDialect, reqmeta.Tenant(ctx), filter, nil)
+	sb.WriteString(" GROUP BY gender, nation, age")
+
+	rows, err := s.db.QueryContext(ctx, sb.String(), args...)
+	if err != nil {
+		return model.PersonStats{}, err
+	}
+	defer rows.Close()
+
+	b := newStatsBuilder(buckets)
+	for rows.Next() {
+		var (
+			gender, nation sql.NullString
+			age            sql.NullInt64
+			count          int
+		)
+		if err = rows.Scan(&gender, &nation, &age, &count); err != nil {
+			return model.PersonStats{}, err
+		}
+		b.add(nullableRecord{
+			record: record{
+				Gender: gender.String,
+				Nation: nation.String,
+				Age:    int(age.Int64),
+			},
+			nullGender: !gender.Valid,
+			nullNation: !nation.Valid,
+			nullAge:    !age.Valid,
+		}, count)
+	}
+	if err = rows.Err(); err != nil {
+		return model.PersonStats{}, err
+	}
+	return b.stats(), nil
+}
+
+// History returns the person changes from the oldest to the newest one.
+func (s *sqliteDB) History(ctx context.Context, id string, limit, offset int) (_ []model.PersonChange, err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("sqliteDB.History: %w", err)
+		}
+	}()
+
+	query := `SELECT id, person_id, op, before, after, actor, source, correlation_id, changed_at
+	FROM person_history WHERE person_id = ?1 AND tenant_id = ?2 ORDER BY id LIMIT ?3 OFFSET ?4`
+	if limit <= 0 {
+		limit = -1
+	}
+	rows, err := s.db.QueryContext(ctx, query, id, reqmeta.Tenant(ctx), limit, max(offset, 0))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := make([]model.PersonChange, 0)
+	for rows.Next() {
+		var (
+			change        model.PersonChange
+			op            string
+			before, after sql.NullString
+			changedAt     int64
+		)
+		err = rows.Scan(
+			&change.Id,
+			&change.PersonId,
+			&op,
+			&before,
+			&after,
+			&change.Actor,
+			&change.Source,
+			&change.CorrelationId,
+			&changedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		change.Op = model.ChangeOp(op)
+		change.ChangedAt = time.Unix(0, changedAt).UTC()
+		if change.Before, err = unmarshalSnapshot([]byte(before.String)); err != nil {
+			return nil, err
+		}
+		if change.After, err = unmarshalSnapshot([]byte(after.String)); err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+	return changes, rows.Err()
+}
+
+// Update applies the patch to the person and returns its incremented version.
+// The non zero expectedVersion must match the current person version.
+func (s *sqliteDB) Update(ctx context.Context, id string, patch model.PersonPatch, expectedVersion int64) (version int64, err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("sqliteDB.Update: %w", err)
+		}
+	}()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		err = s.finishTx(tx, err)
+	}()
+
+	tenant := reqmeta.Tenant(ctx)
+	const selectQuery = `SELECT ` + _personColumns + ` FROM persons
+	WHERE id = ?1 AND tenant_id = ?2 AND deleted_at IS NULL`
+	before, err := scanSQLiteRecord(tx.QueryRowContext(ctx, selectQuery, id, tenant))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = model.ErrNotFound
+		}
+		return 0, err
+	}
+	if expectedVersion != 0 && before.Version != expectedVersion {
+		return 0, model.ErrVersionMismatch
+	}
+
+	after := before.patched(patch, time.Now().UTC())
+	const query = `UPDATE persons SET name = ?1, surname = ?2, patronymic = ?3,
+	nation = ?4, gender = ?5, age = ?6, overrides = ?7, enriched_at = ?8,
+	version = ?9, updated_at = ?10
+	WHERE id = ?11 AND tenant_id = ?12`
+	_, err = tx.ExecContext(ctx, query,
+		after.Name,
+		after.Surname,
+		after.Patronymic,
+		nullIf(after.Nation, after.nullNation),
+		nullIf(after.Gender, after.nullGender),
+		nullIf(after.Age, after.nullAge),
+		after.Overrides.String(),
+		sqliteTime(after.EnrichedAt),
+		after.Version,
+		after.UpdatedAt.UnixNano(),
+		id,
+		tenant,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return after.Version, s.recordChange(ctx, tx, patch.ChangeOp(), id, &before.record, &after.record)
+}
+
+// Delete marks the person as deleted, it's hidden until restored or purged.
+// The non zero expectedVersion must match the current person version.
+func (s *sqliteDB) Delete(ctx context.Context, id string, expectedVersion int64) (err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("sqliteDB.Delete: %w", err)
+		}
+	}()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = s.finishTx(tx, err)
+	}()
+
+	tenant := reqmeta.Tenant(ctx)
+	const selectQuery = `SELECT ` + _personColumns + ` FROM persons
+	WHERE id = ?1 AND tenant_id = ?2 AND deleted_at IS NULL`
+	before, err := scanSQLiteRecord(tx.QueryRowContext(ctx, selectQuery, id, tenant))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = model.ErrNotFound
+		}
+		return err
+	}
+	if expectedVersion != 0 && before.Version != expectedVersion {
+		return model.ErrVersionMismatch
+	}
+
+	now := time.Now().UTC().UnixNano()
+	const query = `UPDATE persons SET deleted_at = ?1, version = version + 1, updated_at = ?1
+	WHERE id = ?2 AND tenant_id = ?3`
+	if _, err = tx.ExecContext(ctx, query, now, id, tenant); err != nil {
+		return err
+	}
+	return s.recordChange(ctx, tx, model.OpDelete, id, &before.record, nil)
+}
+
+// Restore unmarks the soft deleted person.
+func (s *sqliteDB) Restore(ctx context.Context, id string) (err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("sqliteDB.Restore: %w", err)
+		}
+	}()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = s.finishTx(tx, err)
+	}()
+
+	const query = `UPDATE persons SET deleted_at = NULL, version = version + 1, updated_at = ?1
+	WHERE id = ?2 AND tenant_id = ?3 AND deleted_at IS NOT NULL RETURNING ` + _personColumns
+	after, err := scanSQLiteRecord(tx.QueryRowContext(ctx, query, time.Now().UTC().UnixNano(), id, reqmeta.Tenant(ctx)))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = model.ErrNotFound
+		}
+		return err
+	}
+	return s.recordChange(ctx, tx, model.OpRestore, id, nil, &after.record)
+}
+
+// Purge hard deletes the persons of all the tenants soft deleted before
+// the time. It returns the number of the purged persons.
+func (s *sqliteDB) Purge(ctx context.Context, deletedBefore time.Time) (_ int64, err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("sqliteDB.Purge: %w", err)
+		}
+	}()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		err = s.finishTx(tx, err)
+	}()
+
+	const query = `DELETE FROM persons WHERE deleted_at < ?1 RETURNING ` + _personColumns + `, tenant_id`
+	rows, err := tx.QueryContext(ctx, query, deletedBefore.UnixNano())
+	if err != nil {
+		return 0, err
+	}
+	purged := make(map[string][]record)
+	for rows.Next() {
+		var tenant string
+		r, err := scanSQLiteRecord(rows, &tenant)
+		if err != nil {
+			rows.Close()
+			return 0, err
+		}
+		purged[tenant] = append(purged[tenant], r.record)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return 0, err
+	}
+
+	// The changes are recorded in the tenants of the purged persons.
+	var total int64
+	for tenant, records := range purged {
+		tenantCtx := reqmeta.WithTenant(ctx, tenant)
+		for i := range records {
+			if err = s.recordChange(tenantCtx, tx, model.OpPurge, records[i].Id, &records[i], nil); err != nil {
+				return 0, err
+			}
+		}
+		if err = s.reserveQuota(tenantCtx, tx, -len(records)); err != nil {
+			return 0, err
+		}
+		total += int64(len(records))
+	}
+	return total, nil
+}
+
+// reserveQuota adds n persons to the ctx tenant usage within the tx like
+// pgxDB.reserveQuota does, the negative n releases them.
+func (s *sqliteDB) reserveQuota(ctx context.Context, tx *sql.Tx, n int) error {
+	const query = `INSERT INTO tenant_usage(tenant_id, persons) VALUES(?1, ?2)
+	ON CONFLICT (tenant_id) DO UPDATE SET persons = persons + excluded.persons
+	RETURNING persons`
+
+	tenant := reqmeta.Tenant(ctx)
+	var persons int64
+	if err := tx.QueryRowContext(ctx, query, tenant, n).Scan(&persons); err != nil {
+		return fmt.Errorf("tenant usage: %w", err)
+	}
+	if n > 0 && s.quotas.Exceeded(tenant, persons) {
+		return model.ErrQuotaExceeded
+	}
+	return nil
+}
+
+// recordChange writes the person change to the history within the tx.
+// The tenant, actor, source and correlation ID are taken from ctx.
+func (s *sqliteDB) recordChange(
+	ctx context.Context,
+	tx *sql.Tx,
+	op model.ChangeOp,
+	personId string,
+	before, after *record,
+) error {
+	const query = `INSERT INTO
+	person_history(person_id, op, before, after, actor, source, correlation_id, changed_at, tenant_id)
+	VALUES(?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9)`
+
+	beforeJSON, err := marshalSnapshot(before)
+	if err != nil {
+		return err
+	}
+	afterJSON, err := marshalSnapshot(after)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, query,
+		personId,
+		string(op),
+		nullIf(string(beforeJSON), before == nil),
+		nullIf(string(afterJSON), after == nil),
+		reqmeta.Actor(ctx),
+		reqmeta.Source(ctx),
+		reqmeta.CorrelationID(ctx),
+		time.Now().UTC().UnixNano(),
+		reqmeta.Tenant(ctx),
+	)
+	if err != nil {
+		return fmt.Errorf("record %s change: %w", op, err)
+	}
+	return nil
+}
+
+// finishTx rollbacks transaction if error is provided.
+// If err is nil transaction is committed.
+func (s *sqliteDB) finishTx(tx *sql.Tx, err error) error {
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return errors.Join(err, rollbackErr)
+		}
+		return err
+	}
+	if commitErr := tx.Commit(); commitErr != nil {
+		return fmt.Errorf("commit tx: %w", commitErr)
+	}
+	return nil
+}
+
+// scanSQLiteRecord scans the row of _personColumns followed by the extra columns.
+func scanSQLiteRecord(row interface{ Scan(...any) error }, extra ...any) (nullableRecord, error) {
+	var (
+		r                     nullableRecord
+		nation, gender        sql.NullString
+		age                   sql.NullInt64
+		createdAt, updatedAt  int64
+		enrichedAt, deletedAt sql.NullInt64
+		overrides             string
+	)
+	dest := []any{
+		&r.Id,
+		&r.Name,
+		&r.Surname,
+		&r.Patronymic,
+		&nation,
+		&gender,
+		&age,
+		&r.Version,
+		&createdAt,
+		&updatedAt,
+		&enrichedAt,
+		&overrides,
+		&deletedAt,
+	}
+	err := row.Scan(append(dest, extra...)...)
+	if err != nil {
+		return nullableRecord{}, err
+	}
+	r.Nation, r.nullNation = nation.String, !nation.Valid
+	r.Gender, r.nullGender = gender.String, !gender.Valid
+	r.Age, r.nullAge = int(age.Int64), !age.Valid
+	r.CreatedAt = time.Unix(0, createdAt).UTC()
+	r.UpdatedAt = time.Unix(0, updatedAt).UTC()
+	r.EnrichedAt = fromSQLiteTime(enrichedAt)
+	r.DeletedAt = fromSQLiteTime(deletedAt)
+	err = r.Overrides.ScanRedis(overrides)
+	return r, err
+}
+
+// sqliteTime returns the stored time or nil for the nil one.
+func sqliteTime(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.UnixNano()
+}
+
+func fromSQLiteTime(t sql.NullInt64) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	v := time.Unix(0, t.Int64).UTC()
+	return &v
+}
+
+// nullIf returns nil for the NULL value.
+func nullIf[T any](v T, null bool) any {
+	if null {
+		return nil
+	}
+	return v
+}
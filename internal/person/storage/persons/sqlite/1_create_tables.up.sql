@@ -0,0 +1,35 @@
+-- The times are the unix nanoseconds, so they're compared as stored.
+CREATE TABLE IF NOT EXISTS "persons" (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL CHECK(LENGTH(name) > 0),
+    surname TEXT NOT NULL CHECK(LENGTH(surname) > 0),
+    patronymic TEXT NOT NULL,
+    nation TEXT,
+    gender TEXT,
+    age INTEGER CHECK(age > 0),
+    version INTEGER NOT NULL DEFAULT 1,
+    created_at INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL,
+    enriched_at INTEGER,
+    overrides TEXT NOT NULL DEFAULT '',
+    deleted_at INTEGER
+);
+
+CREATE INDEX IF NOT EXISTS "persons_deleted_at_idx"
+    ON "persons" (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS "persons_created_at_idx" ON "persons" (created_at);
+
+CREATE TABLE IF NOT EXISTS "person_history" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    person_id TEXT NOT NULL,
+    op TEXT NOT NULL,
+    before TEXT,
+    after TEXT,
+    actor TEXT NOT NULL DEFAULT '',
+    source TEXT NOT NULL DEFAULT '',
+    correlation_id TEXT NOT NULL DEFAULT '',
+    changed_at INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS "person_history_person_id_idx"
+    ON "person_history" (person_id, id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "persons_age_idx";
+DROP INDEX IF EXISTS "persons_nation_age_idx";
+DROP INDEX IF EXISTS "persons_gender_nation_age_idx";
@@ -0,0 +1,6 @@
+CREATE INDEX IF NOT EXISTS "persons_gender_nation_age_idx"
+    ON "persons" (gender, nation, age, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS "persons_nation_age_idx"
+    ON "persons" (nation, age, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS "persons_age_idx"
+    ON "persons" (age, id) WHERE deleted_at IS NULL;
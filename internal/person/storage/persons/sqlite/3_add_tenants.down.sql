@@ -0,0 +1,20 @@
+DROP TABLE IF EXISTS "tenant_usage";
+
+-- The indexed column can't be dropped.
+DROP INDEX IF EXISTS "persons_created_at_idx";
+DROP INDEX IF EXISTS "persons_gender_nation_age_idx";
+DROP INDEX IF EXISTS "persons_nation_age_idx";
+DROP INDEX IF EXISTS "persons_age_idx";
+DROP INDEX IF EXISTS "person_history_person_id_idx";
+CREATE INDEX "persons_created_at_idx" ON "persons" (created_at);
+CREATE INDEX "persons_gender_nation_age_idx"
+    ON "persons" (gender, nation, age, id) WHERE deleted_at IS NULL;
+CREATE INDEX "persons_nation_age_idx"
+    ON "persons" (nation, age, id) WHERE deleted_at IS NULL;
+CREATE INDEX "persons_age_idx"
+    ON "persons" (age, id) WHERE deleted_at IS NULL;
+CREATE INDEX "person_history_person_id_idx"
+    ON "person_history" (person_id, id);
+
+ALTER TABLE "person_history" DROP COLUMN tenant_id;
+ALTER TABLE "persons" DROP COLUMN tenant_id;
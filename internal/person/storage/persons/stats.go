@@ -0,0 +1,113 @@
+package persons
+
+import (
+	"sort"
+
+	"github.com/alukart32/effective-mobile-test-task/internal/person/model"
+)
+
+// statsGroup is the stats group key, the NULL one is ordered last.
+type statsGroup struct {
+	value string
+	null  bool
+}
+
+type nationGroup struct {
+	count, ages, ageSum int
+}
+
+// statsBuilder aggregates the person stats the way pgxDB.Stats does
+// for the storages without the SQL aggregates.
+type statsBuilder struct {
+	buckets   model.AgeBuckets
+	total     int
+	genders   map[statsGroup]int
+	nations   map[statsGroup]*nationGroup
+	histogram []model.AgeBucket
+}
+
+func newStatsBuilder(buckets model.AgeBuckets) *statsBuilder {
+	return &statsBuilder{
+		buckets:   buckets,
+		genders:   make(map[statsGroup]int),
+		nations:   make(map[statsGroup]*nationGroup),
+		histogram: model.NewAgeHistogram(buckets),
+	}
+}
+
+// add counts the persons like r count times.
+func (b *statsBuilder) add(r nullableRecord, count int) {
+	b.total += count
+	b.genders[statsGroup{r.Gender, r.nullGender}] += count
+
+	key := statsGroup{r.Nation, r.nullNation}
+	n, ok := b.nations[key]
+	if !ok {
+		n = &nationGroup{}
+		b.nations[key] = n
+	}
+	n.count += count
+	if r.nullAge {
+		return
+	}
+	n.ages += count
+	n.ageSum += r.Age * count
+	if len(b.buckets) > 0 {
+		b.histogram[ageBucket(b.buckets, r.Age)].Count += count
+	}
+}
+
+func (b *statsBuilder) stats() model.PersonStats {
+	stats := model.PersonStats{
+		Total:        b.total,
+		Genders:      make([]model.GenderStats, 0, len(b.genders)),
+		Nations:      make([]model.NationStats, 0, len(b.nations)),
+		AgeHistogram: b.histogram,
+	}
+
+	genders := make([]statsGroup, 0, len(b.genders))
+	for k := range b.genders {
+		genders = append(genders, k)
+	}
+	sortStatsGroups(genders, func(k statsGroup) int { return b.genders[k] })
+	for _, k := range genders {
+		stats.Genders = append(stats.Genders, model.GenderStats{Gender: k.value, Count: b.genders[k]})
+	}
+
+	nations := make([]statsGroup, 0, len(b.nations))
+	for k := range b.nations {
+		nations = append(nations, k)
+	}
+	sortStatsGroups(nations, func(k statsGroup) int { return b.nations[k].count })
+	for _, k := range nations {
+		n := b.nations[k]
+		s := model.NationStats{Nation: k.value, Count: n.count}
+		if n.ages > 0 {
+			s.AvgAge = float64(n.ageSum) / float64(n.ages)
+		}
+		stats.Nations = append(stats.Nations, s)
+	}
+	return stats
+}
+
+// sortStatsGroups orders the groups by the count descending, then by
+// the value with the NULL one last.
+func sortStatsGroups(groups []statsGroup, count func(statsGroup) int) {
+	sort.Slice(groups, func(i, j int) bool {
+		if ci, cj := count(groups[i]), count(groups[j]); ci != cj {
+			return ci > cj
+		}
+		if groups[i].null != groups[j].null {
+			return groups[j].null
+		}
+		return groups[i].value < groups[j].value
+	})
+}
+
+// ageBucket returns the histogram bucket of the age, it's the number of
+// the bounds not above the age like the postgres width_bucket.
+func ageBucket(buckets model.AgeBuckets, age int) int {
+	return sort.Search(len(buckets), func(i int) bool {
+		return buckets[i] > age
+	})
+}
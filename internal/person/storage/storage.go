@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/alukart32/effective-mobile-test-task/internal/person/model"
+	"github.com/alukart32/effective-mobile-test-task/internal/person/storage/persons"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+// Backend is the persons storage backend.
+type Backend string
+
+const (
+	// BackendMemory keeps the persons in process, they're lost on restart.
+	BackendMemory Backend = "memory"
+	// BackendPostgres keeps the persons in postgres.
+	BackendPostgres Backend = "postgres"
+	// BackendPostgresRedis keeps the persons in postgres cached in redis.
+	BackendPostgresRedis Backend = "postgres+redis"
+	// BackendSQLite keeps the persons in the local SQLite file.
+	BackendSQLite Backend = "sqlite"
+)
+
+func ParseBackend(s string) (Backend, error) {
+	switch b := Backend(s); b {
+	case BackendMemory, BackendPostgres, BackendPostgresRedis, BackendSQLite:
+		return b, nil
+	default:
+		return "", fmt.Errorf("unsupported storage backend: %s", s)
+	}
+}
+
+// UsesPostgres reports whether the backend needs the postgres pool.
+func (b Backend) UsesPostgres() bool {
+	return b == BackendPostgres || b == BackendPostgresRedis
+}
+
+// UsesSQLite reports whether the backend needs the SQLite db.
+func (b Backend) UsesSQLite() bool {
+	return b == BackendSQLite
+}
+
+// UsesRedis reports whether the backend needs the redis client.
+func (b Backend) UsesRedis() bool {
+	return b == BackendPostgresRedis
+}
+
+// PersonRepo is the persons storage of every backend.
+type PersonRepo interface {
+	Save(ctx context.Context, person model.Person) error
+	SaveBatch(ctx context.Context, persons []model.Person) error
+	FindById(ctx context.Context, id string, includeDeleted bool) (model.Person, error)
+	Collect(ctx context.Context, filter model.PersonFilter, limit, offset int) ([]model.Person, error)
+	Export(ctx context.Context, filter model.PersonFilter, fn func(model.Person) error) error
+	Stats(ctx context.Context, filter model.PersonFilter, buckets model.AgeBuckets) (model.PersonStats, error)
+	History(ctx context.Context, id string, limit, offset int) ([]model.PersonChange, error)
+	Update(ctx context.Context, id string, patch model.PersonPatch, expectedVersion int64) (int64, error)
+	Delete(ctx context.Context, id string, expectedVersion int64) error
+	Restore(ctx context.Context, id string) error
+	Purge(ctx context.Context, deletedBefore time.Time) (int64, error)
+}
+
+// Config is the persons storage configuration. Postgres, Redis and SQLite
+// are required only by the backends using them.
+type Config struct {
+	Backend  Backend
+	Postgres *pgxpool.Pool
+	Redis    *redis.Client
+	// PostgresConfig configures the postgres backends.
+	PostgresConfig persons.PostgresConfig
+	Cache          persons.CacheConfig
+	// SQLite is the migrated db, see persons.SQLiteMigrations.
+	SQLite *sql.DB
+	// Quotas limit the persons of the tenants of every backend.
+	Quotas model.TenantQuotas
+}
+
+// Persons returns the persons storage of the configured backend.
+func Persons(cfg Config) (PersonRepo, error) {
+	var (
+		repo PersonRepo
+		err  error
+	)
+	cfg.PostgresConfig.Quotas = cfg.Quotas
+	switch cfg.Backend {
+	case BackendMemory:
+		repo, err = persons.MemoryStorage(cfg.Quotas)
+	case BackendPostgres:
+		repo, err = persons.Storage(cfg.Postgres, cfg.PostgresConfig)
+	case BackendPostgresRedis:
+		repo, err = persons.CachedStorage(cfg.Postgres, cfg.PostgresConfig, cfg.Redis, cfg.Cache)
+	case BackendSQLite:
+		repo, err = persons.SQLiteStorage(cfg.SQLite, cfg.Quotas)
+	default:
+		err = fmt.Errorf("init persons storage: unsupported backend: %s", cfg.Backend)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
@@ -0,0 +1,343 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alukart32/effective-mobile-test-task/internal/person/model"
+	"github.com/alukart32/effective-mobile-test-task/internal/pkg/reqmeta"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	_webhookColumns  = "id, url, secret, events, enabled, failures, created_at, disabled_at"
+	_deliveryColumns = "id, webhook_id, event_id, event_type, payload, status, attempts, " +
+		"response_code, last_error, next_attempt_at, created_at, updated_at"
+)
+
+// pgxDB stores the webhooks of the tenants. The webhooks are managed in
+// the ctx tenant, the deliveries of all the tenants are dispatched at once.
+type pgxDB struct {
+	pool *pgxpool.Pool
+}
+
+func Storage(db *pgxpool.Pool) (*pgxDB, error) {
+	if db == nil {
+		return nil, fmt.Errorf("init webhooks storage: postgres pool is nil")
+	}
+	return &pgxDB{pool: db}, nil
+}
+
+func (p *pgxDB) Save(ctx context.Context, w model.Webhook) error {
+	const query = `INSERT INTO webhooks(` + _webhookColumns + `, tenant_id)
+	VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+	_, err := p.pool.Exec(ctx, query,
+		w.Id,
+		w.URL,
+		w.Secret,
+		eventNames(w.Events),
+		w.Enabled,
+		w.Failures,
+		w.CreatedAt,
+		w.DisabledAt,
+		reqmeta.Tenant(ctx),
+	)
+	if err != nil {
+		return fmt.Errorf("pgxDB.Save: %w", err)
+	}
+	return nil
+}
+
+func (p *pgxDB) FindById(ctx context.Context, id string) (model.Webhook, error) {
+	const query = `SELECT ` + _webhookColumns + ` FROM webhooks WHERE id = $1 AND tenant_id = $2`
+
+	rows, _ := p.pool.Query(ctx, query, id, reqmeta.Tenant(ctx))
+	w, err := pgx.CollectOneRow(rows, scanWebhook)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = model.ErrNotFound
+		}
+		return model.Webhook{}, fmt.Errorf("pgxDB.FindById: %w", err)
+	}
+	return w, nil
+}
+
+func (p *pgxDB) List(ctx context.Context, limit, offset int) ([]model.Webhook, error) {
+	const query = `SELECT ` + _webhookColumns + ` FROM webhooks
+	WHERE tenant_id = $3 ORDER BY created_at, id LIMIT $1 OFFSET $2`
+
+	if limit <= 0 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	rows, _ := p.pool.Query(ctx, query, limit, offset, reqmeta.Tenant(ctx))
+	webhooks, err := pgx.CollectRows(rows, scanWebhook)
+	if err != nil {
+		return nil, fmt.Errorf("pgxDB.List: %w", err)
+	}
+	return webhooks, nil
+}
+
+// Delete deletes the webhook with its delivery log.
+func (p *pgxDB) Delete(ctx context.Context, id string) error {
+	tag, err := p.pool.Exec(ctx, `DELETE FROM webhooks WHERE id = $1 AND tenant_id = $2`, id, reqmeta.Tenant(ctx))
+	if err != nil {
+		return fmt.Errorf("pgxDB.Delete: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("pgxDB.Delete: %w", model.ErrNotFound)
+	}
+	return nil
+}
+
+// Enable enables the webhook and resets its failures.
+func (p *pgxDB) Enable(ctx context.Context, id string) error {
+	const query = `UPDATE webhooks SET enabled = true, failures = 0, disabled_at = NULL
+	WHERE id = $1 AND tenant_id = $2`
+
+	tag, err := p.pool.Exec(ctx, query, id, reqmeta.Tenant(ctx))
+	if err != nil {
+		return fmt.Errorf("pgxDB.Enable: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("pgxDB.Enable: %w", model.ErrNotFound)
+	}
+	return nil
+}
+
+// Deliveries returns the delivery log of the webhook, the latest first.
+// The log of the other tenant webhook is empty.
+func (p *pgxDB) Deliveries(ctx context.Context, webhookId string, limit, offset int) ([]model.WebhookDelivery, error) {
+	const query = `SELECT ` + _deliveryColumns + ` FROM webhook_deliveries
+	WHERE webhook_id = (SELECT id FROM webhooks WHERE id = $1 AND tenant_id = $4)
+	ORDER BY id DESC LIMIT $2 OFFSET $3`
+
+	if limit <= 0 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	rows, _ := p.pool.Query(ctx, query, webhookId, limit, offset, reqmeta.Tenant(ctx))
+	deliveries, err := pgx.CollectRows(rows, scanDelivery)
+	if err != nil {
+		return nil, fmt.Errorf("pgxDB.Deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// Enqueue adds the pending deliveries of the events to the enabled webhooks
+// of the event tenant subscribed to them. The event already enqueued to
+// the webhook is skipped.
+func (p *pgxDB) Enqueue(ctx context.Context, msgs []model.OutboxMessage) error {
+	const query = `INSERT INTO webhook_deliveries(webhook_id, event_id, event_type, payload)
+	SELECT id, $1, $2, $3 FROM webhooks WHERE enabled AND $2 = ANY(events) AND tenant_id = $4
+	ON CONFLICT (webhook_id, event_id) DO NOTHING`
+
+	batch := &pgx.Batch{}
+	for _, m := range msgs {
+		batch.Queue(query, m.Id, string(m.Type), m.Payload, m.Tenant)
+	}
+	if err := p.pool.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("pgxDB.Enqueue: %w", err)
+	}
+	return nil
+}
+
+// Claim returns the due pending deliveries of the enabled webhooks and
+// postpones their next attempt until leaseUntil, so the other dispatchers
+// skip them while they are being delivered.
+func (p *pgxDB) Claim(ctx context.Context, limit int, leaseUntil time.Time) ([]model.WebhookDispatch, error) {
+	const query = `WITH due AS (
+		SELECT d.id FROM webhook_deliveries d JOIN webhooks w ON w.id = d.webhook_id
+		WHERE d.status = 'pending' AND d.next_attempt_at <= now() AND w.enabled
+		ORDER BY d.next_attempt_at, d.id
+		LIMIT $1
+		FOR UPDATE OF d SKIP LOCKED
+	)
+	UPDATE webhook_deliveries d SET next_attempt_at = $2
+	FROM due, webhooks w
+	WHERE d.id = due.id AND w.id = d.webhook_id
+	RETURNING d.id, d.webhook_id, d.event_id, d.event_type, d.payload, d.status,
+		d.attempts, d.response_code, d.last_error, d.next_attempt_at, d.created_at,
+		d.updated_at, w.url, w.secret`
+
+	rows, _ := p.pool.Query(ctx, query, limit, leaseUntil)
+	dispatches, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.WebhookDispatch, error) {
+		var (
+			d         model.WebhookDispatch
+			eventType string
+			status    string
+		)
+		err := row.Scan(
+			&d.Delivery.Id,
+			&d.Delivery.WebhookId,
+			&d.Delivery.EventId,
+			&eventType,
+			&d.Delivery.Payload,
+			&status,
+			&d.Delivery.Attempts,
+			&d.Delivery.ResponseCode,
+			&d.Delivery.LastError,
+			&d.Delivery.NextAttemptAt,
+			&d.Delivery.CreatedAt,
+			&d.Delivery.UpdatedAt,
+			&d.Webhook.URL,
+			&d.Webhook.Secret,
+		)
+		d.Delivery.EventType = model.EventType(eventType)
+		d.Delivery.Status = model.DeliveryStatus(status)
+		d.Webhook.Id = d.Delivery.WebhookId
+		d.Webhook.Enabled = true
+		return d, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("pgxDB.Claim: %w", err)
+	}
+	return dispatches, nil
+}
+
+// RecordAttempt logs the delivery attempt. The successful attempt resets
+// the webhook failures, the failed one increments them and disables
+// the webhook after disableAfter consecutive failures. It returns true
+// if the webhook is disabled by the attempt.
+func (p *pgxDB) RecordAttempt(
+	ctx context.Context,
+	attempt model.DeliveryAttempt,
+	disableAfter int,
+) (disabled bool, err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("pgxDB.RecordAttempt: %w", err)
+		}
+	}()
+	tx, err := p.pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.ReadCommitted,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		err = p.finishTx(ctx, tx, err)
+	}()
+
+	status := model.DeliveryDelivered
+	nextAttemptAt := time.Now().UTC()
+	if !attempt.Succeeded() {
+		status = model.DeliveryFailed
+		if attempt.NextAttemptAt != nil {
+			status = model.DeliveryPending
+			nextAttemptAt = *attempt.NextAttemptAt
+		}
+	}
+
+	const deliveryQuery = `UPDATE webhook_deliveries SET status = $2, attempts = attempts + 1,
+	response_code = $3, last_error = $4, next_attempt_at = $5, updated_at = now()
+	WHERE id = $1`
+	_, err = tx.Exec(ctx, deliveryQuery,
+		attempt.DeliveryId,
+		string(status),
+		attempt.ResponseCode,
+		attempt.Err,
+		nextAttemptAt,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	if attempt.Succeeded() {
+		_, err = tx.Exec(ctx, `UPDATE webhooks SET failures = 0 WHERE id = $1 AND failures <> 0`,
+			attempt.WebhookId)
+		return false, err
+	}
+
+	const webhookQuery = `UPDATE webhooks SET failures = failures + 1,
+	enabled = CASE WHEN $2 > 0 AND failures + 1 >= $2 THEN false ELSE enabled END,
+	disabled_at = CASE WHEN enabled AND $2 > 0 AND failures + 1 >= $2 THEN now() ELSE disabled_at END
+	WHERE id = $1
+	RETURNING NOT enabled AND failures = $2`
+	err = tx.QueryRow(ctx, webhookQuery, attempt.WebhookId, disableAfter).Scan(&disabled)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// The webhook is deleted while its event is being delivered.
+		return false, nil
+	}
+	return disabled, err
+}
+
+// finishTx rollbacks transaction if error is provided.
+// If err is nil transaction is committed.
+func (p *pgxDB) finishTx(ctx context.Context, tx pgx.Tx, err error) error {
+	if err != nil {
+		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+			return errors.Join(err, rollbackErr)
+		}
+		return err
+	}
+	if commitErr := tx.Commit(ctx); commitErr != nil {
+		return fmt.Errorf("commit tx: %w", commitErr)
+	}
+	return nil
+}
+
+func scanWebhook(row pgx.CollectableRow) (model.Webhook, error) {
+	var (
+		w      model.Webhook
+		events []string
+	)
+	err := row.Scan(
+		&w.Id,
+		&w.URL,
+		&w.Secret,
+		&events,
+		&w.Enabled,
+		&w.Failures,
+		&w.CreatedAt,
+		&w.DisabledAt,
+	)
+	w.Events = make([]model.EventType, len(events))
+	for i, e := range events {
+		w.Events[i] = model.EventType(e)
+	}
+	return w, err
+}
+
+func scanDelivery(row pgx.CollectableRow) (model.WebhookDelivery, error) {
+	var (
+		d         model.WebhookDelivery
+		eventType string
+		status    string
+	)
+	err := row.Scan(
+		&d.Id,
+		&d.WebhookId,
+		&d.EventId,
+		&eventType,
+		&d.Payload,
+		&status,
+		&d.Attempts,
+		&d.ResponseCode,
+		&d.LastError,
+		&d.NextAttemptAt,
+		&d.CreatedAt,
+		&d.UpdatedAt,
+	)
+	d.EventType = model.EventType(eventType)
+	d.Status = model.DeliveryStatus(status)
+	return d, err
+}
+
+func eventNames(events []model.EventType) []string {
+	names := make([]string, len(events))
+	for i, e := range events {
+		names[i] = string(e)
+	}
+	return names
+}
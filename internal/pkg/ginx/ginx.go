@@ -2,13 +2,13 @@
 package ginx
 
 import (
-	"context"
 	"fmt"
 	"net/http"
 	"os"
 	"sync"
 	"time"
 
+	"github.com/alukart32/effective-mobile-test-task/internal/pkg/reqmeta"
 	"github.com/alukart32/effective-mobile-test-task/internal/pkg/zerologx"
 	"github.com/gin-gonic/gin"
 	"github.com/rs/xid"
@@ -53,10 +53,11 @@ func Get() (*gin.Engine, error) {
 // zerologHandler represents a logger for gin router.
 type zerologHandler struct{}
 
-// CorrID represents the corresponding ID for the request.
-type CorrID string
+// ActorHeader is the request header with the name of the actor, it isn't
+// authenticated and is stored as reqmeta.ClientActor.
+const ActorHeader = "X-Actor"
 
-// Handle adds zerlog context to the request context.
+// Handle adds zerlog context and the request metadata to the request context.
 func (h *zerologHandler) Handle(c *gin.Context) {
 	t := time.Now()
 
@@ -64,7 +65,10 @@ func (h *zerologHandler) Handle(c *gin.Context) {
 	raw := c.Request.URL.RawQuery
 
 	correlationID := xid.New().String()
-	ctx := context.WithValue(c.Request.Context(), CorrID("correlation_id"), correlationID)
+	ctx := reqmeta.WithCorrelationID(c.Request.Context(), correlationID)
+	if actor := c.GetHeader(ActorHeader); len(actor) != 0 {
+		ctx = reqmeta.WithActor(ctx, reqmeta.ClientActor(actor))
+	}
 	c.Request = c.Request.WithContext(ctx)
 
 	l := zerologx.Get().
@@ -1,54 +1,82 @@
 // Package migrate provides postgres db migration.
 //
-// Migration sql files are in /migrations directory.
+// Migration sql files are embedded by the migrations package.
 // Migration engine is a golang-migrate project.
 package migrate
 
 import (
+	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"log"
 	"net/url"
+	"os"
 	"time"
 
 	"github.com/golang-migrate/migrate/v4"
-	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
 )
 
 const (
 	_defaultAttempts = 5
 	_defaultTimeout  = time.Second
 	_driverName      = "postgres"
-	_sslMode         = "?sslmode=disable"
-	_migrations      = "migrations"
+	// _lockID is the advisory lock key held while migrating, so the
+	// replicas started together don't race.
+	_lockID int64 = 0x6d6967726174652e // "migrate."
 )
 
-// Up starts db migrations. If the filepath is empty, the nearest migrations folder will be selected.
-func Up(uri string, filepath string) (err error) {
+// Status is the migration state of the db.
+type Status struct {
+	// Version is the last applied migration, zero if none.
+	Version uint
+	// Dirty is true if the last migration failed, it has to be fixed by
+	// hand and forced.
+	Dirty      bool
+	Migrations []Migration
+}
+
+// Migration is the known migration.
+type Migration struct {
+	Version uint
+	Name    string
+	Applied bool
+}
+
+// Source is the migrations set.
+type Source struct {
+	FS  fs.FS
+	Dir string
+	// Table is the version table of the set, the default one if empty.
+	Table string
+}
+
+type migrator struct {
+	db  *sql.DB
+	src source.Driver
+	m   *migrate.Migrate
+}
+
+// Postgres returns the migrator of the postgres db by the uri. The db is
+// dialed a few times until it's up.
+func Postgres(uri string, set Source) (_ *migrator, err error) {
 	defer func() {
 		if err != nil {
-			err = fmt.Errorf("migrate up: %w", err)
+			err = fmt.Errorf("migrate: %w", err)
 		}
 	}()
 	if len(uri) == 0 {
-		err = fmt.Errorf("empty uri")
-		return
-	}
-	if len(filepath) == 0 {
-		filepath = _migrations
+		return nil, errors.New("empty uri")
 	}
 
-	var (
-		attempts = _defaultAttempts
-		path     = filepath
-		m        *migrate.Migrate
-	)
-
 	u, err := url.Parse(uri)
 	if err != nil {
-		err = fmt.Errorf("invalid uri: %q", uri)
-		return
+		return nil, fmt.Errorf("invalid uri: %q", uri)
 	}
 	queryValues := u.Query()
 	if !queryValues.Has("sslmode") {
@@ -56,25 +84,143 @@ func Up(uri string, filepath string) (err error) {
 	}
 	u.RawQuery = queryValues.Encode()
 
-	for attempts > 0 {
-		m, err = migrate.New("file://"+path, u.String())
-		if err == nil {
+	db, err := sql.Open(_driverName, u.String())
+	if err != nil {
+		return nil, err
+	}
+	for attempts := _defaultAttempts; ; attempts-- {
+		if err = db.Ping(); err == nil {
 			break
 		}
-
-		log.Printf("migrate: trying to connect, attempts left: %d", attempts)
+		if attempts == 1 {
+			db.Close()
+			return nil, err
+		}
+		log.Printf("migrate: trying to connect, attempts left: %d", attempts-1)
 		time.Sleep(_defaultTimeout)
-		attempts--
 	}
-	if m == nil {
-		return fmt.Errorf("unable to create migration")
+
+	src, err := iofs.New(set.FS, set.Dir)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	driver, err := postgres.WithInstance(db, &postgres.Config{MigrationsTable: set.Table})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	m, err := migrate.NewWithInstance("iofs", src, _driverName, driver)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &migrator{db: db, src: src, m: m}, nil
+}
+
+// Up applies all the up migrations.
+func (m *migrator) Up(ctx context.Context) error {
+	err := m.locked(ctx, func() error {
+		return ignoreNoChange(m.m.Up())
+	})
+	if err != nil {
+		return fmt.Errorf("migrate up: %w", err)
+	}
+	return nil
+}
+
+// Down applies the steps down migrations, all of them if steps is zero.
+func (m *migrator) Down(ctx context.Context, steps int) error {
+	if steps < 0 {
+		return fmt.Errorf("migrate down: negative steps: %d", steps)
+	}
+	err := m.locked(ctx, func() error {
+		if steps == 0 {
+			return ignoreNoChange(m.m.Down())
+		}
+		return ignoreNoChange(m.m.Steps(-steps))
+	})
+	if err != nil {
+		return fmt.Errorf("migrate down: %w", err)
+	}
+	return nil
+}
+
+// Force sets the version and clears the dirty flag without migrating,
+// version -1 means no migrations are applied.
+func (m *migrator) Force(ctx context.Context, version int) error {
+	err := m.locked(ctx, func() error {
+		return m.m.Force(version)
+	})
+	if err != nil {
+		return fmt.Errorf("migrate force: %w", err)
 	}
+	return nil
+}
+
+// Status returns the db version and the known migrations.
+func (m *migrator) Status() (Status, error) {
+	var status Status
 
-	err = m.Up()
-	defer m.Close()
-	if err != nil && errors.Is(err, migrate.ErrNoChange) {
-		err = nil
+	version, dirty, err := m.m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return Status{}, fmt.Errorf("migrate status: %w", err)
 	}
+	status.Version, status.Dirty = version, dirty
 
+	v, err := m.src.First()
+	for err == nil {
+		var (
+			r    io.ReadCloser
+			name string
+		)
+		r, name, err = m.src.ReadUp(v)
+		if err != nil {
+			return Status{}, fmt.Errorf("migrate status: %w", err)
+		}
+		r.Close()
+		status.Migrations = append(status.Migrations, Migration{
+			Version: v,
+			Name:    name,
+			Applied: status.Version >= v,
+		})
+		v, err = m.src.Next(v)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return Status{}, fmt.Errorf("migrate status: %w", err)
+	}
+	return status, nil
+}
+
+// Close closes the source and the db.
+func (m *migrator) Close() error {
+	srcErr, dbErr := m.m.Close()
+	return errors.Join(srcErr, dbErr)
+}
+
+// locked runs fn holding the advisory lock on a dedicated connection.
+func (m *migrator) locked(ctx context.Context, fn func() error) (err error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err = conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, _lockID); err != nil {
+		return fmt.Errorf("acquire lock: %w", err)
+	}
+	defer func() {
+		_, unlockErr := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, _lockID)
+		if unlockErr != nil {
+			err = errors.Join(err, fmt.Errorf("release lock: %w", unlockErr))
+		}
+	}()
+	return fn()
+}
+
+func ignoreNoChange(err error) error {
+	if errors.Is(err, migrate.ErrNoChange) {
+		return nil
+	}
 	return err
 }
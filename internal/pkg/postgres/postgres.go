@@ -4,71 +4,90 @@ package postgres
 import (
 	"context"
 	"errors"
-	"sync"
+	"fmt"
+	"strconv"
 	"time"
 
-	"github.com/caarlos0/env/v8"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-var (
-	pool *pgxpool.Pool
-	once sync.Once
-)
-
-// Get returns an instance of pgxpool.Pool.
-func Get(dsn string) (*pgxpool.Pool, error) {
-	var err error
-
-	once.Do(func() {
-		var cfg *pgxpool.Config
-
-		cfg, err = conf(dsn)
-		if err != nil {
-			return
-		}
+// Options are the pool settings, the zero ones keep the pgx defaults.
+type Options struct {
+	MinConns int32
+	MaxConns int32
+	// MaxConnIdleTime is how long the idle connection is kept.
+	MaxConnIdleTime time.Duration
+	// MaxConnLifetime is how long the connection is used before it's closed.
+	MaxConnLifetime time.Duration
+	// StatementTimeout aborts the longer statements on the server.
+	StatementTimeout time.Duration
+	// ApplicationName is the application_name shown in pg_stat_activity.
+	ApplicationName string
+	// PingTimeout is the timeout of the new pool ping, zero skips the ping
+	// and the connections are established on demand.
+	PingTimeout time.Duration
+	// Tracer traces the queries of every connection.
+	Tracer pgx.QueryTracer
+}
 
-		pool, err = pgxpool.NewWithConfig(context.Background(), cfg)
-		if err != nil {
-			return
-		}
+// New returns a new pgxpool.Pool of the dsn.
+func New(ctx context.Context, dsn string, opts Options) (*pgxpool.Pool, error) {
+	cfg, err := conf(dsn, opts)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: %w", err)
+	}
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: %w", err)
+	}
 
-		// Ping a new pool.
-		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	if opts.PingTimeout > 0 {
+		ctx, cancel := context.WithTimeout(ctx, opts.PingTimeout)
 		defer cancel()
-		err = pool.Ping(ctx)
-		if err != nil {
-			return
+		if err = pool.Ping(ctx); err != nil {
+			pool.Close()
+			return nil, fmt.Errorf("postgres: ping: %w", err)
 		}
-	})
-
-	return pool, err
+	}
+	return pool, nil
 }
 
 // conf prepares pgxpool.Config.
-func conf(dsn string) (*pgxpool.Config, error) {
-	opts := env.Options{RequiredIfNoDef: true}
-
-	var cfg poolConf
-	err := env.ParseWithOptions(&cfg, opts)
-	if err != nil {
-		return nil, err
-	}
-
+func conf(dsn string, opts Options) (*pgxpool.Config, error) {
 	if len(dsn) == 0 {
 		return nil, errors.New("DSN is empty")
 	}
+	if opts.MinConns < 0 || opts.MaxConns < 0 || (opts.MaxConns > 0 && opts.MinConns > opts.MaxConns) {
+		return nil, fmt.Errorf("invalid connections limits: min %d, max %d", opts.MinConns, opts.MaxConns)
+	}
 	conf, err := pgxpool.ParseConfig(dsn)
 	if err != nil {
 		return nil, err
 	}
-	conf.MaxConns = int32(cfg.MaxConns)
 
-	return conf, nil
-}
+	if opts.MaxConns > 0 {
+		conf.MaxConns = opts.MaxConns
+	}
+	if opts.MinConns > 0 {
+		conf.MinConns = opts.MinConns
+	}
+	if opts.MaxConnIdleTime > 0 {
+		conf.MaxConnIdleTime = opts.MaxConnIdleTime
+	}
+	if opts.MaxConnLifetime > 0 {
+		conf.MaxConnLifetime = opts.MaxConnLifetime
+	}
 
-// poolConf is the representation of postgres pool settings.
-type poolConf struct {
-	MaxConns    int32         `env:"DATABASE_MAX_CONNS" envDefault:"5"`
-	PingTimeout time.Duration `env:"PING_TIMEOUT" envDefault:"300ms"`
+	params := conf.ConnConfig.RuntimeParams
+	if opts.StatementTimeout > 0 {
+		params["statement_timeout"] = strconv.FormatInt(opts.StatementTimeout.Milliseconds(), 10)
+	}
+	if len(opts.ApplicationName) > 0 {
+		params["application_name"] = opts.ApplicationName
+	}
+	if opts.Tracer != nil {
+		conf.ConnConfig.Tracer = opts.Tracer
+	}
+	return conf, nil
 }
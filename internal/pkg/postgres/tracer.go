@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/alukart32/effective-mobile-test-task/internal/pkg/zerologx"
+	"github.com/jackc/pgx/v5"
+)
+
+type traceQueryKey struct{}
+
+type traceQuery struct {
+	sql   string
+	start time.Time
+}
+
+type slowQueryTracer struct {
+	threshold time.Duration
+}
+
+// SlowQueryTracer returns the tracer logging the queries taking threshold
+// or longer and the failed ones.
+func SlowQueryTracer(threshold time.Duration) *slowQueryTracer {
+	return &slowQueryTracer{threshold: threshold}
+}
+
+func (t *slowQueryTracer) TraceQueryStart(
+	ctx context.Context,
+	_ *pgx.Conn,
+	data pgx.TraceQueryStartData,
+) context.Context {
+	return context.WithValue(ctx, traceQueryKey{}, traceQuery{sql: data.SQL, start: time.Now()})
+}
+
+func (t *slowQueryTracer) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+	q, ok := ctx.Value(traceQueryKey{}).(traceQuery)
+	if !ok {
+		return
+	}
+	elapsed := time.Since(q.start)
+	if data.Err == nil && elapsed < t.threshold {
+		return
+	}
+
+	logger := zerologx.Get()
+	event := logger.Warn()
+	if data.Err != nil {
+		event = logger.Debug().Err(data.Err)
+	}
+	event.
+		Str("sql", q.sql).
+		Dur("elapsed", elapsed).
+		Str("host", conn.Config().Host).
+		Msg("postgres query")
+}
@@ -0,0 +1,69 @@
+// Package reqmeta carries the request metadata through context.Context.
+package reqmeta
+
+import "context"
+
+type ctxKey int
+
+const (
+	actorKey ctxKey = iota
+	sourceKey
+	correlationIDKey
+	tenantKey
+)
+
+// DefaultTenant is the tenant of the requests without one.
+const DefaultTenant = "default"
+
+// WithActor returns a copy of ctx with the actor who makes the request.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// ClientActor returns the actor named by the client, it's marked as
+// asserted by the client and not authenticated.
+func ClientActor(name string) string {
+	return "client:" + name
+}
+
+// Actor returns the request actor or an empty string.
+func Actor(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey).(string)
+	return actor
+}
+
+// WithSource returns a copy of ctx with the port the request came from.
+func WithSource(ctx context.Context, source string) context.Context {
+	return context.WithValue(ctx, sourceKey, source)
+}
+
+// Source returns the request source port or an empty string.
+func Source(ctx context.Context) string {
+	source, _ := ctx.Value(sourceKey).(string)
+	return source
+}
+
+// WithCorrelationID returns a copy of ctx with the request correlation ID.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey, id)
+}
+
+// CorrelationID returns the request correlation ID or an empty string.
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey).(string)
+	return id
+}
+
+// WithTenant returns a copy of ctx with the tenant the request is scoped to.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey, tenant)
+}
+
+// Tenant returns the request tenant or DefaultTenant.
+func Tenant(ctx context.Context) string {
+	tenant, _ := ctx.Value(tenantKey).(string)
+	if len(tenant) == 0 {
+		return DefaultTenant
+	}
+	return tenant
+}
@@ -0,0 +1,75 @@
+// Package sqlite provides the SQLite database of the single node deployment.
+//
+// Migration engine is a golang-migrate project.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/sqlite3"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const (
+	_driverName     = "sqlite3"
+	_busyTimeout    = 5 * time.Second
+	_defaultTimeout = 10 * time.Second
+)
+
+// Open opens the SQLite database file, it's created if missing.
+//
+// The transactions take the write lock at once, the concurrent ones wait
+// for it up to the busy timeout. The readers don't wait for the writer.
+func Open(path string) (*sql.DB, error) {
+	if len(path) == 0 {
+		return nil, errors.New("sqlite: path is empty")
+	}
+	dsn := fmt.Sprintf("file:%s?_journal_mode=WAL&_busy_timeout=%d&_foreign_keys=on&_txlock=immediate",
+		path, _busyTimeout.Milliseconds())
+	db, err := sql.Open(_driverName, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), _defaultTimeout)
+	defer cancel()
+	if err = db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("sqlite: %w", err)
+	}
+	return db, nil
+}
+
+// Migrate applies the up migrations from the dir of fsys.
+func Migrate(db *sql.DB, fsys fs.FS, dir string) (err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("sqlite migrate up: %w", err)
+		}
+	}()
+
+	src, err := iofs.New(fsys, dir)
+	if err != nil {
+		return err
+	}
+	driver, err := sqlite3.WithInstance(db, &sqlite3.Config{})
+	if err != nil {
+		return err
+	}
+	// The migrate instance isn't closed, it would close the db.
+	m, err := migrate.NewWithInstance("iofs", src, _driverName, driver)
+	if err != nil {
+		return err
+	}
+	if err = m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+	return nil
+}
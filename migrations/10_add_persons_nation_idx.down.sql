@@ -0,0 +1 @@
+DROP INDEX CONCURRENTLY IF EXISTS "persons_nation_age_idx";
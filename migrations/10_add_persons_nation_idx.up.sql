@@ -0,0 +1,3 @@
+-- The filter of the not deleted persons by nation, optionally by age.
+CREATE INDEX CONCURRENTLY IF NOT EXISTS "persons_nation_age_idx"
+    ON "persons" (nation, age, id) WHERE deleted_at IS NULL;
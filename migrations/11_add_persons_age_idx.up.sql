@@ -0,0 +1,3 @@
+-- The filter of the not deleted persons by age range.
+CREATE INDEX CONCURRENTLY IF NOT EXISTS "persons_age_idx"
+    ON "persons" (age, id) WHERE deleted_at IS NULL;
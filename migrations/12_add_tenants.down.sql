@@ -0,0 +1,21 @@
+DROP TABLE IF EXISTS "tenant_usage";
+
+DROP INDEX IF EXISTS "persons_created_at_idx";
+DROP INDEX IF EXISTS "persons_gender_nation_age_idx";
+DROP INDEX IF EXISTS "persons_nation_age_idx";
+DROP INDEX IF EXISTS "persons_age_idx";
+DROP INDEX IF EXISTS "person_history_person_id_idx";
+CREATE INDEX "persons_created_at_idx" ON "persons" (created_at);
+CREATE INDEX "persons_gender_nation_age_idx"
+    ON "persons" (gender, nation, age, id) WHERE deleted_at IS NULL;
+CREATE INDEX "persons_nation_age_idx"
+    ON "persons" (nation, age, id) WHERE deleted_at IS NULL;
+CREATE INDEX "persons_age_idx"
+    ON "persons" (age, id) WHERE deleted_at IS NULL;
+CREATE INDEX "person_history_person_id_idx"
+    ON "person_history" (person_id, id);
+
+ALTER TABLE "webhooks" DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE "outbox" DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE "person_history" DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE "persons" DROP COLUMN IF EXISTS tenant_id;
@@ -0,0 +1,35 @@
+-- The existing rows belong to the default tenant. The constant default
+-- doesn't rewrite the tables.
+ALTER TABLE "persons" ADD COLUMN IF NOT EXISTS tenant_id VARCHAR NOT NULL DEFAULT 'default';
+ALTER TABLE "person_history" ADD COLUMN IF NOT EXISTS tenant_id VARCHAR NOT NULL DEFAULT 'default';
+ALTER TABLE "outbox" ADD COLUMN IF NOT EXISTS tenant_id VARCHAR NOT NULL DEFAULT 'default';
+ALTER TABLE "webhooks" ADD COLUMN IF NOT EXISTS tenant_id VARCHAR NOT NULL DEFAULT 'default';
+
+-- Every query filters the tenant, so the filter indexes lead by it. They're
+-- rebuilt in the migration, not concurrently, the writes wait for it. The
+-- hash partitioning set creates the same indexes.
+DROP INDEX IF EXISTS "persons_created_at_idx";
+DROP INDEX IF EXISTS "persons_gender_nation_age_idx";
+DROP INDEX IF EXISTS "persons_nation_age_idx";
+DROP INDEX IF EXISTS "persons_age_idx";
+DROP INDEX IF EXISTS "person_history_person_id_idx";
+CREATE INDEX "persons_created_at_idx" ON "persons" (tenant_id, created_at);
+CREATE INDEX "persons_gender_nation_age_idx"
+    ON "persons" (tenant_id, gender, nation, age, id) WHERE deleted_at IS NULL;
+CREATE INDEX "persons_nation_age_idx"
+    ON "persons" (tenant_id, nation, age, id) WHERE deleted_at IS NULL;
+CREATE INDEX "persons_age_idx"
+    ON "persons" (tenant_id, age, id) WHERE deleted_at IS NULL;
+CREATE INDEX "person_history_person_id_idx"
+    ON "person_history" (tenant_id, person_id, id);
+
+-- tenant_usage counts the stored persons of the tenants for the quotas,
+-- the soft deleted ones count until purged.
+CREATE TABLE IF NOT EXISTS "tenant_usage" (
+    tenant_id VARCHAR PRIMARY KEY,
+    persons BIGINT NOT NULL DEFAULT 0
+);
+
+INSERT INTO "tenant_usage" (tenant_id, persons)
+    SELECT tenant_id, COUNT(*) FROM "persons" GROUP BY tenant_id
+    ON CONFLICT (tenant_id) DO UPDATE SET persons = EXCLUDED.persons;
@@ -1 +1 @@
-DROP TABLE IF EXISTS "persons";
\ No newline at end of file
+DROP TABLE IF EXISTS "persons";
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS "person_history" (
+    id BIGSERIAL PRIMARY KEY,
+    person_id uuid NOT NULL,
+    op VARCHAR NOT NULL,
+    before JSONB,
+    after JSONB,
+    actor VARCHAR NOT NULL DEFAULT '',
+    source VARCHAR NOT NULL DEFAULT '',
+    correlation_id VARCHAR NOT NULL DEFAULT '',
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS "person_history_person_id_idx"
+    ON "person_history" (person_id, id);
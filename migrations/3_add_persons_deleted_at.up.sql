@@ -0,0 +1,4 @@
+ALTER TABLE "persons" ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS "persons_deleted_at_idx"
+    ON "persons" (deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS "persons_created_at_idx";
+ALTER TABLE "persons"
+    DROP COLUMN IF EXISTS enriched_at,
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS created_at;
@@ -0,0 +1,6 @@
+ALTER TABLE "persons"
+    ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ADD COLUMN IF NOT EXISTS enriched_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS "persons_created_at_idx" ON "persons" (created_at);
@@ -0,0 +1 @@
+ALTER TABLE "persons" DROP COLUMN IF EXISTS overrides;
@@ -0,0 +1 @@
+ALTER TABLE "persons" ADD COLUMN IF NOT EXISTS overrides TEXT[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS "outbox" (
+    id BIGSERIAL PRIMARY KEY,
+    aggregate_id uuid NOT NULL,
+    event_type VARCHAR NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    published_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS "outbox_unpublished_idx"
+    ON "outbox" (id) WHERE published_at IS NULL;
+CREATE INDEX IF NOT EXISTS "outbox_published_at_idx"
+    ON "outbox" (published_at);
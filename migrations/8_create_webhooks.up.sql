@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS "webhooks" (
+    id uuid PRIMARY KEY,
+    url VARCHAR NOT NULL,
+    secret VARCHAR NOT NULL,
+    events TEXT[] NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    failures INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    disabled_at TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS "webhook_deliveries" (
+    id BIGSERIAL PRIMARY KEY,
+    webhook_id uuid NOT NULL REFERENCES "webhooks" (id) ON DELETE CASCADE,
+    event_id BIGINT NOT NULL,
+    event_type VARCHAR NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    response_code INTEGER NOT NULL DEFAULT 0,
+    last_error VARCHAR NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (webhook_id, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS "webhook_deliveries_pending_idx"
+    ON "webhook_deliveries" (next_attempt_at, id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS "webhook_deliveries_webhook_id_idx"
+    ON "webhook_deliveries" (webhook_id, id);
@@ -0,0 +1,5 @@
+-- The filter of the not deleted persons by gender, optionally by nation
+-- and age, ordered by id. Each index is created concurrently in its own
+-- migration, it can't be created in a transaction.
+CREATE INDEX CONCURRENTLY IF NOT EXISTS "persons_gender_nation_age_idx"
+    ON "persons" (gender, nation, age, id) WHERE deleted_at IS NULL;
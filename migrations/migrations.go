@@ -0,0 +1,18 @@
+// Package migrations embeds the postgres migrations.
+package migrations
+
+import "embed"
+
+// FS holds the up and down migrations of the postgres schema in its root
+// and the optional sets in the subdirs.
+//
+//go:embed *.sql partitioning/hash/*.sql
+var FS embed.FS
+
+const (
+	// Dir is the schema migrations dir of FS.
+	Dir = "."
+	// HashPartitioningDir is the optional set partitioning persons by the
+	// hash of id. It's applied after the schema migrations.
+	HashPartitioningDir = "partitioning/hash"
+)
@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"io/fs"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFS_DownMigrations(t *testing.T) {
+	var ups []string
+	for _, dir := range []string{Dir, HashPartitioningDir} {
+		dirUps, err := fs.Glob(FS, path.Join(dir, "*.up.sql"))
+		require.NoError(t, err)
+		require.NotEmpty(t, dirUps, dir)
+		ups = append(ups, dirUps...)
+	}
+
+	for _, up := range ups {
+		down := strings.TrimSuffix(up, ".up.sql") + ".down.sql"
+		t.Run(down, func(t *testing.T) {
+			b, err := fs.ReadFile(FS, down)
+			require.NoError(t, err, "up migration without the down one")
+			assert.NotEmpty(t, strings.TrimSpace(string(b)), "empty down migration")
+		})
+	}
+}
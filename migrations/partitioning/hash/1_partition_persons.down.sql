@@ -0,0 +1,20 @@
+LOCK TABLE "persons" IN EXCLUSIVE MODE;
+
+CREATE TABLE "persons_plain"
+    (LIKE "persons" INCLUDING DEFAULTS INCLUDING CONSTRAINTS);
+ALTER TABLE "persons_plain" ADD PRIMARY KEY (id);
+
+INSERT INTO "persons_plain" SELECT * FROM "persons";
+DROP TABLE "persons";
+ALTER TABLE "persons_plain" RENAME TO "persons";
+ALTER TABLE "persons" RENAME CONSTRAINT "persons_plain_pkey" TO "persons_pkey";
+
+CREATE INDEX "persons_deleted_at_idx"
+    ON "persons" (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX "persons_created_at_idx" ON "persons" (tenant_id, created_at);
+CREATE INDEX "persons_gender_nation_age_idx"
+    ON "persons" (tenant_id, gender, nation, age, id) WHERE deleted_at IS NULL;
+CREATE INDEX "persons_nation_age_idx"
+    ON "persons" (tenant_id, nation, age, id) WHERE deleted_at IS NULL;
+CREATE INDEX "persons_age_idx"
+    ON "persons" (tenant_id, age, id) WHERE deleted_at IS NULL;
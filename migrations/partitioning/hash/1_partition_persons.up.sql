@@ -0,0 +1,42 @@
+-- Partitions persons by the hash of id into 8 partitions. The table is
+-- copied in one transaction, the writes wait for it. It's applied after
+-- the schema migrations, the later ones must not create the persons
+-- indexes concurrently.
+LOCK TABLE "persons" IN EXCLUSIVE MODE;
+
+CREATE TABLE "persons_partitioned"
+    (LIKE "persons" INCLUDING DEFAULTS INCLUDING CONSTRAINTS)
+    PARTITION BY HASH (id);
+ALTER TABLE "persons_partitioned" ADD PRIMARY KEY (id);
+
+CREATE TABLE "persons_p0" PARTITION OF "persons_partitioned"
+    FOR VALUES WITH (MODULUS 8, REMAINDER 0);
+CREATE TABLE "persons_p1" PARTITION OF "persons_partitioned"
+    FOR VALUES WITH (MODULUS 8, REMAINDER 1);
+CREATE TABLE "persons_p2" PARTITION OF "persons_partitioned"
+    FOR VALUES WITH (MODULUS 8, REMAINDER 2);
+CREATE TABLE "persons_p3" PARTITION OF "persons_partitioned"
+    FOR VALUES WITH (MODULUS 8, REMAINDER 3);
+CREATE TABLE "persons_p4" PARTITION OF "persons_partitioned"
+    FOR VALUES WITH (MODULUS 8, REMAINDER 4);
+CREATE TABLE "persons_p5" PARTITION OF "persons_partitioned"
+    FOR VALUES WITH (MODULUS 8, REMAINDER 5);
+CREATE TABLE "persons_p6" PARTITION OF "persons_partitioned"
+    FOR VALUES WITH (MODULUS 8, REMAINDER 6);
+CREATE TABLE "persons_p7" PARTITION OF "persons_partitioned"
+    FOR VALUES WITH (MODULUS 8, REMAINDER 7);
+
+INSERT INTO "persons_partitioned" SELECT * FROM "persons";
+DROP TABLE "persons";
+ALTER TABLE "persons_partitioned" RENAME TO "persons";
+ALTER TABLE "persons" RENAME CONSTRAINT "persons_partitioned_pkey" TO "persons_pkey";
+
+CREATE INDEX "persons_deleted_at_idx"
+    ON "persons" (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX "persons_created_at_idx" ON "persons" (tenant_id, created_at);
+CREATE INDEX "persons_gender_nation_age_idx"
+    ON "persons" (tenant_id, gender, nation, age, id) WHERE deleted_at IS NULL;
+CREATE INDEX "persons_nation_age_idx"
+    ON "persons" (tenant_id, nation, age, id) WHERE deleted_at IS NULL;
+CREATE INDEX "persons_age_idx"
+    ON "persons" (tenant_id, age, id) WHERE deleted_at IS NULL;